@@ -8,6 +8,16 @@ import (
 	"pandora-pay/cryptography/crypto"
 	"pandora-pay/cryptography/crypto/balance_decryptor"
 	"pandora-pay/helpers/generics"
+	"pandora-pay/helpers/metrics"
+)
+
+var (
+	metricRequests          = metrics.NewCounter("pandora_balance_decryptor_requests_total", "Balances requested to be decrypted")
+	metricPreviousValueHits = metrics.NewCounter("pandora_balance_decryptor_previous_value_hits_total", "Balances decrypted instantly using the previous value")
+	metricQueue             = metrics.NewGauge("pandora_balance_decryptor_queue", "Balances waiting to be decrypted by a worker")
+	metricWorking           = metrics.NewGauge("pandora_balance_decryptor_working", "Balances being decrypted by workers")
+	metricFailed            = metrics.NewCounter("pandora_balance_decryptor_failed_total", "Balances that could not be decrypted")
+	metricDuration          = metrics.NewHistogram("pandora_balance_decryptor_duration_seconds", "Time spent by a worker decrypting a balance", metrics.DURATION_BUCKETS)
 )
 
 type AddressBalanceDecryptor struct {
@@ -24,6 +34,8 @@ func (decryptor *AddressBalanceDecryptor) DecryptBalance(decryptionName string,
 		return 0, nil
 	}
 
+	metricRequests.Inc()
+
 	previousValue := uint64(0)
 	if useNewPreviousValue {
 		previousValue = newPreviousValue
@@ -38,11 +50,13 @@ func (decryptor *AddressBalanceDecryptor) DecryptBalance(decryptionName string,
 
 	balancePoint := new(bn256.G1).Add(balance.Left, new(bn256.G1).Neg(new(bn256.G1).ScalarMult(balance.Right, new(crypto.BNRed).SetBytes(privateKey).BigInt())))
 	if balance_decryptor.BalanceDecryptor.TryDecryptBalance(balancePoint, previousValue) {
+		metricPreviousValueHits.Inc()
 		return previousValue, nil
 	}
 
 	foundWork, loaded := decryptor.all.LoadOrStore(string(publicKey)+"_"+string(encryptedBalance), &addressBalanceDecryptorWork{balancePoint, previousValue, make(chan struct{}), ADDRESS_BALANCE_DECRYPTED_INIT, 0, nil, ctx, statusCallback})
 	if !loaded {
+		metricQueue.Add(1)
 		decryptor.newWorkCn <- foundWork
 	}
 
@@ -2,6 +2,7 @@ package address_balance_decryptor
 
 import (
 	"pandora-pay/gui"
+	"pandora-pay/gui/gui_logger"
 	"pandora-pay/helpers/msgpack"
 	"pandora-pay/store"
 	"pandora-pay/store/store_db/store_db_interface"
@@ -54,9 +55,9 @@ func (decryptor *AddressBalanceDecryptor) saveToStore() {
 			writer.Put("map", bytes)
 			return
 		}); err != nil {
-			gui.GUI.Error("Error storing Address Balance Decryptor", err)
+			gui.GUI.Error(gui_logger.SUBSYSTEM_WALLET, "Error storing Address Balance Decryptor", err)
 		}
 
-		gui.GUI.Log("AddressBalanceDecryptor saveToStore ", len(data))
+		gui.GUI.Log(gui_logger.SUBSYSTEM_WALLET, "AddressBalanceDecryptor saveToStore ", len(data))
 	}
 }
@@ -19,10 +19,18 @@ func (worker *AddressBalanceDecryptorWorker) run() {
 
 	for {
 		foundWork, _ := <-worker.newWorkCn
+		metricQueue.Add(-1)
+		metricWorking.Add(1)
 
 		foundWork.result = &addressBalanceDecryptorWorkResult{}
 
+		start := time.Now()
 		foundWork.result.decryptedBalance, foundWork.result.err = worker.processWork(foundWork)
+		metricDuration.ObserveDuration(start)
+		metricWorking.Add(-1)
+		if foundWork.result.err != nil {
+			metricFailed.Inc()
+		}
 
 		foundWork.time = time.Now().Unix()
 		atomic.StoreInt32(&foundWork.status, ADDRESS_BALANCE_DECRYPTED_PROCESSED)
@@ -14,6 +14,7 @@ import (
 var (
 	Settings                *settings.Settings
 	Wallet                  *wallet.Wallet
+	Wallets                 *wallet.Wallets
 	Forging                 *forging.Forging
 	Mempool                 *mempool.Mempool
 	AddressBalanceDecryptor *address_balance_decryptor.AddressBalanceDecryptor
@@ -25,5 +26,9 @@ func Close() {
 	gui.GUI.Close()
 	Forging.Close()
 	Chain.Close()
-	Wallet.Close()
+	if Wallets != nil {
+		Wallets.Close()
+	} else {
+		Wallet.Close()
+	}
 }
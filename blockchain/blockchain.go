@@ -22,6 +22,7 @@ import (
 	"pandora-pay/config/config_coins"
 	"pandora-pay/config/config_stake"
 	"pandora-pay/gui"
+	"pandora-pay/gui/gui_logger"
 	"pandora-pay/helpers"
 	"pandora-pay/helpers/generics"
 	"pandora-pay/helpers/multicast"
@@ -83,6 +84,8 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 	chain.mutex.Lock()
 	defer chain.mutex.Unlock()
 
+	defer metricAddBlocksDuration.ObserveDuration(time.Now())
+
 	chainData := chain.GetChainData()
 
 	if calledByForging && blocksComplete[len(blocksComplete)-1].Height == chainData.Height-1 && chainData.ConsecutiveSelfForged > 0 {
@@ -90,7 +93,7 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 		return
 	}
 
-	gui.GUI.Info("Including blocks " + strconv.FormatUint(blocksComplete[0].Height, 10) + " ... " + strconv.FormatUint(blocksComplete[len(blocksComplete)-1].Height, 10))
+	gui.GUI.Info(gui_logger.SUBSYSTEM_CONSENSUS, "Including blocks "+strconv.FormatUint(blocksComplete[0].Height, 10)+" ... "+strconv.FormatUint(blocksComplete[len(blocksComplete)-1].Height, 10))
 
 	//chain.RLock() is not required because it is guaranteed that no other thread is writing now in the chain
 	var newChainData = &BlockchainData{
@@ -122,6 +125,7 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 
 	removedBlocksHeights := []uint64{}
 	removedBlocksTransactionsCount := uint64(0)
+	var reorgRemovedBlocksHeights []uint64 //all the heights removed by a reorg, removedBlocksHeights gets consumed
 
 	var dataStorage *data_storage.DataStorage
 
@@ -166,6 +170,10 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 			firstBlockComplete := blocksComplete[0]
 			if firstBlockComplete.Block.Height < newChainData.Height {
 
+				if err = checkReorgFinality(newChainData.Height, firstBlockComplete.Block.Height); err != nil {
+					return
+				}
+
 				index := newChainData.Height - 1
 				for {
 
@@ -184,8 +192,10 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 					}
 				}
 
+				reorgRemovedBlocksHeights = append([]uint64{}, removedBlocksHeights...)
+
 				if firstBlockComplete.Block.Height == 0 {
-					gui.GUI.Info("chain.createGenesisBlockchainData called")
+					gui.GUI.Info(gui_logger.SUBSYSTEM_CONSENSUS, "chain.createGenesisBlockchainData called")
 					newChainData = chain.createGenesisBlockchainData()
 					removedBlocksTransactionsCount = 0
 				} else {
@@ -223,6 +233,10 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 						return errors.New("Block Height is not right!")
 					}
 
+					if err = checkBlockCheckpoint(blkComplete.Block.Height, blkComplete.Block.Bloom.Hash); err != nil {
+						return
+					}
+
 					//check existance of a tx with payloads
 					var foundStakingRewardTx *transaction.Transaction
 					for index, tx := range blkComplete.Txs {
@@ -330,6 +344,12 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 						return errors.New("Error saving block complete: " + err.Error())
 					}
 
+					if config.NODE_PROVIDE_ANALYTICS {
+						if err = saveBlockStats(writer, blkComplete, newChainData.Timestamp, newChainData.Target, dataStorage); err != nil {
+							return errors.New("Error saving block stats: " + err.Error())
+						}
+					}
+
 					if len(removedBlocksHeights) > 0 {
 						removedBlocksHeights = removedBlocksHeights[1:]
 					}
@@ -467,8 +487,10 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 	if err == nil {
 		kernelHash = newChainData.KernelHash
 		chain.ChainData.Store(newChainData)
+		metricBlocksInserted.Add(uint64(len(insertedBlocks)))
 		chain.mempool.ContinueProcessingCn <- mempool.CONTINUE_PROCESSING_NO_ERROR
 	} else {
+		metricAddBlocksErrors.Inc()
 		chain.mempool.ContinueProcessingCn <- mempool.CONTINUE_PROCESSING_ERROR
 	}
 
@@ -486,6 +508,7 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 		update.insertedTxs = insertedTxs
 		update.insertedTxsList = insertedTxsList
 		update.insertedBlocks = insertedBlocks
+		update.removedBlocksHeights = reorgRemovedBlocksHeights
 		update.allTransactionsChanges = allTransactionsChanges
 	}
 
@@ -496,7 +519,7 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 
 func CreateBlockchain(mempool *mempool.Mempool) (*Blockchain, error) {
 
-	gui.GUI.Log("Blockchain init...")
+	gui.GUI.Log(gui_logger.SUBSYSTEM_CONSENSUS, "Blockchain init...")
 
 	chain := &Blockchain{
 		&generics.Value[*BlockchainData]{},
@@ -59,4 +59,6 @@ func (chainData *BlockchainData) updateChainInfo() {
 	gui.GUI.Info2Update("Chain  Hash", base64.StdEncoding.EncodeToString(chainData.Hash))
 	gui.GUI.Info2Update("Chain KHash", base64.StdEncoding.EncodeToString(chainData.KernelHash))
 	gui.GUI.Info2Update("TXs", strconv.FormatUint(chainData.TransactionsCount, 10))
+	metricChainHeight.Set(float64(chainData.Height))
+	metricChainTransactions.Set(float64(chainData.TransactionsCount))
 }
@@ -21,6 +21,7 @@ import (
 	"pandora-pay/config/config_stake"
 	"pandora-pay/cryptography"
 	"pandora-pay/gui"
+	"pandora-pay/gui/gui_logger"
 	"pandora-pay/helpers"
 	"pandora-pay/helpers/advanced_buffers"
 	"pandora-pay/helpers/recovery"
@@ -57,7 +58,7 @@ func (chain *Blockchain) createGenesisBlockchainData() *BlockchainData {
 
 func (chain *Blockchain) initializeNewChain(chainData *BlockchainData, dataStorage *data_storage.DataStorage) (err error) {
 
-	gui.GUI.Info("Initializing New Chain")
+	gui.GUI.Info(gui_logger.SUBSYSTEM_CONSENSUS, "Initializing New Chain")
 
 	supply := uint64(0)
 
@@ -193,7 +194,7 @@ func (chain *Blockchain) createNextBlockForForging(chainData *BlockchainData, ne
 		var err error
 		if chainData.Height == 0 {
 			if blk, err = genesis.CreateNewGenesisBlock(); err != nil {
-				gui.GUI.Error("Error creating next block", err)
+				gui.GUI.Error(gui_logger.SUBSYSTEM_CONSENSUS, "Error creating next block", err)
 				return
 			}
 		} else {
@@ -0,0 +1,49 @@
+package blockchain
+
+import (
+	"bytes"
+	"errors"
+	"pandora-pay/config"
+	"strconv"
+)
+
+//checkReorgFinality rejects the reorgs which remove finalized blocks. chainHeight is the number of blocks and forkHeight is the first removed block
+func checkReorgFinality(chainHeight, forkHeight uint64) error {
+
+	if config.FINALITY_DEPTH > 0 && chainHeight-forkHeight > config.FINALITY_DEPTH {
+		return errors.New("Reorg of " + strconv.FormatUint(chainHeight-forkHeight, 10) + " blocks is deeper than the finality depth")
+	}
+
+	if checkpoint := config.GetLastCheckpoint(chainHeight - 1); checkpoint != nil && forkHeight <= checkpoint.Height {
+		return errors.New("Reorg removes the checkpoint " + strconv.FormatUint(checkpoint.Height, 10))
+	}
+
+	return nil
+}
+
+func checkBlockCheckpoint(height uint64, hash []byte) error {
+	if checkpoint := config.GetCheckpoint(height); checkpoint != nil && !bytes.Equal(checkpoint, hash) {
+		return errors.New("Block " + strconv.FormatUint(height, 10) + " doesn't match the checkpoint")
+	}
+	return nil
+}
+
+//GetFinalizedHeight returns the height of the last block which can't be removed by a reorg anymore
+func (chain *Blockchain) GetFinalizedHeight() (finalized uint64) {
+
+	chainData := chain.GetChainData()
+	if chainData.Height == 0 {
+		return 0
+	}
+
+	tip := chainData.Height - 1
+	if config.FINALITY_DEPTH > 0 && tip >= config.FINALITY_DEPTH {
+		finalized = tip - config.FINALITY_DEPTH
+	}
+
+	if checkpoint := config.GetLastCheckpoint(tip); checkpoint != nil && checkpoint.Height > finalized {
+		finalized = checkpoint.Height
+	}
+
+	return
+}
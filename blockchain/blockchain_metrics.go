@@ -0,0 +1,13 @@
+package blockchain
+
+import (
+	"pandora-pay/helpers/metrics"
+)
+
+var (
+	metricChainHeight       = metrics.NewGauge("pandora_chain_height", "Height of the local chain")
+	metricChainTransactions = metrics.NewGauge("pandora_chain_transactions", "Number of transactions included in the local chain")
+	metricBlocksInserted    = metrics.NewCounter("pandora_blocks_inserted_total", "Blocks inserted in the chain by AddBlocks")
+	metricAddBlocksErrors   = metrics.NewCounter("pandora_add_blocks_errors_total", "AddBlocks calls that failed")
+	metricAddBlocksDuration = metrics.NewHistogram("pandora_add_blocks_duration_seconds", "Time spent processing blocks in AddBlocks", metrics.DURATION_BUCKETS)
+)
@@ -0,0 +1,420 @@
+package blockchain
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"pandora-pay/blockchain/blocks/block/difficulty"
+	"pandora-pay/blockchain/blocks/block_complete"
+	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/blockchain/info"
+	"pandora-pay/blockchain/transactions/transaction/transaction_simple"
+	"pandora-pay/blockchain/transactions/transaction/transaction_type"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/helpers/msgpack"
+	"pandora-pay/store"
+	"pandora-pay/store/min_max_heap"
+	"pandora-pay/store/store_db/store_db_interface"
+	"sort"
+	"strconv"
+)
+
+const (
+	STATS_MAX_RESULTS               = 1000
+	STATS_FEE_LIQUIDITY_MAX_RESULTS = 100
+)
+
+//the analytics index keeps the stats of every block since "stats:firstHeight" and a history for every asset with the entries of the blocks which changed its holders or supply
+
+func getStatsUint(reader store_db_interface.StoreDBTransactionInterface, key string) (uint64, bool, error) {
+	data := reader.Get(key)
+	if data == nil {
+		return 0, false, nil
+	}
+	value, err := strconv.ParseUint(string(data), 10, 64)
+	return value, true, err
+}
+
+func loadBlockStats(reader store_db_interface.StoreDBTransactionInterface, height uint64) (*info.BlockStats, error) {
+	data := reader.Get("stats:block:" + strconv.FormatUint(height, 10))
+	if data == nil {
+		return nil, nil
+	}
+	stats := &info.BlockStats{}
+	if err := msgpack.Unmarshal(data, stats); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func loadAssetStats(reader store_db_interface.StoreDBTransactionInterface, asset []byte, index uint64) (*info.AssetStats, error) {
+	data := reader.Get("stats:asset:" + string(asset) + ":" + strconv.FormatUint(index, 10))
+	if data == nil {
+		return nil, errors.New("Asset stats were not found")
+	}
+	stats := &info.AssetStats{}
+	if err := msgpack.Unmarshal(data, stats); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+//appendAssetStats adds a new entry to the asset history in case its holders or supply changed
+func appendAssetStats(writer store_db_interface.StoreDBTransactionInterface, asset []byte, height, timestamp uint64, dataStorage *data_storage.DataStorage) (bool, error) {
+
+	accs, err := dataStorage.AccsCollection.GetMap(asset)
+	if err != nil {
+		return false, err
+	}
+
+	ast, err := dataStorage.Asts.Get(string(asset))
+	if err != nil {
+		return false, err
+	}
+
+	stats := &info.AssetStats{height, timestamp, accs.Count, 0}
+	if ast != nil {
+		stats.Supply = ast.Supply
+	}
+
+	countKey := "stats:asset:" + string(asset) + ":count"
+	count, _, err := getStatsUint(writer, countKey)
+	if err != nil {
+		return false, err
+	}
+
+	if count > 0 {
+		var last *info.AssetStats
+		if last, err = loadAssetStats(writer, asset, count-1); err != nil {
+			return false, err
+		}
+		if last.Holders == stats.Holders && last.Supply == stats.Supply {
+			return false, nil
+		}
+	}
+
+	marshal, err := msgpack.Marshal(stats)
+	if err != nil {
+		return false, err
+	}
+
+	writer.Put("stats:asset:"+string(asset)+":"+strconv.FormatUint(count, 10), marshal)
+	writer.Put(countKey, []byte(strconv.FormatUint(count+1, 10)))
+	return true, nil
+}
+
+func saveBlockStats(writer store_db_interface.StoreDBTransactionInterface, blkComplete *block_complete.BlockComplete, prevTimestamp uint64, target *big.Int, dataStorage *data_storage.DataStorage) (err error) {
+
+	stats := &info.BlockStats{
+		Height:     blkComplete.Block.Height,
+		Timestamp:  blkComplete.Block.Timestamp,
+		Size:       blkComplete.BloomBlkComplete.Size,
+		TXs:        uint64(len(blkComplete.Txs)),
+		Difficulty: difficulty.ConvertTargetToDifficulty(target).String(),
+		Scripts:    make(map[string]uint64),
+		RingSizes:  make(map[int]uint64),
+	}
+
+	if stats.Height > 0 && stats.Timestamp > prevTimestamp {
+		stats.Interval = stats.Timestamp - prevTimestamp
+	}
+
+	if stats.Fees, err = blkComplete.ComputeFees(); err != nil {
+		return
+	}
+
+	assets := map[string][]byte{string(config_coins.NATIVE_ASSET_FULL): config_coins.NATIVE_ASSET_FULL}
+
+	for _, tx := range blkComplete.Txs {
+		switch tx.Version {
+		case transaction_type.TX_SIMPLE:
+			txBase := tx.TransactionBaseInterface.(*transaction_simple.TransactionSimple)
+			stats.Scripts[txBase.TxScript.String()] += 1
+		case transaction_type.TX_ZETHER:
+			txBase := tx.TransactionBaseInterface.(*transaction_zether.TransactionZether)
+			for _, payload := range txBase.Payloads {
+				stats.Scripts[payload.PayloadScript.String()] += 1
+				stats.RingSizes[payload.Statement.RingSize] += 1
+				assets[string(payload.Asset)] = payload.Asset
+			}
+		}
+	}
+
+	//sorted to store the same index on every node
+	list := make([][]byte, 0, len(assets))
+	for _, asset := range assets {
+		list = append(list, asset)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return string(list[i]) < string(list[j])
+	})
+
+	for _, asset := range list {
+		var appended bool
+		if appended, err = appendAssetStats(writer, asset, stats.Height, stats.Timestamp, dataStorage); err != nil {
+			return
+		}
+		if appended {
+			stats.Assets = append(stats.Assets, asset)
+		}
+	}
+
+	var marshal []byte
+	if marshal, err = msgpack.Marshal(stats); err != nil {
+		return
+	}
+
+	heightStr := strconv.FormatUint(stats.Height, 10)
+	writer.Put("stats:block:"+heightStr, marshal)
+
+	//the indexed blocks are always continuous. In case the analytics were disabled for a while, the index starts again
+	firstHeight, exists, err := getStatsUint(writer, "stats:firstHeight")
+	if err != nil {
+		return
+	}
+	if !exists || firstHeight > stats.Height || (stats.Height > 0 && !writer.Exists("stats:block:"+strconv.FormatUint(stats.Height-1, 10))) {
+		writer.Put("stats:firstHeight", []byte(heightStr))
+	}
+
+	return
+}
+
+func removeBlockStats(writer store_db_interface.StoreDBTransactionInterface, height uint64) (err error) {
+
+	var stats *info.BlockStats
+	if stats, err = loadBlockStats(writer, height); err != nil || stats == nil {
+		return
+	}
+
+	for _, asset := range stats.Assets {
+
+		countKey := "stats:asset:" + string(asset) + ":count"
+
+		var count uint64
+		if count, _, err = getStatsUint(writer, countKey); err != nil {
+			return
+		}
+		if count == 0 {
+			return errors.New("Asset stats count is invalid")
+		}
+
+		var last *info.AssetStats
+		if last, err = loadAssetStats(writer, asset, count-1); err != nil {
+			return
+		}
+		if last.Height != height {
+			continue
+		}
+
+		count -= 1
+		writer.Delete("stats:asset:" + string(asset) + ":" + strconv.FormatUint(count, 10))
+		if count == 0 {
+			writer.Delete(countKey)
+		} else {
+			writer.Put(countKey, []byte(strconv.FormatUint(count, 10)))
+		}
+	}
+
+	heightStr := strconv.FormatUint(height, 10)
+	writer.Delete("stats:block:" + heightStr)
+
+	if firstHeight := writer.Get("stats:firstHeight"); firstHeight != nil && string(firstHeight) == heightStr {
+		writer.Delete("stats:firstHeight")
+	}
+
+	return
+}
+
+//searchStatsHeight returns the first height between start and end for which the condition is true using the timestamps of the indexed blocks
+func searchStatsHeight(reader store_db_interface.StoreDBTransactionInterface, start, end uint64, condition func(timestamp uint64) bool) (out uint64, err error) {
+
+	for start < end {
+		middle := start + (end-start)/2
+
+		var stats *info.BlockStats
+		if stats, err = loadBlockStats(reader, middle); err != nil {
+			return
+		}
+		if stats == nil {
+			return 0, errors.New("Block stats were not found")
+		}
+
+		if condition(stats.Timestamp) {
+			end = middle
+		} else {
+			start = middle + 1
+		}
+	}
+
+	return start, nil
+}
+
+//loadStatsRange returns the heights range [start, end) of the indexed blocks which satisfy both the heights and the time range. Zero values are ignored
+func loadStatsRange(reader store_db_interface.StoreDBTransactionInterface, startHeight, endHeight, startTime, endTime uint64) (start, end uint64, err error) {
+
+	firstHeight, exists, err := getStatsUint(reader, "stats:firstHeight")
+	if err != nil {
+		return
+	}
+	if !exists {
+		return 0, 0, errors.New("Analytics are not indexed yet")
+	}
+
+	chainHeight, _ := binary.Uvarint(reader.Get("chainHeight"))
+
+	start, end = firstHeight, chainHeight
+	if startHeight > start {
+		start = startHeight
+	}
+	if endHeight > 0 && endHeight+1 < end {
+		end = endHeight + 1
+	}
+	if start >= end {
+		return start, start, nil
+	}
+
+	if startTime > 0 {
+		if start, err = searchStatsHeight(reader, start, end, func(timestamp uint64) bool { return timestamp >= startTime }); err != nil {
+			return
+		}
+	}
+	if endTime > 0 && start < end {
+		if end, err = searchStatsHeight(reader, start, end, func(timestamp uint64) bool { return timestamp > endTime }); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+//OpenLoadBlocksStats returns the stats of the blocks in the range. A step greater than one returns only every step-th block
+func (chain *Blockchain) OpenLoadBlocksStats(startHeight, endHeight, startTime, endTime, step uint64) (list []*info.BlockStats, err error) {
+
+	if step == 0 {
+		step = 1
+	}
+
+	err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		var start, end uint64
+		if start, end, err = loadStatsRange(reader, startHeight, endHeight, startTime, endTime); err != nil {
+			return
+		}
+
+		list = make([]*info.BlockStats, 0)
+		for height := start; height < end && len(list) < STATS_MAX_RESULTS; height += step {
+			var stats *info.BlockStats
+			if stats, err = loadBlockStats(reader, height); err != nil {
+				return
+			}
+			if stats == nil {
+				return errors.New("Block stats were not found")
+			}
+			list = append(list, stats)
+		}
+
+		return
+	})
+
+	return
+}
+
+//OpenLoadAssetStats returns the entries of the asset history in the range together with the last entry before the range which gives the holders and supply at its start
+func (chain *Blockchain) OpenLoadAssetStats(asset []byte, startHeight, endHeight, startTime, endTime uint64) (list []*info.AssetStats, err error) {
+
+	err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		var start, end uint64
+		if start, end, err = loadStatsRange(reader, startHeight, endHeight, startTime, endTime); err != nil {
+			return
+		}
+
+		var count uint64
+		if count, _, err = getStatsUint(reader, "stats:asset:"+string(asset)+":count"); err != nil {
+			return
+		}
+
+		//first entry with the height greater than start
+		left, right := uint64(0), count
+		for left < right {
+			middle := left + (right-left)/2
+
+			var stats *info.AssetStats
+			if stats, err = loadAssetStats(reader, asset, middle); err != nil {
+				return
+			}
+
+			if stats.Height > start {
+				right = middle
+			} else {
+				left = middle + 1
+			}
+		}
+		if left > 0 {
+			left -= 1
+		}
+
+		list = make([]*info.AssetStats, 0)
+		for i := left; i < count && len(list) < STATS_MAX_RESULTS; i++ {
+			var stats *info.AssetStats
+			if stats, err = loadAssetStats(reader, asset, i); err != nil {
+				return
+			}
+			if stats.Height >= end {
+				break
+			}
+			list = append(list, stats)
+		}
+
+		return
+	})
+
+	return
+}
+
+//OpenLoadFeeLiquidityProviders returns the plain accounts providing the highest fee liquidity for the asset
+func (chain *Blockchain) OpenLoadFeeLiquidityProviders(asset []byte, count int) (list []*info.FeeLiquidityProvider, err error) {
+
+	if count <= 0 || count > STATS_FEE_LIQUIDITY_MAX_RESULTS {
+		count = STATS_FEE_LIQUIDITY_MAX_RESULTS
+	}
+
+	err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		dataStorage := data_storage.NewDataStorage(reader)
+
+		maxHeap, err := dataStorage.AstsFeeLiquidityCollection.GetMaxHeap(asset)
+		if err != nil {
+			return
+		}
+
+		//the heap is walked best first, so only the top elements are read
+		list = make([]*info.FeeLiquidityProvider, 0, count)
+		if err = maxHeap.Walk(func(element *min_max_heap.HeapElement) bool {
+			list = append(list, &info.FeeLiquidityProvider{PublicKey: element.Key, Score: element.Score})
+			return len(list) < count
+		}); err != nil {
+			return
+		}
+
+		for _, provider := range list {
+			plainAcc, err := dataStorage.PlainAccs.Get(string(provider.PublicKey))
+			if err != nil {
+				return err
+			}
+			if plainAcc == nil {
+				continue
+			}
+			if liquidity := plainAcc.AssetFeeLiquidities.GetLiquidity(asset); liquidity != nil {
+				provider.Rate = liquidity.Rate
+				provider.LeadingZeros = liquidity.LeadingZeros
+			}
+			provider.Collector = plainAcc.AssetFeeLiquidities.Collector
+		}
+
+		return
+	})
+
+	return
+}
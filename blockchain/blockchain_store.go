@@ -22,6 +22,44 @@ func (chain *Blockchain) OpenExistsTx(hash []byte) (exists bool, errFinal error)
 	return
 }
 
+//OpenLoadTxStatus checks the blockchain first, as an included tx may still be in the mempool for a short time
+func (chain *Blockchain) OpenLoadTxStatus(hash []byte) (status *blockchain_types.TxStatus, errFinal error) {
+
+	status = &blockchain_types.TxStatus{Status: blockchain_types.TX_STATUS_UNKNOWN}
+
+	history := chain.mempool.Txs.GetHistory(string(hash))
+	if history != nil {
+		status.Orphaned = history.Orphaned
+	}
+
+	if errFinal = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+		if data := reader.Get("txBlock:" + string(hash)); data != nil {
+			chainHeight, _ := binary.Uvarint(reader.Get("chainHeight"))
+			status.Status = blockchain_types.TX_STATUS_INCLUDED
+			status.BlockHeight, _ = binary.Uvarint(data)
+			if chainHeight > status.BlockHeight {
+				status.Confirmations = chainHeight - status.BlockHeight
+			}
+		}
+		return nil
+	}); errFinal != nil || status.Status == blockchain_types.TX_STATUS_INCLUDED {
+		return
+	}
+
+	if chain.mempool.Txs.Exists(string(hash)) {
+		status.Status = blockchain_types.TX_STATUS_PENDING
+	} else if history != nil {
+		if history.Error != "" {
+			status.Status = blockchain_types.TX_STATUS_INVALID
+			status.Error = history.Error
+		} else {
+			status.Status = blockchain_types.TX_STATUS_DROPPED
+		}
+	}
+
+	return
+}
+
 func (chain *Blockchain) OpenExistsBlock(hash []byte) (exists bool, errFinal error) {
 	errFinal = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
 		exists = reader.Exists("blockHeight_ByHash" + string(hash)) //optimized
@@ -123,6 +161,12 @@ func (chain *Blockchain) removeBlockComplete(writer store_db_interface.StoreDBTr
 		}
 	}
 
+	if config.NODE_PROVIDE_ANALYTICS {
+		if err = removeBlockStats(writer, blockHeight); err != nil {
+			return
+		}
+	}
+
 	return allTransactionsChangesFinal, nil
 }
 
@@ -5,6 +5,7 @@ import (
 	"pandora-pay/config/arguments"
 	"pandora-pay/gui"
 	"pandora-pay/helpers/generics"
+	"pandora-pay/helpers/metrics"
 	"pandora-pay/helpers/multicast"
 	"pandora-pay/helpers/recovery"
 	"time"
@@ -18,6 +19,12 @@ type BlockchainSyncData struct {
 	Started                       bool   `json:"started" msgpack:"started" `
 }
 
+var (
+	metricSync                      = metrics.NewGauge("pandora_sync", "1 if the node is synchronized with the network")
+	metricSyncTime                  = metrics.NewGauge("pandora_sync_time_seconds", "Unix time when the node was synchronized last time")
+	metricBlocksChangedLastInterval = metrics.NewGauge("pandora_sync_blocks_changed_last_interval", "Blocks changed in the current sync interval")
+)
+
 type BlockchainSync struct {
 	syncData            *generics.Value[*BlockchainSyncData]
 	UpdateSyncMulticast *multicast.MulticastChannel[*BlockchainSyncData]
@@ -101,6 +108,14 @@ func (self *BlockchainSync) start() {
 				return
 			}
 
+			if chainSyncData.Sync {
+				metricSync.Set(1)
+			} else {
+				metricSync.Set(0)
+			}
+			metricSyncTime.Set(float64(chainSyncData.SyncTime))
+			metricBlocksChangedLastInterval.Set(float64(chainSyncData.BlocksChangedLastInterval))
+
 			if chainSyncData.SyncTime != 0 {
 				gui.GUI.Info2Update("Sync", fmt.Sprintf("%s %d", time.Unix(int64(chainSyncData.SyncTime), 0).Format("15:04:05"), chainSyncData.BlocksChangedLastInterval))
 			} else {
@@ -41,6 +41,8 @@ type BlockchainUpdates struct {
 	Registrations  *registrations.Registrations
 	BlockHeight    uint64
 	BlockHash      []byte
+	InsertedBlocks []*block_complete.BlockComplete
+	RemovedHeights []uint64 //heights removed by a chain reorganization
 }
 
 type BlockchainSolutionAnswer struct {
@@ -53,6 +55,24 @@ type BlockchainSolution struct {
 	Done        chan *BlockchainSolutionAnswer
 }
 
+type TxStatusType string
+
+const (
+	TX_STATUS_UNKNOWN  TxStatusType = "unknown"
+	TX_STATUS_PENDING  TxStatusType = "pending"
+	TX_STATUS_INCLUDED TxStatusType = "included"
+	TX_STATUS_DROPPED  TxStatusType = "dropped"
+	TX_STATUS_INVALID  TxStatusType = "invalid"
+)
+
+type TxStatus struct {
+	Status        TxStatusType `json:"status" msgpack:"status"`
+	BlockHeight   uint64       `json:"blockHeight,omitempty" msgpack:"blockHeight,omitempty"`
+	Confirmations uint64       `json:"confirmations,omitempty" msgpack:"confirmations,omitempty"`
+	Orphaned      bool         `json:"orphaned,omitempty" msgpack:"orphaned,omitempty"` //removed from the blockchain by a reorg
+	Error         string       `json:"error,omitempty" msgpack:"error,omitempty"`       //reason of the mempool validation
+}
+
 func ComputeBlockReward(height uint64, txs []*transaction.Transaction) (blockReward uint64, finalForgerReward uint64, err error) {
 
 	blockReward = config_reward.GetRewardAt(height)
@@ -9,6 +9,7 @@ import (
 	"pandora-pay/blockchain/data_storage"
 	"pandora-pay/blockchain/transactions/transaction"
 	"pandora-pay/gui"
+	"pandora-pay/gui/gui_logger"
 	"pandora-pay/helpers/multicast"
 	"pandora-pay/helpers/recovery"
 	"pandora-pay/network/websocks/connection/advanced_connection_types"
@@ -29,6 +30,7 @@ type BlockchainUpdate struct {
 	insertedTxs            map[string]*transaction.Transaction
 	insertedTxsList        []*transaction.Transaction
 	insertedBlocks         []*block_complete.BlockComplete
+	removedBlocksHeights   []uint64
 	calledByForging        bool
 	exceptSocketUUID       advanced_connection_types.UUID
 }
@@ -70,10 +72,10 @@ func (queue *BlockchainUpdatesQueue) lastSuccess(updates []*BlockchainUpdate) *B
 
 func (queue *BlockchainUpdatesQueue) executeUpdate(update *BlockchainUpdate) (err error) {
 
-	gui.GUI.Warning("-------------------------------------------")
-	gui.GUI.Warning(fmt.Sprintf("Included blocks %v - %d | TXs: %d | Hash %s", update.calledByForging, len(update.insertedBlocks), len(update.insertedTxs), base64.StdEncoding.EncodeToString(update.newChainData.Hash)))
-	gui.GUI.Warning(update.newChainData.Height, base64.StdEncoding.EncodeToString(update.newChainData.Hash), update.newChainData.Target.Text(10), update.newChainData.BigTotalDifficulty.Text(10))
-	gui.GUI.Warning("-------------------------------------------")
+	gui.GUI.Warning(gui_logger.SUBSYSTEM_CONSENSUS, "-------------------------------------------")
+	gui.GUI.Warning(gui_logger.SUBSYSTEM_CONSENSUS, fmt.Sprintf("Included blocks %v - %d | TXs: %d | Hash %s", update.calledByForging, len(update.insertedBlocks), len(update.insertedTxs), base64.StdEncoding.EncodeToString(update.newChainData.Hash)))
+	gui.GUI.Warning(gui_logger.SUBSYSTEM_CONSENSUS, update.newChainData.Height, base64.StdEncoding.EncodeToString(update.newChainData.Hash), update.newChainData.Target.Text(10), update.newChainData.BigTotalDifficulty.Text(10))
+	gui.GUI.Warning(gui_logger.SUBSYSTEM_CONSENSUS, "-------------------------------------------")
 	update.newChainData.updateChainInfo()
 
 	queue.chain.UpdateNewChainUpdate.Broadcast(&blockchain_types.BlockchainUpdates{
@@ -83,6 +85,8 @@ func (queue *BlockchainUpdatesQueue) executeUpdate(update *BlockchainUpdate) (er
 		update.dataStorage.Regs,
 		update.newChainData.Height,
 		update.newChainData.Hash,
+		update.insertedBlocks,
+		update.removedBlocksHeights,
 	})
 
 	chainSyncData := queue.chain.Sync.AddBlocksChanged(uint32(len(update.insertedBlocks)), true)
@@ -90,7 +94,7 @@ func (queue *BlockchainUpdatesQueue) executeUpdate(update *BlockchainUpdate) (er
 	queue.updatesMempool.Broadcast(update)
 	queue.updatesNotifications.Broadcast(update)
 
-	gui.GUI.Log("queue.chain.UpdateNewChain fired")
+	gui.GUI.Log(gui_logger.SUBSYSTEM_CONSENSUS, "queue.chain.UpdateNewChain fired")
 	queue.chain.UpdateNewChain.Broadcast(update.newChainData.Height)
 
 	queue.chain.UpdateNewChainDataUpdate.Broadcast(&BlockchainDataUpdate{
@@ -125,7 +129,7 @@ func (queue *BlockchainUpdatesQueue) processBlockchainUpdatesQueue() {
 			for _, update = range works {
 				if update.err == nil {
 					if err := queue.executeUpdate(update); err != nil {
-						gui.GUI.Error("Error processUpdate", err)
+						gui.GUI.Error(gui_logger.SUBSYSTEM_CONSENSUS, "Error processUpdate", err)
 					}
 				}
 			}
@@ -9,6 +9,7 @@ import (
 	"pandora-pay/blockchain/transactions/transaction"
 	"pandora-pay/config"
 	"pandora-pay/gui"
+	"pandora-pay/gui/gui_logger"
 	"pandora-pay/helpers/generics"
 	"pandora-pay/helpers/multicast"
 	"pandora-pay/helpers/recovery"
@@ -70,7 +71,7 @@ func (forging *Forging) InitializeForging(createForgingTransactions func(*block_
 func (forging *Forging) StartForging() bool {
 
 	if config.NODE_CONSENSUS != config.NODE_CONSENSUS_TYPE_FULL {
-		gui.GUI.Warning(`Staking was not started as "--node-consensus=full" is missing`)
+		gui.GUI.Warning(gui_logger.SUBSYSTEM_FORGING, `Staking was not started as "--node-consensus=full" is missing`)
 		return false
 	}
 
@@ -83,6 +84,10 @@ func (forging *Forging) StartForging() bool {
 	return true
 }
 
+func (forging *Forging) IsForging() bool {
+	return forging.started.IsSet()
+}
+
 func (forging *Forging) StopForging() bool {
 	if forging.started.SetToIf(true, false) {
 		return true
@@ -9,8 +9,10 @@ import (
 	"pandora-pay/blockchain/forging/forging_block_work"
 	"pandora-pay/blockchain/transactions/transaction"
 	"pandora-pay/gui"
+	"pandora-pay/gui/gui_logger"
 	"pandora-pay/helpers/advanced_buffers"
 	"pandora-pay/helpers/generics"
+	"pandora-pay/helpers/metrics"
 	"pandora-pay/helpers/recovery"
 	"pandora-pay/mempool"
 	"strconv"
@@ -18,6 +20,11 @@ import (
 	"time"
 )
 
+var (
+	metricHashesPerSecond = metrics.NewGauge("pandora_forging_hashes_per_second", "Forging hashes per second of all forging threads")
+	metricHashes          = metrics.NewCounter("pandora_forging_hashes_total", "Forging hashes computed")
+)
+
 type ForgingThread struct {
 	mempool                   *mempool.Mempool
 	addressBalanceDecryptor   *address_balance_decryptor.AddressBalanceDecryptor
@@ -53,11 +60,15 @@ func (thread *ForgingThread) startForging() {
 		for {
 
 			s := ""
+			total := uint64(0)
 			for i := 0; i < thread.threads; i++ {
 				hashesPerSecond := atomic.SwapUint32(&thread.workers[i].hashes, 0)
 				s += strconv.FormatUint(uint64(hashesPerSecond), 10) + " "
+				total += uint64(hashesPerSecond)
 			}
 			gui.GUI.InfoUpdate("Hashes/s", s)
+			metricHashesPerSecond.Set(float64(total))
+			metricHashes.Add(total)
 
 			time.Sleep(time.Second)
 		}
@@ -79,9 +90,9 @@ func (thread *ForgingThread) startForging() {
 			}
 
 			if newKernelHash, err = thread.publishSolution(solution); err != nil {
-				gui.GUI.Error(fmt.Errorf("Error publishing solution: %d error: %s ", solution.blkComplete.Height, err))
+				gui.GUI.Error(gui_logger.SUBSYSTEM_FORGING, fmt.Errorf("Error publishing solution: %d error: %s ", solution.blkComplete.Height, err))
 			} else {
-				gui.GUI.Info(fmt.Errorf("Block was forged! %d ", solution.blkComplete.Height))
+				gui.GUI.Info(gui_logger.SUBSYSTEM_FORGING, fmt.Errorf("Block was forged! %d ", solution.blkComplete.Height))
 				thread.lastPrevKernelHash.Store(newKernelHash)
 			}
 
@@ -15,6 +15,7 @@ import (
 	"pandora-pay/config/config_forging"
 	"pandora-pay/cryptography/crypto"
 	"pandora-pay/gui"
+	"pandora-pay/gui/gui_logger"
 	"pandora-pay/helpers/generics"
 	"pandora-pay/helpers/multicast"
 	"pandora-pay/store"
@@ -227,7 +228,7 @@ func (w *ForgingWallet) runProcessUpdates() {
 					return
 				}(); err != nil {
 					w.deleteAccount(key)
-					gui.GUI.Error(err)
+					gui.GUI.Error(gui_logger.SUBSYSTEM_FORGING, err)
 				}
 
 			}
@@ -262,7 +263,7 @@ func (w *ForgingWallet) runProcessUpdates() {
 
 					} else if v.Stored == "delete" {
 						w.deleteAccount(k)
-						gui.GUI.Error("Account was deleted from Forging")
+						gui.GUI.Error(gui_logger.SUBSYSTEM_FORGING, "Account was deleted from Forging")
 					}
 
 				}
@@ -13,6 +13,7 @@ import (
 	"pandora-pay/cryptography/bn256"
 	"pandora-pay/cryptography/crypto"
 	"pandora-pay/gui"
+	"pandora-pay/gui/gui_logger"
 	"pandora-pay/helpers"
 	"pandora-pay/helpers/generics"
 	"strconv"
@@ -242,7 +243,7 @@ func (worker *ForgingWorkerThread) forge() {
 
 						requireStakingAmount := new(big.Int).Div(new(big.Int).SetBytes(kernelHash), work.Target)
 
-						gui.GUI.Log("forged", worker.index, " -> ", work.BlkHeight, work.BlkComplete.PrevHash, address.walletAdr.decryptedStakingBalance)
+						gui.GUI.Log(gui_logger.SUBSYSTEM_FORGING, "forged", worker.index, " -> ", work.BlkHeight, work.BlkComplete.PrevHash, address.walletAdr.decryptedStakingBalance)
 
 						solution := &ForgingSolution{
 							localTimestamp,
@@ -273,7 +274,7 @@ func (worker *ForgingWorkerThread) forge() {
 						}
 
 					} /* else { // for debugging only
-						gui.GUI.Log(base64.StdEncoding.EncodeToString(kernelHash), strconv.FormatUint(timestamp, 10 ))
+						gui.GUI.Log(gui_logger.SUBSYSTEM_FORGING, base64.StdEncoding.EncodeToString(kernelHash), strconv.FormatUint(timestamp, 10 ))
 					}*/
 
 					walletsStakedTimestamp[key] += 1
@@ -0,0 +1,29 @@
+package info
+
+type BlockStats struct {
+	Height     uint64            `json:"height" msgpack:"height"`
+	Timestamp  uint64            `json:"timestamp" msgpack:"timestamp"`
+	Interval   uint64            `json:"interval" msgpack:"interval"` //seconds since the previous block
+	Size       uint64            `json:"size" msgpack:"size"`
+	TXs        uint64            `json:"txs" msgpack:"txs"`
+	Fees       uint64            `json:"fees" msgpack:"fees"`
+	Difficulty string            `json:"difficulty" msgpack:"difficulty"`
+	Scripts    map[string]uint64 `json:"scripts" msgpack:"scripts"`     //txs and payloads by script
+	RingSizes  map[int]uint64    `json:"ringSizes" msgpack:"ringSizes"` //zether payloads by ring size
+	Assets     [][]byte          `json:"-" msgpack:"assets"`            //assets which got a new AssetStats entry in this block
+}
+
+type AssetStats struct {
+	Height    uint64 `json:"height" msgpack:"height"`
+	Timestamp uint64 `json:"timestamp" msgpack:"timestamp"`
+	Holders   uint64 `json:"holders" msgpack:"holders"`
+	Supply    uint64 `json:"supply" msgpack:"supply"`
+}
+
+type FeeLiquidityProvider struct {
+	PublicKey    []byte  `json:"publicKey" msgpack:"publicKey"`
+	Score        float64 `json:"score" msgpack:"score"`
+	Rate         uint64  `json:"rate" msgpack:"rate"`
+	LeadingZeros byte    `json:"leadingZeros" msgpack:"leadingZeros"`
+	Collector    []byte  `json:"collector" msgpack:"collector"`
+}
@@ -52,12 +52,18 @@ func initialize() {
 				"importWalletJSON":        js.FuncOf(importWalletJSON),
 				"exportWalletJSON":        js.FuncOf(exportWalletJSON),
 				"importWalletAddressJSON": js.FuncOf(importWalletAddressJSON),
+				"getWalletContacts":       js.FuncOf(getWalletContacts),
+				"addWalletContact":        js.FuncOf(addWalletContact),
+				"updateWalletContact":     js.FuncOf(updateWalletContact),
+				"removeWalletContact":     js.FuncOf(removeWalletContact),
 				"encryption": js.ValueOf(map[string]any{
 					"checkPasswordWallet":    js.FuncOf(checkPasswordWallet),
 					"encryptWallet":          js.FuncOf(encryptWallet),
 					"decryptWallet":          js.FuncOf(decryptWallet),
 					"removeEncryptionWallet": js.FuncOf(removeEncryptionWallet),
 					"logoutWallet":           js.FuncOf(logoutWallet),
+					"changePasswordWallet":   js.FuncOf(changePasswordWallet),
+					"setAutoLockWallet":      js.FuncOf(setAutoLockWallet),
 				}),
 				"setWalletNonHardening": js.FuncOf(setWalletNonHardening),
 			}),
@@ -177,6 +183,10 @@ func initialize() {
 						"SUBSCRIPTION_ASSET":                js.ValueOf(int(api_code_types.SUBSCRIPTION_ASSET)),
 						"SUBSCRIPTION_REGISTRATION":         js.ValueOf(int(api_code_types.SUBSCRIPTION_REGISTRATION)),
 						"SUBSCRIPTION_TRANSACTION":          js.ValueOf(int(api_code_types.SUBSCRIPTION_TRANSACTION)),
+						"SUBSCRIPTION_BLOCKS":               js.ValueOf(int(api_code_types.SUBSCRIPTION_BLOCKS)),
+						"SUBSCRIPTION_REORGS":               js.ValueOf(int(api_code_types.SUBSCRIPTION_REORGS)),
+						"SUBSCRIPTION_MEMPOOL":              js.ValueOf(int(api_code_types.SUBSCRIPTION_MEMPOOL)),
+						"SUBSCRIPTION_TX_STATUS":            js.ValueOf(int(api_code_types.SUBSCRIPTION_TX_STATUS)),
 					}),
 				}),
 			}),
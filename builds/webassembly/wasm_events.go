@@ -3,10 +3,13 @@ package main
 import (
 	"encoding/base64"
 	"errors"
+	"pandora-pay/blockchain/blockchain_types"
+	"pandora-pay/blockchain/blocks/block"
 	"pandora-pay/blockchain/data_storage/accounts/account"
 	"pandora-pay/blockchain/data_storage/assets/asset"
 	"pandora-pay/blockchain/data_storage/plain_accounts/plain_account"
 	"pandora-pay/blockchain/data_storage/registrations/registration"
+	"pandora-pay/blockchain/transactions/transaction"
 	"pandora-pay/builds/webassembly/webassembly_utils"
 	"pandora-pay/config/globals"
 	"pandora-pay/helpers/advanced_buffers"
@@ -133,6 +136,30 @@ func listenNetworkNotifications(this js.Value, args []js.Value) interface{} {
 					case api_code_types.SUBSCRIPTION_TRANSACTION:
 						object = data.Data
 						extra = &api_types.APISubscriptionNotificationTxExtra{}
+					case api_code_types.SUBSCRIPTION_BLOCKS:
+						blk := block.CreateEmptyBlock()
+						if err = blk.Deserialize(advanced_buffers.NewBufferReader(data.Data)); err != nil {
+							return
+						}
+						if err = blk.BloomNow(); err != nil {
+							return
+						}
+						object = blk
+						extra = &api_types.APISubscriptionNotificationBlockExtra{}
+					case api_code_types.SUBSCRIPTION_REORGS:
+						extra = &api_types.APISubscriptionNotificationReorgExtra{}
+					case api_code_types.SUBSCRIPTION_MEMPOOL:
+						tx := &transaction.Transaction{}
+						if err = tx.Deserialize(advanced_buffers.NewBufferReader(data.Data)); err != nil {
+							return
+						}
+						if err = tx.BloomAll(); err != nil {
+							return
+						}
+						object = tx
+						extra = &api_types.APISubscriptionNotificationMempoolExtra{}
+					case api_code_types.SUBSCRIPTION_TX_STATUS:
+						extra = &blockchain_types.TxStatus{}
 					default:
 						return //invalid
 					}
@@ -7,6 +7,7 @@ import (
 	"pandora-pay/builds/webassembly/webassembly_utils"
 	"pandora-pay/helpers"
 	"pandora-pay/helpers/advanced_buffers"
+	"pandora-pay/wallet/wallet_contact"
 	"syscall/js"
 )
 
@@ -203,6 +204,60 @@ func importWalletAddressJSON(this js.Value, args []js.Value) interface{} {
 	})
 }
 
+func getWalletContacts(this js.Value, args []js.Value) interface{} {
+	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
+		if err := app.Wallet.Encryption.CheckPassword(args[0].String(), false); err != nil {
+			return nil, err
+		}
+		return webassembly_utils.ConvertJSONBytes(app.Wallet.GetContacts(true))
+	})
+}
+
+func addWalletContact(this js.Value, args []js.Value) interface{} {
+	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
+		if err := app.Wallet.Encryption.CheckPassword(args[0].String(), false); err != nil {
+			return nil, err
+		}
+
+		contact := &wallet_contact.WalletContact{}
+		if err := webassembly_utils.UnmarshalBytes(args[1], contact); err != nil {
+			return nil, err
+		}
+
+		if err := app.Wallet.AddContact(contact, true); err != nil {
+			return nil, err
+		}
+		return true, nil
+	})
+}
+
+func updateWalletContact(this js.Value, args []js.Value) interface{} {
+	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
+		if err := app.Wallet.Encryption.CheckPassword(args[0].String(), false); err != nil {
+			return nil, err
+		}
+
+		contact := &wallet_contact.WalletContact{}
+		if err := webassembly_utils.UnmarshalBytes(args[2], contact); err != nil {
+			return nil, err
+		}
+
+		if err := app.Wallet.UpdateContact(args[1].String(), contact, true); err != nil {
+			return nil, err
+		}
+		return true, nil
+	})
+}
+
+func removeWalletContact(this js.Value, args []js.Value) interface{} {
+	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
+		if err := app.Wallet.Encryption.CheckPassword(args[0].String(), false); err != nil {
+			return nil, err
+		}
+		return app.Wallet.RemoveContact(args[1].String(), true)
+	})
+}
+
 func checkPasswordWallet(this js.Value, args []js.Value) interface{} {
 	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
 		if err := app.Wallet.Encryption.CheckPassword(args[0].String(), false); err != nil {
@@ -230,6 +285,27 @@ func decryptWallet(this js.Value, args []js.Value) interface{} {
 	})
 }
 
+func changePasswordWallet(this js.Value, args []js.Value) interface{} {
+	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
+		if err := app.Wallet.Encryption.ChangeEncryption(args[0].String(), args[1].String(), args[2].Int()); err != nil {
+			return nil, err
+		}
+		return true, nil
+	})
+}
+
+func setAutoLockWallet(this js.Value, args []js.Value) interface{} {
+	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
+		if err := app.Wallet.Encryption.CheckPassword(args[0].String(), false); err != nil {
+			return nil, err
+		}
+		if err := app.Wallet.Encryption.SetAutoLockTimeout(uint64(args[1].Int())); err != nil {
+			return nil, err
+		}
+		return true, nil
+	})
+}
+
 func removeEncryptionWallet(this js.Value, args []js.Value) interface{} {
 	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
 		if err := app.Wallet.Encryption.CheckPassword(args[0].String(), true); err != nil {
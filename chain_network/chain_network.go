@@ -5,45 +5,60 @@ import (
 	"pandora-pay/config"
 	"pandora-pay/helpers/recovery"
 	"pandora-pay/mempool"
-	"pandora-pay/network/api_implementation/api_websockets/consensus"
+	"pandora-pay/network/dandelion"
+	"pandora-pay/network/network_config"
 	"pandora-pay/network/server/node_http"
 	"pandora-pay/network/websocks"
 	"pandora-pay/network/websocks/connection"
+	"pandora-pay/network/websocks/connection/advanced_connection_types"
 	"time"
 )
 
 func continuouslyDownloadChain() {
 	recovery.SafeGo(func() {
 
+		consensus := node_http.HttpServer.ApiWebsockets.Consensus
+
 		for {
 
 			list := websocks.Websockets.GetAllSockets()
+
+			//the peers announce their new tips. Only the silent ones are polled
+			connected := make(map[advanced_connection_types.UUID]bool)
 			for _, conn := range list {
-				if conn.Handshake.Consensus == config.NODE_CONSENSUS_TYPE_FULL {
-					data, err := connection.SendJSONAwaitAnswer[consensus.ChainUpdateNotification](conn, []byte("get-chain"), nil, nil, 0)
-					if err == nil {
-						node_http.HttpServer.ApiWebsockets.Consensus.ChainUpdateProcess(conn, data)
-					}
+				connected[conn.UUID] = true
+				if conn.Handshake.Consensus == config.NODE_CONSENSUS_TYPE_FULL && consensus.ShouldPollTip(conn) {
+					consensus.PollTip(conn)
 					time.Sleep(1 * time.Millisecond)
 				}
 			}
 
+			consensus.RemoveDisconnectedTips(connected)
+
 			time.Sleep(2000 * time.Millisecond)
 		}
 
 	})
 }
 
-func continuouslyDownloadMempool() {
+func continuouslyDownloadMempool(mempool *mempool.Mempool) {
 
 	recovery.SafeGo(func() {
 
 		for {
 
 			list := websocks.Websockets.GetAllSockets()
+
 			for _, conn := range list {
 				if config.NODE_CONSENSUS == config.NODE_CONSENSUS_TYPE_FULL && conn.Handshake.Consensus == config.NODE_CONSENSUS_TYPE_FULL {
-					DownloadMempool(conn)
+
+					//older peers don't support the reconciliation and their mempool is downloaded page by page
+					if conn.HasFeature(connection.FEATURE_MEMPOOL_RECONCILIATION) {
+						ReconcileMempool(conn, mempool)
+					} else {
+						DownloadMempool(conn)
+					}
+
 					time.Sleep(1 * time.Millisecond)
 				}
 			}
@@ -71,10 +86,7 @@ func syncBlockchainNewConnections() {
 			//making it async
 			recovery.SafeGo(func() {
 
-				data, err := connection.SendJSONAwaitAnswer[consensus.ChainUpdateNotification](conn, []byte("get-chain"), nil, nil, 0)
-				if err == nil {
-					node_http.HttpServer.ApiWebsockets.Consensus.ChainUpdateProcess(conn, data)
-				}
+				node_http.HttpServer.ApiWebsockets.Consensus.PollTip(conn)
 
 			})
 
@@ -87,7 +99,10 @@ func InitChainNetwork(chain *blockchain.Blockchain, mempool *mempool.Mempool) {
 	continuouslyDownloadChain()
 
 	if config.NODE_CONSENSUS == config.NODE_CONSENSUS_TYPE_FULL {
-		continuouslyDownloadMempool()
+		continuouslyDownloadMempool(mempool)
+		if network_config.NETWORK_DANDELION_ENABLED {
+			dandelion.InitDandelion(chain, mempool)
+		}
 	}
 
 	syncBlockchainNewConnections()
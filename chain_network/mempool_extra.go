@@ -5,18 +5,35 @@ import (
 	"pandora-pay/blockchain"
 	"pandora-pay/blockchain/transactions/transaction"
 	"pandora-pay/config"
+	"pandora-pay/gui"
+	"pandora-pay/gui/gui_logger"
 	"pandora-pay/helpers/recovery"
 	"pandora-pay/mempool"
 	"pandora-pay/network/api_implementation/api_common"
 	"pandora-pay/network/network_config"
 	"pandora-pay/network/server/node_http"
 	"pandora-pay/network/websocks"
+	"pandora-pay/network/websocks/connection"
 	"pandora-pay/network/websocks/connection/advanced_connection_types"
 	"time"
 )
 
+//broadcastChain announces the new tip to every peer which doesn't have it yet. The peers are sent concurrently, so a stalled peer doesn't delay the others
 func broadcastChain(newChainData *blockchain.BlockchainData, ctxDuration time.Duration) {
-	websocks.Websockets.BroadcastJSON([]byte("chain-update"), node_http.HttpServer.ApiWebsockets.Consensus.GetUpdateNotification(newChainData), map[config.NodeConsensusType]bool{config.NODE_CONSENSUS_TYPE_FULL: true, config.NODE_CONSENSUS_TYPE_APP: true}, advanced_connection_types.UUID_ALL, ctxDuration)
+
+	consensus := node_http.HttpServer.ApiWebsockets.Consensus
+
+	announcement, err := consensus.NewTipAnnouncement(newChainData)
+	if err != nil {
+		gui.GUI.Error(gui_logger.SUBSYSTEM_NETWORK, "Error creating the tip announcement", err)
+		return
+	}
+
+	for _, conn := range websocks.Websockets.GetAllSockets() {
+		go func(conn *connection.AdvancedConnection) {
+			consensus.Announce(conn, announcement, ctxDuration)
+		}(conn)
+	}
 }
 
 func BroadcastTxs(txs []*transaction.Transaction, justCreated, awaitPropagation bool, exceptSocketUUID advanced_connection_types.UUID, ctxParent context.Context) []error {
@@ -2,6 +2,7 @@ package chain_network
 
 import (
 	"pandora-pay/config"
+	"pandora-pay/mempool"
 	"pandora-pay/network/api_implementation/api_common"
 	"pandora-pay/network/server/node_http"
 	"pandora-pay/network/websocks/connection"
@@ -47,3 +48,30 @@ func DownloadMempool(conn *connection.AdvancedConnection) (err error) {
 
 	return
 }
+
+//ReconcileMempool downloads only the txs which are missing. The filter is sent only when the digests of the mempools are different
+func ReconcileMempool(conn *connection.AdvancedConnection, mempool *mempool.Mempool) (err error) {
+
+	digest, count := mempool.Txs.GetDigest()
+
+	var data *api_common.APIMempoolReconcileReply
+	if data, err = connection.SendJSONAwaitAnswer[api_common.APIMempoolReconcileReply](conn, []byte("mempool/reconcile"), &api_common.APIMempoolReconcileRequest{digest, count, nil}, nil, 0); err != nil {
+		return
+	}
+
+	if data.Synced || data.Count == 0 {
+		return
+	}
+
+	filter := mempool.Txs.CreateFilter()
+	if data, err = connection.SendJSONAwaitAnswer[api_common.APIMempoolReconcileReply](conn, []byte("mempool/reconcile"), &api_common.APIMempoolReconcileRequest{digest, count, filter}, nil, 0); err != nil {
+		return
+	}
+
+	cb := node_http.HttpServer.ApiWebsockets.GetMap["mempool/new-tx-id"]
+	for _, tx := range data.Hashes {
+		cb(conn, tx)
+	}
+
+	return
+}
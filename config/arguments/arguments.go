@@ -2,16 +2,94 @@ package arguments
 
 import (
 	"github.com/docopt/docopt.go"
+	"os"
+	"pandora-pay/helpers/generics"
+	"strings"
 )
 
+//Arguments are the arguments used at start. They are never changed, the reloadable settings must be read using GetArguments
 var Arguments map[string]any
+
+//reloaded is published again by ReloadArguments
+var reloaded = &generics.Value[map[string]any]{}
 var VERSION_STRING string
 
+var argvUsed []string
+
+//ENV_PREFIX is used to read the options from environment variables. --auth-users becomes PANDORAPAY_AUTH_USERS
+const ENV_PREFIX = "PANDORAPAY_"
+
+func getEnvName(option string) string {
+	return ENV_PREFIX + strings.ToUpper(strings.ReplaceAll(strings.TrimPrefix(option, "--"), "-", "_"))
+}
+
+//getExplicitOptions returns the options that were specified in the command line
+func getExplicitOptions(argv []string) map[string]bool {
+	explicit := make(map[string]bool)
+	for _, arg := range argv {
+		if strings.HasPrefix(arg, "--") {
+			explicit[strings.SplitN(arg, "=", 2)[0]] = true
+		}
+	}
+	return explicit
+}
+
+//parseArguments applies the precedence: command line > environment variables > config file > defaults
+func parseArguments(argv []string) (map[string]any, error) {
+
+	args, err := docopt.Parse(commands, argv, false, VERSION_STRING, false, false)
+	if err != nil {
+		return nil, err
+	}
+
+	explicit := getExplicitOptions(argv)
+
+	if err = loadConfigFile(args, explicit); err != nil {
+		return nil, err
+	}
+
+	for option, value := range args {
+		if !strings.HasPrefix(option, "--") || explicit[option] || option == "--config" {
+			continue
+		}
+		env, ok := os.LookupEnv(getEnvName(option))
+		if !ok {
+			continue
+		}
+		if _, isFlag := value.(bool); isFlag {
+			args[option] = env == "true" || env == "1"
+		} else {
+			args[option] = env
+		}
+	}
+
+	return args, nil
+}
+
 func InitArguments(argv []string) (err error) {
 
-	if Arguments, err = docopt.Parse(commands, argv, false, VERSION_STRING, false, false); err != nil {
+	if Arguments, err = parseArguments(argv); err != nil {
 		return err
 	}
+	reloaded.Store(Arguments)
+	argvUsed = argv
 
 	return
 }
+
+//ReloadArguments reads again the environment variables and the config file. Only the non consensus settings should be reloaded by the callers
+func ReloadArguments() error {
+
+	args, err := parseArguments(argvUsed)
+	if err != nil {
+		return err
+	}
+
+	reloaded.Store(args)
+	return nil
+}
+
+//GetArguments returns the arguments read by the last reload
+func GetArguments() map[string]any {
+	return reloaded.Load()
+}
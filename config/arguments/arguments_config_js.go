@@ -0,0 +1,8 @@
+//go:build wasm
+// +build wasm
+
+package arguments
+
+func loadConfigFile(args map[string]any, explicit map[string]bool) error {
+	return nil
+}
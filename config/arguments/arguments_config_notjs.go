@@ -0,0 +1,117 @@
+//go:build !wasm
+// +build !wasm
+
+package arguments
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+func readConfigFile(path string) (map[string]any, error) {
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]any)
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		if err = yaml.Unmarshal(data, &values); err != nil {
+			return nil, err
+		}
+	case ".toml":
+		if err = toml.Unmarshal(data, &values); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("Config file must be .yaml, .yml or .toml")
+	}
+
+	return values, nil
+}
+
+func convertConfigValue(key string, value, current any) (any, error) {
+
+	if _, isFlag := current.(bool); isFlag {
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			if v == "true" || v == "false" {
+				return v == "true", nil
+			}
+		}
+		return nil, fmt.Errorf("Config option %s must be a boolean", key)
+	}
+
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case uint64:
+		return strconv.FormatUint(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case []any, map[string]any, []map[string]any:
+		//options like auth-users are JSON. The arrays of tables of TOML are decoded as []map[string]any
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		return string(data), nil
+	case nil:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("Config option %s has an invalid type", key)
+	}
+}
+
+//loadConfigFile overwrites the default values using the file specified by --config. Options specified in the command line are kept
+func loadConfigFile(args map[string]any, explicit map[string]bool) error {
+
+	path, ok := args["--config"].(string)
+	if !ok || path == "" {
+		if path = os.Getenv(getEnvName("--config")); path == "" {
+			return nil
+		}
+	}
+
+	values, err := readConfigFile(path)
+	if err != nil {
+		return fmt.Errorf("Error reading config file %s: %s", path, err)
+	}
+
+	for key, value := range values {
+
+		option := "--" + key
+		current, found := args[option]
+		if !found || option == "--config" || option == "--help" || option == "--version" {
+			return fmt.Errorf("Config file contains unknown option %s", key)
+		}
+
+		if explicit[option] {
+			continue
+		}
+
+		if args[option], err = convertConfigValue(key, value, current); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+//go:build !wasm
+// +build !wasm
+
+package arguments
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConfigFileTOMLAuthUsers(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "config.toml")
+	assert.Nil(t, os.WriteFile(path, []byte(`
+tcp-max-clients = 100
+
+[[auth-users]]
+user = "alice"
+pass = "secret1"
+
+[[auth-users]]
+user = "bob"
+pass = "secret2"
+
+  [auth-users.webhook]
+  url = "https://exchange.net/webhook"
+  secret = "secret3"
+  confirmations = 5
+`), 0600))
+
+	args, err := parseArguments([]string{"--config=" + path})
+	assert.Nil(t, err)
+	assert.Equal(t, "100", args["--tcp-max-clients"])
+
+	var users []struct {
+		User    string `json:"user"`
+		Pass    string `json:"pass"`
+		Webhook *struct {
+			URL           string `json:"url"`
+			Secret        string `json:"secret"`
+			Confirmations uint64 `json:"confirmations"`
+		} `json:"webhook"`
+	}
+	assert.Nil(t, json.Unmarshal([]byte(args["--auth-users"].(string)), &users))
+
+	assert.Equal(t, 2, len(users))
+	assert.Equal(t, "alice", users[0].User)
+	assert.Equal(t, "secret1", users[0].Pass)
+	assert.Nil(t, users[0].Webhook)
+	assert.Equal(t, "bob", users[1].User)
+	assert.Equal(t, "https://exchange.net/webhook", users[1].Webhook.URL)
+	assert.Equal(t, uint64(5), users[1].Webhook.Confirmations)
+}
+
+func TestReloadArguments(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	assert.Nil(t, os.WriteFile(path, []byte("tcp-max-clients: 100\n"), 0600))
+	assert.Nil(t, InitArguments([]string{"--config=" + path}))
+
+	assert.Nil(t, os.WriteFile(path, []byte("tcp-max-clients: 200\n"), 0600))
+	assert.Nil(t, ReloadArguments())
+
+	//the arguments used at start are not changed
+	assert.Equal(t, "100", Arguments["--tcp-max-clients"])
+	assert.Equal(t, "200", GetArguments()["--tcp-max-clients"])
+}
@@ -6,7 +6,7 @@ package arguments
 var commands = `PANDORA PAY WASM.
 
 Usage:
-  pandorapay [--pprof] [--version] [--network=network] [--debug] [--gui-type=type] [--forging] [--new-devnet] [--node-name=name] [--set-genesis=genesis] [--store-wallet-type=type] [--store-chain-type=type] [--node-consensus=type] [--tcp-max-clients=limit] [--node-provide-extended-info-app=bool] [--wallet-encrypt=args] [--wallet-decrypt=password] [--wallet-remove-encryption] [--wallet-export-shared-staked-address=args] [--wallet-import-secret-mnemonic=mnemonic] [--wallet-import-secret-entropy=entropy] [--instance=prefix] [--instance-id=id] [--balance-decryptor-disable-init] [--tcp-connections-ready=threshold] [--exit]
+  pandorapay [--pprof] [--version] [--network=network] [--debug] [--gui-type=type] [--forging] [--new-devnet] [--node-name=name] [--set-genesis=genesis] [--store-wallet-type=type] [--store-chain-type=type] [--node-consensus=type] [--finality-depth=blocks] [--checkpoints=list] [--trusted-peers=list] [--require-peer-identity=bool] [--dandelion=bool] [--tcp-max-clients=limit] [--tcp-upload-limit=KBps] [--tcp-download-limit=KBps] [--tcp-peer-upload-limit=KBps] [--node-provide-extended-info-app=bool] [--node-provide-analytics=bool] [--wallet-encrypt=args] [--wallet-decrypt=password] [--wallet-remove-encryption] [--wallet-export-shared-staked-address=args] [--wallet-import-secret-mnemonic=mnemonic] [--wallet-import-secret-entropy=entropy] [--instance=prefix] [--instance-id=id] [--balance-decryptor-disable-init] [--tcp-connections-ready=threshold] [--exit]
   pandorapay -h | --help
   pandorapay -v | --version
 
@@ -23,10 +23,19 @@ Options:
   --store-chain-type=type                            Set Chain Store Type. Accepted values: "bunt-memory|memory|js". [default: memory].
   --forging                                          Start forging blocks.
   --tcp-max-clients=limit                            Change limit of clients [default: 1].
+  --tcp-upload-limit=KBps                            Limit the upload to the peers in KB/s. Consensus messages are never delayed and historical blocks are sent last [default: unlimited].
+  --tcp-download-limit=KBps                          Limit the download from the peers in KB/s [default: unlimited].
+  --tcp-peer-upload-limit=KBps                       Limit the upload to every peer in KB/s [default: unlimited].
   --tcp-connections-ready=threshold                  Number of connections to become "ready" state [default: 1].
   --node-name=name                                   Change node name.
   --node-consensus=type                              Consensus type. Accepted values: "full|wallet|none". [default: full]
+  --finality-depth=blocks                            Reorgs deeper than the finality depth are rejected. 0 disables it. [default: 720]
+  --checkpoints=list                                 Additional checkpoints as "height:hash,height:hash" with the block hash in hex.
+  --trusted-peers=list                               Pinned peers as "publicKey" or "publicKey:url" separated by ",". Trusted peers are never banned and the peer at the url must prove the public key.
+  --require-peer-identity=bool                       Full node peers must prove a node identity in the handshake, so they can be banned by it. By default false.
+  --dandelion=bool                                   New zether txs are relayed privately through a single peer before being diffused. [default: true]
   --node-provide-extended-info-app=bool              Storing and serving additional info to wallet nodes. [default: true]. To enable, it requires full node
+  --node-provide-analytics=bool                      Indexing and serving the blocks, assets and fee liquidity statistics. [default: false]. To enable, it requires full node
   --wallet-import-secret-mnemonic=mnemonic           Import Wallet from a given Mnemonic. It will delete your existing wallet. 
   --wallet-import-secret-entropy=entropy             Import Wallet from a given Entropy. It will delete your existing wallet.
   --wallet-encrypt=args                              Encrypt wallet. Argument must be "password,difficulty".
@@ -8,7 +8,7 @@ package arguments
 var commands = `PANDORA PAY.
 
 Usage:
-  pandorapay [--pprof] [--network=network] [--debug] [--gui-type=type] [--forging] [--new-devnet] [--run-testnet-script] [--node-name=name] [--tcp-server-port=port] [--tcp-server-address=address] [--tcp-server-auto-tls-certificate] [--tcp-server-tls-cert-file=path] [--tcp-server-tls-key-file=path] [--instance=prefix] [--instance-id=id] [--set-genesis=genesis] [--create-new-genesis=args] [--store-wallet-type=type] [--store-chain-type=type] [--node-consensus=type] [--tcp-max-clients=limit] [--tcp-max-server-sockets=limit] [--node-provide-extended-info-app=bool] [--wallet-encrypt=args] [--wallet-decrypt=password] [--wallet-remove-encryption] [--wallet-export-shared-staked-address=args] [--wallet-import-secret-mnemonic=mnemonic] [--wallet-import-secret-entropy=entropy] [--hcaptcha-secret=args] [--faucet-testnet-enabled=args] [--delegator-enabled=bool] [--delegator-require-auth=bool] [--delegates-maximum=args] [--auth-users=args] [--light-computations] [--balance-decryptor-disable-init] [--balance-decryptor-table-size=size] [--tcp-connections-ready=threshold] [--exit] [--skip-init-sync] [--tcp-server-url=url] [--tcp-proxy=PROXY]
+  pandorapay [--pprof] [--network=network] [--debug] [--gui-type=type] [--forging] [--new-devnet] [--run-testnet-script] [--node-name=name] [--tcp-server-port=port] [--tcp-server-p2p-port=port] [--tcp-server-address=address] [--tcp-server-auto-tls-certificate] [--tcp-server-tls-cert-file=path] [--tcp-server-tls-key-file=path] [--instance=prefix] [--instance-id=id] [--set-genesis=genesis] [--create-new-genesis=args] [--store-wallet-type=type] [--store-chain-type=type] [--node-consensus=type] [--finality-depth=blocks] [--checkpoints=list] [--trusted-peers=list] [--require-peer-identity=bool] [--dandelion=bool] [--tcp-max-clients=limit] [--tcp-upload-limit=KBps] [--tcp-download-limit=KBps] [--tcp-peer-upload-limit=KBps] [--tcp-max-server-sockets=limit] [--node-provide-extended-info-app=bool] [--node-provide-analytics=bool] [--wallet-encrypt=args] [--wallet-decrypt=password] [--wallet-remove-encryption] [--wallet-open=name] [--wallet-open-password-file=path] [--wallet-export-shared-staked-address=args] [--wallet-import-secret-mnemonic=mnemonic] [--wallet-import-secret-entropy=entropy] [--hcaptcha-secret=args] [--faucet-testnet-enabled=args] [--delegator-enabled=bool] [--delegator-require-auth=bool] [--delegates-maximum=args] [--exchange-enabled=bool] [--exchange-wallet=name] [--exchange-confirmations=number] [--auth-users=args] [--light-computations] [--balance-decryptor-disable-init] [--balance-decryptor-table-size=size] [--tcp-connections-ready=threshold] [--exit] [--skip-init-sync] [--tcp-server-url=url] [--tcp-proxy=PROXY] [--config=path] [--log-format=format] [--log-level=levels] [--log-max-size=size] [--log-max-age=days] [--webhooks-test-stub]
   pandorapay -h | --help
   pandorapay -v | --version
 
@@ -29,10 +29,20 @@ Options:
   --forging                                          Start Forging blocks.
   --node-name=name                                   Change node name.
   --node-consensus=type                              Consensus type. Accepted values: "full|app|none" [default: full].
+  --finality-depth=blocks                            Reorgs deeper than the finality depth are rejected. 0 disables it. [default: 720]
+  --checkpoints=list                                 Additional checkpoints as "height:hash,height:hash" with the block hash in hex.
+  --trusted-peers=list                               Pinned peers as "publicKey" or "publicKey:url" separated by ",". Trusted peers are never banned and the peer at the url must prove the public key.
+  --require-peer-identity=bool                       Full node peers must prove a node identity in the handshake, so they can be banned by it. By default false.
+  --dandelion=bool                                   New zether txs are relayed privately through a single peer before being diffused. [default: true]
   --node-provide-extended-info-app=bool              Storing and serving additional info to wallet nodes. [default: true]. To enable, it requires full node
+  --node-provide-analytics=bool                      Indexing and serving the blocks, assets and fee liquidity statistics. [default: false]. To enable, it requires full node
   --tcp-server-url=url                               TCP Server URL (schema, address, port, path).
   --tcp-server-port=port                             Change node tcp server port [default: 8080].
+  --tcp-server-p2p-port=port                         Accept native tcp sockets of the full nodes on this port. Uses the TLS certificate of the tcp server. Disabled by default.
   --tcp-max-clients=limit                            Change limit of clients [default: 50].
+  --tcp-upload-limit=KBps                            Limit the upload to the peers in KB/s. Consensus messages are never delayed and historical blocks are sent last [default: unlimited].
+  --tcp-download-limit=KBps                          Limit the download from the peers in KB/s [default: unlimited].
+  --tcp-peer-upload-limit=KBps                       Limit the upload to every peer in KB/s [default: unlimited].
   --tcp-max-server-sockets=limit                     Change limit of servers [default: 500].
   --tcp-connections-ready=threshold                  Number of connections to become "ready" state [default: 1].
   --tcp-server-address=address                       Change node tcp address.
@@ -45,16 +55,27 @@ Options:
   --wallet-encrypt=args                              Encrypt wallet. Argument must be "password,difficulty".
   --wallet-decrypt=password                          Decrypt wallet.
   --wallet-remove-encryption                         Remove wallet encryption.
+  --wallet-open=name                                 Open and select a named wallet in the CLI. It will be created in case it doesn't exist.
+  --wallet-open-password-file=path                   Read the password of the wallet opened by --wallet-open from the given file.
   --wallet-export-shared-staked-address=args         Derive and export Staked address. Argument must be "account,nonce,path".
   --hcaptcha-secret=args                             hcaptcha Secret.
   --faucet-testnet-enabled=args                      Enable Faucet Testnet. Use "true" to enable it
   --delegator-enabled=bool                           Enable Delegator. Will allow other users to Delegate to the node. Use "true" to enable it
   --delegator-require-auth=bool                      Delegator will require authentication.
   --delegates-maximum=args                           Maximum number of Delegates
+  --exchange-enabled=bool                            Enable the authenticated Exchange deposits and withdrawals API. Use "true" to enable it
+  --exchange-wallet=name                             Opened wallet used by the Exchange API. By default the wallet selected at the first start.
+  --exchange-confirmations=number                    Confirmations required to credit deposits and to confirm withdrawals. [default: 10]
   --auth-users=args                                  Credential for Authenticated Users. Arguments must be a JSON "[{'user': 'username', 'pass': 'secret'}]".
   --light-computations                               Reduces the computations for a testnet node.
   --balance-decryptor-disable-init                   Disable first balance decryptor initialization. 
   --balance-decryptor-table-size=size                Balance Decryptor initial table size. [default: 23]
   --exit                                             Exit node.
   --skip-init-sync                                   Skip sync wait at when the node started. Useful when creating a new testnet.
+  --log-format=format                                Logs format. Accepted values: "text|json". [default: text]
+  --log-level=levels                                 Minimum level of the logs "log|info|warning|error|fatal". It can be set per subsystem "general|consensus|mempool|forging|network|wallet" like "info,network:warning,consensus:log". [default: log]
+  --log-max-size=size                                Logs file is rotated when it exceeds the size in MB. [default: 100]
+  --log-max-age=days                                 Logs files older than the number of days are deleted. [default: 30]
+  --webhooks-test-stub                               Enable a local webhooks receiver at /webhooks/test-stub which verifies and logs the deliveries.
+  --config=path                                      Load options from a YAML or TOML file. Keys are the option names without "--". Command line options override environment variables (PANDORAPAY_AUTH_USERS) which override the file. SIGHUP reloads auth users and connection limits.
 `
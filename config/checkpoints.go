@@ -0,0 +1,98 @@
+package config
+
+import (
+	"encoding/hex"
+	"errors"
+	"pandora-pay/config/arguments"
+	"pandora-pay/cryptography"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type Checkpoint struct {
+	Height uint64 `json:"height" msgpack:"height"`
+	Hash   []byte `json:"hash" msgpack:"hash"`
+}
+
+var (
+	MAIN_NET_CHECKPOINTS = []*Checkpoint{}
+	TEST_NET_CHECKPOINTS = []*Checkpoint{}
+	DEV_NET_CHECKPOINTS  = []*Checkpoint{}
+)
+
+var (
+	/* FINALITY_DEPTH
+	reorgs removing more blocks than the finality depth are rejected. 0 disables the rule
+	*/
+	FINALITY_DEPTH = uint64(720)
+
+	NETWORK_SELECTED_CHECKPOINTS = MAIN_NET_CHECKPOINTS //sorted by height
+	checkpointsMap               = map[uint64][]byte{}
+)
+
+//GetCheckpoint returns the hash of the checkpoint at the height or nil
+func GetCheckpoint(height uint64) []byte {
+	return checkpointsMap[height]
+}
+
+//GetLastCheckpoint returns the highest checkpoint which is not above the height or nil
+func GetLastCheckpoint(height uint64) *Checkpoint {
+	index := sort.Search(len(NETWORK_SELECTED_CHECKPOINTS), func(i int) bool {
+		return NETWORK_SELECTED_CHECKPOINTS[i].Height > height
+	})
+	if index == 0 {
+		return nil
+	}
+	return NETWORK_SELECTED_CHECKPOINTS[index-1]
+}
+
+//initCheckpoints adds the checkpoints given as "height:hash,height:hash" to the hardcoded ones of the selected network
+func initCheckpoints() (err error) {
+
+	list := append([]*Checkpoint{}, NETWORK_SELECTED_CHECKPOINTS...)
+
+	if arguments.Arguments["--checkpoints"] != nil {
+		for _, value := range strings.Split(arguments.Arguments["--checkpoints"].(string), ",") {
+
+			parts := strings.Split(strings.TrimSpace(value), ":")
+			if len(parts) != 2 {
+				return errors.New("Checkpoint must be height:hash")
+			}
+
+			checkpoint := &Checkpoint{}
+			if checkpoint.Height, err = strconv.ParseUint(parts[0], 10, 64); err != nil {
+				return
+			}
+			if checkpoint.Hash, err = hex.DecodeString(parts[1]); err != nil {
+				return
+			}
+			if len(checkpoint.Hash) != cryptography.HashSize {
+				return errors.New("Checkpoint hash length is invalid")
+			}
+
+			list = append(list, checkpoint)
+		}
+	}
+
+	checkpointsMap = make(map[uint64][]byte)
+	for _, checkpoint := range list {
+		if checkpointsMap[checkpoint.Height] != nil {
+			return errors.New("Checkpoint height " + strconv.FormatUint(checkpoint.Height, 10) + " is duplicated")
+		}
+		checkpointsMap[checkpoint.Height] = checkpoint.Hash
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Height < list[j].Height
+	})
+	NETWORK_SELECTED_CHECKPOINTS = list
+
+	if arguments.Arguments["--finality-depth"] != nil {
+		if FINALITY_DEPTH, err = strconv.ParseUint(arguments.Arguments["--finality-depth"].(string), 10, 64); err != nil {
+			return
+		}
+	}
+
+	return
+}
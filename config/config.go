@@ -76,6 +76,7 @@ var (
 
 var (
 	NODE_PROVIDE_EXTENDED_INFO_APP bool
+	NODE_PROVIDE_ANALYTICS         bool
 	NODE_CONSENSUS                 NodeConsensusType = NODE_CONSENSUS_TYPE_FULL
 )
 
@@ -91,12 +92,14 @@ func InitConfig() (err error) {
 	} else if arguments.Arguments["--network"] == "testnet" {
 		NETWORK_SELECTED = TEST_NET_NETWORK_BYTE
 		NETWORK_SELECTED_SEEDS = TEST_NET_SEED_NODES
+		NETWORK_SELECTED_CHECKPOINTS = TEST_NET_CHECKPOINTS
 		NETWORK_SELECTED_DELEGATOR_NODES = config_nodes.TEST_NET_DELEGATOR_NODES
 		NETWORK_SELECTED_NAME = TEST_NET_NETWORK_NAME
 		NETWORK_SELECTED_BYTE_PREFIX = TEST_NET_NETWORK_BYTE_PREFIX
 	} else if arguments.Arguments["--network"] == "devnet" {
 		NETWORK_SELECTED = DEV_NET_NETWORK_BYTE
 		NETWORK_SELECTED_SEEDS = DEV_NET_SEED_NODES
+		NETWORK_SELECTED_CHECKPOINTS = DEV_NET_CHECKPOINTS
 		NETWORK_SELECTED_DELEGATOR_NODES = config_nodes.DEV_NET_DELEGATOR_NODES
 		NETWORK_SELECTED_NAME = DEV_NET_NETWORK_NAME
 		NETWORK_SELECTED_BYTE_PREFIX = DEV_NET_NETWORK_BYTE_PREFIX
@@ -104,6 +107,10 @@ func InitConfig() (err error) {
 		return errors.New("selected --network is invalid. Accepted only: mainnet, testnet, devnet")
 	}
 
+	if err = initCheckpoints(); err != nil {
+		return
+	}
+
 	if arguments.Arguments["--debug"] == true {
 		DEBUG = true
 	}
@@ -113,12 +120,16 @@ func InitConfig() (err error) {
 	}
 
 	NODE_PROVIDE_EXTENDED_INFO_APP = false
+	NODE_PROVIDE_ANALYTICS = false
 	switch arguments.Arguments["--node-consensus"] {
 	case "full":
 		NODE_CONSENSUS = NODE_CONSENSUS_TYPE_FULL
 		if arguments.Arguments["--node-provide-extended-info-app"] == "true" {
 			NODE_PROVIDE_EXTENDED_INFO_APP = true
 		}
+		if arguments.Arguments["--node-provide-analytics"] == "true" {
+			NODE_PROVIDE_ANALYTICS = true
+		}
 	case "app":
 		NODE_CONSENSUS = NODE_CONSENSUS_TYPE_APP
 	case "none":
@@ -81,5 +81,9 @@ func InitConfig() (err error) {
 		DELEGATOR_REQUIRE_AUTH = true
 	}
 
+	if err = initConfigExchange(); err != nil {
+		return
+	}
+
 	return nil
 }
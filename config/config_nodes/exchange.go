@@ -0,0 +1,38 @@
+package config_nodes
+
+import (
+	"errors"
+	"pandora-pay/config/arguments"
+	"strconv"
+)
+
+var (
+	/* EXCHANGE_ENABLED
+	this will enable the authenticated deposits and withdrawals API used by exchanges
+	*/
+	EXCHANGE_ENABLED       = false
+	EXCHANGE_WALLET        = ""
+	EXCHANGE_CONFIRMATIONS = uint64(10)
+)
+
+func initConfigExchange() (err error) {
+
+	if arguments.Arguments["--exchange-enabled"] == "true" {
+		EXCHANGE_ENABLED = true
+	}
+
+	if arguments.Arguments["--exchange-wallet"] != nil {
+		EXCHANGE_WALLET = arguments.Arguments["--exchange-wallet"].(string)
+	}
+
+	if arguments.Arguments["--exchange-confirmations"] != nil {
+		if EXCHANGE_CONFIRMATIONS, err = strconv.ParseUint(arguments.Arguments["--exchange-confirmations"].(string), 10, 64); err != nil {
+			return
+		}
+		if EXCHANGE_CONFIRMATIONS == 0 {
+			return errors.New("Exchange confirmations must be at least 1")
+		}
+	}
+
+	return
+}
@@ -43,6 +43,11 @@ func CreateEncryptionCipher(password string, salt []byte, time uint32) (*Encrypt
 }
 
 func (encryption *EncryptionCipher) Encrypt(data []byte) ([]byte, error) {
+	return encryption.EncryptWithAdditionalData(data, nil)
+}
+
+//EncryptWithAdditionalData authenticates the additional data without encrypting it
+func (encryption *EncryptionCipher) EncryptWithAdditionalData(data, additionalData []byte) ([]byte, error) {
 
 	encryption.Lock()
 	defer encryption.Unlock()
@@ -52,18 +57,26 @@ func (encryption *EncryptionCipher) Encrypt(data []byte) ([]byte, error) {
 		return nil, err
 	}
 
-	return encryption.gcm.Seal(nonce, nonce, data, nil), nil
+	return encryption.gcm.Seal(nonce, nonce, data, additionalData), nil
 }
 
 func (encryption *EncryptionCipher) Decrypt(data []byte) ([]byte, error) {
+	return encryption.DecryptWithAdditionalData(data, nil)
+}
+
+//DecryptWithAdditionalData fails when the additional data is not the one used by the encryption
+func (encryption *EncryptionCipher) DecryptWithAdditionalData(data, additionalData []byte) ([]byte, error) {
 
 	encryption.Lock()
 	defer encryption.Unlock()
 
 	nonceSize := encryption.gcm.NonceSize()
+	if len(data) < nonceSize {
+		return nil, errors.New("Encrypted data is too short")
+	}
 	nonce, ciphertext := data[:nonceSize], data[nonceSize:]
 
-	out, err := encryption.gcm.Open(nil, nonce, ciphertext, nil)
+	out, err := encryption.gcm.Open(nil, nonce, ciphertext, additionalData)
 	if err != nil {
 		return nil, err
 	}
@@ -36,6 +36,11 @@ type Command struct {
 }
 
 var commands = []Command{
+	{Name: "Wallets", Text: "List Wallets"},
+	{Name: "Wallets", Text: "Create Wallet"},
+	{Name: "Wallets", Text: "Open Wallet"},
+	{Name: "Wallets", Text: "Close Wallet"},
+	{Name: "Wallets", Text: "Select Wallet"},
 	{Name: "Wallet", Text: "List Addresses"},
 	{Name: "Wallet", Text: "Scan Addresses"},
 	{Name: "Wallet", Text: "Create New Address"},
@@ -48,7 +53,11 @@ var commands = []Command{
 	{Name: "Wallet", Text: "Import Address Secret Key"},
 	{Name: "Wallet", Text: "Remove Address"},
 	{Name: "Wallet", Text: "Export Staked Staked Address"},
+	{Name: "Wallet", Text: "List Contacts"},
+	{Name: "Wallet", Text: "Add Contact"},
+	{Name: "Wallet", Text: "Remove Contact"},
 	{Name: "Wallet:TX", Text: "Private Transfer"},
+	{Name: "Wallet:TX", Text: "Private Sweep"},
 	{Name: "Wallet:TX", Text: "Private Delegate Stake"},
 	{Name: "Wallet:TX", Text: "Private Claim"},
 	{Name: "Wallet:TX", Text: "Private Asset Create"},
@@ -62,9 +71,13 @@ var commands = []Command{
 	{Name: "Wallet", Text: "Import Address JSON"},
 	{Name: "Wallet", Text: "Export Wallet JSON"},
 	{Name: "Wallet", Text: "Import Wallet JSON"},
+	{Name: "Wallet", Text: "Export Wallet Backup"},
+	{Name: "Wallet", Text: "Restore Wallet Backup"},
 	{Name: "Wallet", Text: "Encrypt Wallet"},
 	{Name: "Wallet", Text: "Decrypt Wallet"},
 	{Name: "Wallet", Text: "Remove Encryption"},
+	{Name: "Wallet", Text: "Change Wallet Password"},
+	{Name: "Wallet", Text: "Set Wallet Auto Lock"},
 	{Name: "Utils", Text: "Create (PublicKey, PrivateKey) pair"},
 	{Name: "Utils", Text: "Sign message using PrivateKey"},
 	{Name: "Utils", Text: "Sign Resolution Conditional Payment"},
@@ -47,7 +47,7 @@ func (g *GUIInteractive) Close() {
 	g.tickerRender.Stop()
 	ui.Clear()
 	ui.Close()
-	g.logger.GeneralLog.Close()
+	g.logger.Close()
 }
 
 func CreateGUIInteractive() (*GUIInteractive, error) {
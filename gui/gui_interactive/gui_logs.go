@@ -4,6 +4,7 @@ import (
 	"github.com/gizak/termui/v3/widgets"
 	"pandora-pay/config"
 	"pandora-pay/gui/gui_interface"
+	"pandora-pay/gui/gui_logger"
 	"strings"
 	"time"
 )
@@ -19,44 +20,51 @@ func (g *GUIInteractive) logsRender() {
 	g.logs.Unlock()
 }
 
-func (g *GUIInteractive) message(prefix string, color string, any ...interface{}) {
+func (g *GUIInteractive) message(level gui_logger.Level, color string, any ...interface{}) {
 
+	//the level is checked before formatting the message
+	subsystem, any := gui_logger.GetSubsystem(any)
+	if !g.logger.Enabled(level, subsystem) {
+		return
+	}
+
+	t := time.Now()
 	text := gui_interface.ProcessArgument(any...)
 
+	g.logger.Write(t, level, subsystem, text)
+
 	if config.DEBUG {
-		text = time.Now().Format("2006-01-02 15:04:05  ") + text
+		text = t.Format("2006-01-02 15:04:05  ") + text
 	} else {
-		text = time.Now().Format("15:04:05  ") + text
+		text = t.Format("15:04:05  ") + text
 	}
 
-	final1 := prefix + " " + text + config.LineBreak
-	final2 := "[" + text + "]" + color + config.LineBreak
+	final := "[" + text + "]" + color + config.LineBreak
 
 	g.logs.Lock()
-	g.logger.GeneralLog.WriteString(final1)
-	g.logs.Text += final2
+	g.logs.Text += final
 	g.logs.Unlock()
 }
 
 func (g *GUIInteractive) Log(any ...interface{}) {
-	g.message("LOG", "()", any...)
+	g.message(gui_logger.LEVEL_LOG, "()", any...)
 }
 
 func (g *GUIInteractive) Info(any ...interface{}) {
-	g.message("INF", "(fg:blue)", any...)
+	g.message(gui_logger.LEVEL_INFO, "(fg:blue)", any...)
 }
 
 func (g *GUIInteractive) Warning(any ...interface{}) {
-	g.message("WARN", "(fg:yellow)", any...)
+	g.message(gui_logger.LEVEL_WARNING, "(fg:yellow)", any...)
 }
 
 func (g *GUIInteractive) Fatal(any ...interface{}) {
-	g.message("FATAL", "(fg:red,fg:bold)", any...)
+	g.message(gui_logger.LEVEL_FATAL, "(fg:red,fg:bold)", any...)
 	panic(any)
 }
 
 func (g *GUIInteractive) Error(any ...interface{}) {
-	g.message("ERR", "(fg:red)", any...)
+	g.message(gui_logger.LEVEL_ERROR, "(fg:red)", any...)
 }
 
 func (g *GUIInteractive) logsInit() {
@@ -1,29 +1,253 @@
 package gui_logger
 
 import (
+	"encoding/json"
+	"errors"
 	"os"
+	"pandora-pay/config/arguments"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
+type Level uint8
+
+const (
+	LEVEL_LOG Level = iota
+	LEVEL_INFO
+	LEVEL_WARNING
+	LEVEL_ERROR
+	LEVEL_FATAL
+)
+
+var levelNames = []string{"log", "info", "warning", "error", "fatal"}
+
+func (level Level) String() string {
+	return levelNames[level]
+}
+
+func parseLevel(str string) (Level, error) {
+	for i, name := range levelNames {
+		if name == str {
+			return Level(i), nil
+		}
+	}
+	return 0, errors.New("Invalid log level " + str)
+}
+
+type LogEntry struct {
+	Time      string `json:"time"`
+	Level     string `json:"level"`
+	Subsystem string `json:"subsystem"`
+	Message   string `json:"msg"`
+}
+
 type GUILogger struct {
-	GeneralLog *os.File
+	JSON         bool
+	defaultLevel Level
+	levels       map[Subsystem]Level //per subsystem
+	maxSize      int64
+	maxAge       time.Duration
+	file         *os.File
+	fileDay      string
+	fileSize     int64
+	lock         sync.Mutex
+}
+
+const LOGS_PATH = "./logs"
+
+//Enabled returns if a message of the subsystem with the given level should be logged
+func (logger *GUILogger) Enabled(level Level, subsystem Subsystem) bool {
+	if logger == nil {
+		return true
+	}
+	minimum, ok := logger.levels[subsystem]
+	if !ok {
+		minimum = logger.defaultLevel
+	}
+	return level >= minimum
+}
+
+//Format returns a single line either JSON or text
+func (logger *GUILogger) Format(t time.Time, level Level, subsystem Subsystem, text string) string {
+	if logger != nil && logger.JSON {
+		data, _ := json.Marshal(&LogEntry{t.UTC().Format(time.RFC3339Nano), level.String(), string(subsystem), text})
+		return string(data)
+	}
+	return strings.ToUpper(level.String()) + " " + t.Format("2006-01-02 15:04:05") + " [" + string(subsystem) + "] " + text
+}
+
+//Write appends the message to the log file. The log file is rotated daily or when it exceeds the max size
+func (logger *GUILogger) Write(t time.Time, level Level, subsystem Subsystem, text string) {
+
+	if logger == nil || !logger.Enabled(level, subsystem) {
+		return
+	}
+
+	line := logger.Format(t, level, subsystem, text) + "\n"
+
+	logger.lock.Lock()
+	defer logger.lock.Unlock()
+
+	if logger.file == nil {
+		return
+	}
+
+	if logger.fileDay != t.Format("2006_01_02") || (logger.maxSize > 0 && logger.fileSize+int64(len(line)) > logger.maxSize) {
+		if err := logger.rotate(t); err != nil {
+			return
+		}
+	}
+
+	n, _ := logger.file.WriteString(line)
+	logger.fileSize += int64(n)
+}
+
+func (logger *GUILogger) open(t time.Time) (err error) {
+
+	logger.fileDay = t.Format("2006_01_02")
+	if logger.file, err = os.OpenFile(filepath.Join(LOGS_PATH, "log_"+logger.fileDay+".log"), os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666); err != nil {
+		return
+	}
+
+	stat, err := logger.file.Stat()
+	if err != nil {
+		return
+	}
+	logger.fileSize = stat.Size()
+
+	return
+}
+
+//must be locked before
+func (logger *GUILogger) rotate(t time.Time) error {
+
+	filename := logger.file.Name()
+	logger.file.Close()
+	logger.file = nil
+
+	//the current file is full, it is moved away
+	if logger.fileDay == t.Format("2006_01_02") {
+		os.Rename(filename, strings.TrimSuffix(filename, ".log")+"_"+t.Format("150405.000000")+".log")
+	}
+
+	logger.removeOldLogs(t)
+
+	return logger.open(t)
+}
+
+func (logger *GUILogger) removeOldLogs(t time.Time) {
+
+	if logger.maxAge <= 0 {
+		return
+	}
+
+	files, err := os.ReadDir(LOGS_PATH)
+	if err != nil {
+		return
+	}
+
+	for _, file := range files {
+		if file.IsDir() || !strings.HasPrefix(file.Name(), "log_") {
+			continue
+		}
+		info, err := file.Info()
+		if err == nil && t.Sub(info.ModTime()) > logger.maxAge {
+			os.Remove(filepath.Join(LOGS_PATH, file.Name()))
+		}
+	}
+}
+
+func (logger *GUILogger) Close() {
+	logger.lock.Lock()
+	defer logger.lock.Unlock()
+	if logger.file != nil {
+		logger.file.Close()
+		logger.file = nil
+	}
+}
+
+//parseLevels reads levels like "info" or "info,network:warning,consensus:log"
+func (logger *GUILogger) parseLevels(str string) (err error) {
+
+	for _, part := range strings.Split(str, ",") {
+		if part = strings.TrimSpace(part); part == "" {
+			continue
+		}
+
+		values := strings.Split(part, ":")
+		switch len(values) {
+		case 1:
+			if logger.defaultLevel, err = parseLevel(values[0]); err != nil {
+				return
+			}
+		case 2:
+			if !isSubsystem(values[0]) {
+				return errors.New("Invalid log subsystem " + values[0])
+			}
+			if logger.levels[Subsystem(values[0])], err = parseLevel(values[1]); err != nil {
+				return
+			}
+		default:
+			return errors.New("Invalid log level " + part)
+		}
+	}
+
+	return
 }
 
 func CreateLogger() (*GUILogger, error) {
 
-	logger := &GUILogger{}
+	logger := &GUILogger{
+		levels:  make(map[Subsystem]Level),
+		maxSize: 100 * 1024 * 1024,
+		maxAge:  30 * 24 * time.Hour,
+	}
 	var err error
 
-	if _, err = os.Stat("./logs"); os.IsNotExist(err) {
-		if err = os.Mkdir("./logs", 0755); err != nil {
+	if arguments.Arguments["--log-format"] != nil {
+		switch arguments.Arguments["--log-format"] {
+		case "json":
+			logger.JSON = true
+		case "text":
+		default:
+			return nil, errors.New("Invalid --log-format argument")
+		}
+	}
+
+	if arguments.Arguments["--log-level"] != nil {
+		if err = logger.parseLevels(arguments.Arguments["--log-level"].(string)); err != nil {
 			return nil, err
 		}
 	}
 
-	t := time.Now()
-	filename := "log_" + t.Format("2006_01_02") + ".log"
+	if arguments.Arguments["--log-max-size"] != nil {
+		var size uint64
+		if size, err = strconv.ParseUint(arguments.Arguments["--log-max-size"].(string), 10, 64); err != nil {
+			return nil, err
+		}
+		logger.maxSize = int64(size) * 1024 * 1024
+	}
+
+	if arguments.Arguments["--log-max-age"] != nil {
+		var days uint64
+		if days, err = strconv.ParseUint(arguments.Arguments["--log-max-age"].(string), 10, 64); err != nil {
+			return nil, err
+		}
+		logger.maxAge = time.Duration(days) * 24 * time.Hour
+	}
 
-	if logger.GeneralLog, err = os.OpenFile("./logs/"+filename, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666); err != nil {
+	if _, err = os.Stat(LOGS_PATH); os.IsNotExist(err) {
+		if err = os.Mkdir(LOGS_PATH, 0755); err != nil {
+			return nil, err
+		}
+	}
+
+	t := time.Now()
+	logger.removeOldLogs(t)
+	if err = logger.open(t); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,31 @@
+package gui_logger
+
+//Subsystem tags a message when it is given as the first argument of the GUI log functions
+type Subsystem string
+
+const (
+	SUBSYSTEM_GENERAL   Subsystem = "general"
+	SUBSYSTEM_CONSENSUS Subsystem = "consensus"
+	SUBSYSTEM_MEMPOOL   Subsystem = "mempool"
+	SUBSYSTEM_FORGING   Subsystem = "forging"
+	SUBSYSTEM_NETWORK   Subsystem = "network"
+	SUBSYSTEM_WALLET    Subsystem = "wallet"
+)
+
+func isSubsystem(name string) bool {
+	switch Subsystem(name) {
+	case SUBSYSTEM_GENERAL, SUBSYSTEM_CONSENSUS, SUBSYSTEM_MEMPOOL, SUBSYSTEM_FORGING, SUBSYSTEM_NETWORK, SUBSYSTEM_WALLET:
+		return true
+	}
+	return false
+}
+
+//GetSubsystem returns the subsystem tag of the message and the arguments without it. The messages without a tag are general
+func GetSubsystem(args []any) (Subsystem, []any) {
+	if len(args) > 0 {
+		if subsystem, ok := args[0].(Subsystem); ok {
+			return subsystem, args[1:]
+		}
+	}
+	return SUBSYSTEM_GENERAL, args
+}
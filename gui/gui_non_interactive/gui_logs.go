@@ -3,11 +3,29 @@ package gui_non_interactive
 import (
 	"fmt"
 	"pandora-pay/gui/gui_interface"
+	"pandora-pay/gui/gui_logger"
+	"time"
 )
 
-func (g *GUINonInteractive) message(prefix string, color string, any ...interface{}) {
+func (g *GUINonInteractive) message(level gui_logger.Level, prefix string, color string, any ...interface{}) {
+
+	//the level is checked before formatting the message
+	subsystem, any := gui_logger.GetSubsystem(any)
+	if !g.logger.Enabled(level, subsystem) {
+		return
+	}
+
+	t := time.Now()
 	text := gui_interface.ProcessArgument(any...)
-	final := prefix + " " + color + " " + text
+
+	g.logger.Write(t, level, subsystem, text)
+
+	var final string
+	if g.logger != nil && g.logger.JSON {
+		final = g.logger.Format(t, level, subsystem, text)
+	} else {
+		final = prefix + " " + color + " " + text
+	}
 
 	g.writingMutex.Lock()
 	fmt.Println(final)
@@ -15,22 +33,22 @@ func (g *GUINonInteractive) message(prefix string, color string, any ...interfac
 }
 
 func (g *GUINonInteractive) Log(any ...any) {
-	g.message("LOG", g.colorLog, any...)
+	g.message(gui_logger.LEVEL_LOG, "LOG", g.colorLog, any...)
 }
 
 func (g *GUINonInteractive) Info(any ...any) {
-	g.message("INF", g.colorInfo, any...)
+	g.message(gui_logger.LEVEL_INFO, "INF", g.colorInfo, any...)
 }
 
 func (g *GUINonInteractive) Warning(any ...any) {
-	g.message("WARN", g.colorWarning, any...)
+	g.message(gui_logger.LEVEL_WARNING, "WARN", g.colorWarning, any...)
 }
 
 func (g *GUINonInteractive) Fatal(any ...any) {
-	g.message("FATAL", g.colorFatal, any...)
+	g.message(gui_logger.LEVEL_FATAL, "FATAL", g.colorFatal, any...)
 	panic(any)
 }
 
 func (g *GUINonInteractive) Error(any ...any) {
-	g.message("ERR", g.colorError, any...)
+	g.message(gui_logger.LEVEL_ERROR, "ERR", g.colorError, any...)
 }
@@ -18,11 +18,17 @@ type GUINonInteractive struct {
 }
 
 func (g *GUINonInteractive) Close() {
+	if g.logger != nil {
+		g.logger.Close()
+	}
 }
 
-func CreateGUINonInteractive() (*GUINonInteractive, error) {
+//CreateGUINonInteractive prints the messages to the standard output. The logger is optional
+func CreateGUINonInteractive(logger *gui_logger.GUILogger) (*GUINonInteractive, error) {
 
-	g := &GUINonInteractive{}
+	g := &GUINonInteractive{
+		logger: logger,
+	}
 
 	switch runtime.GOARCH {
 	default:
@@ -7,13 +7,18 @@ import (
 	"errors"
 	"pandora-pay/config/arguments"
 	"pandora-pay/gui/gui_interactive"
+	"pandora-pay/gui/gui_logger"
 	"pandora-pay/gui/gui_non_interactive"
 )
 
 func create_gui() (err error) {
 
 	if arguments.Arguments["--gui-type"] == "non-interactive" {
-		GUI, err = gui_non_interactive.CreateGUINonInteractive()
+		var logger *gui_logger.GUILogger
+		if logger, err = gui_logger.CreateLogger(); err != nil {
+			return
+		}
+		GUI, err = gui_non_interactive.CreateGUINonInteractive(logger)
 	} else if arguments.Arguments["--gui-type"] == "interactive" {
 		GUI, err = gui_interactive.CreateGUIInteractive()
 	} else {
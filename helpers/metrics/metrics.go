@@ -0,0 +1,202 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+//DURATION_BUCKETS are the default histogram buckets (in seconds)
+var DURATION_BUCKETS = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+type metric interface {
+	getName() string
+	write(b *bytes.Buffer)
+}
+
+type base struct {
+	name string
+	help string
+}
+
+func (m *base) getName() string {
+	return m.name
+}
+
+func (m *base) writeHeader(b *bytes.Buffer, kind string) {
+	b.WriteString("# HELP " + m.name + " " + strings.ReplaceAll(m.help, "\n", " ") + "\n")
+	b.WriteString("# TYPE " + m.name + " " + kind + "\n")
+}
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+type Counter struct {
+	base
+	value atomic.Uint64
+}
+
+func (m *Counter) Inc() {
+	m.value.Add(1)
+}
+
+func (m *Counter) Add(value uint64) {
+	m.value.Add(value)
+}
+
+func (m *Counter) Get() uint64 {
+	return m.value.Load()
+}
+
+func (m *Counter) write(b *bytes.Buffer) {
+	m.writeHeader(b, "counter")
+	b.WriteString(m.name + " " + strconv.FormatUint(m.value.Load(), 10) + "\n")
+}
+
+type Gauge struct {
+	base
+	value atomic.Uint64 //float64 bits
+}
+
+func (m *Gauge) Set(value float64) {
+	m.value.Store(math.Float64bits(value))
+}
+
+func (m *Gauge) Add(value float64) {
+	for {
+		old := m.value.Load()
+		if m.value.CompareAndSwap(old, math.Float64bits(math.Float64frombits(old)+value)) {
+			return
+		}
+	}
+}
+
+func (m *Gauge) Get() float64 {
+	return math.Float64frombits(m.value.Load())
+}
+
+func (m *Gauge) write(b *bytes.Buffer) {
+	m.writeHeader(b, "gauge")
+	b.WriteString(m.name + " " + formatFloat(m.Get()) + "\n")
+}
+
+//GaugeFunc is computed only when the metrics are exported
+type GaugeFunc struct {
+	base
+	callback func() float64
+}
+
+func (m *GaugeFunc) write(b *bytes.Buffer) {
+	m.writeHeader(b, "gauge")
+	b.WriteString(m.name + " " + formatFloat(m.callback()) + "\n")
+}
+
+//CounterVec is a set of counters partitioned by the value of a single label
+type CounterVec struct {
+	base
+	label    string
+	lock     sync.RWMutex
+	counters map[string]*atomic.Uint64
+}
+
+func (m *CounterVec) Inc(labelValue string) {
+	m.Add(labelValue, 1)
+}
+
+func (m *CounterVec) Add(labelValue string, value uint64) {
+
+	m.lock.RLock()
+	counter := m.counters[labelValue]
+	m.lock.RUnlock()
+
+	if counter == nil {
+		m.lock.Lock()
+		if counter = m.counters[labelValue]; counter == nil {
+			counter = &atomic.Uint64{}
+			m.counters[labelValue] = counter
+		}
+		m.lock.Unlock()
+	}
+
+	counter.Add(value)
+}
+
+func (m *CounterVec) write(b *bytes.Buffer) {
+
+	m.writeHeader(b, "counter")
+
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	keys := make([]string, 0, len(m.counters))
+	for key := range m.counters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		b.WriteString(fmt.Sprintf("%s{%s=\"%s\"} %d\n", m.name, m.label, escapeLabel(key), m.counters[key].Load()))
+	}
+}
+
+type Histogram struct {
+	base
+	lock    sync.Mutex
+	buckets []float64
+	counts  []uint64
+	sum     float64
+	count   uint64
+}
+
+func (m *Histogram) Observe(value float64) {
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for i, bucket := range m.buckets {
+		if value <= bucket {
+			m.counts[i] += 1
+		}
+	}
+	m.sum += value
+	m.count += 1
+}
+
+//ObserveDuration adds the time elapsed since start in seconds
+func (m *Histogram) ObserveDuration(start time.Time) {
+	m.Observe(time.Since(start).Seconds())
+}
+
+func (m *Histogram) write(b *bytes.Buffer) {
+
+	m.writeHeader(b, "histogram")
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for i, bucket := range m.buckets {
+		b.WriteString(fmt.Sprintf("%s_bucket{le=\"%s\"} %d\n", m.name, formatFloat(bucket), m.counts[i]))
+	}
+	b.WriteString(fmt.Sprintf("%s_bucket{le=\"+Inf\"} %d\n", m.name, m.count))
+	b.WriteString(m.name + "_sum " + formatFloat(m.sum) + "\n")
+	b.WriteString(m.name + "_count " + strconv.FormatUint(m.count, 10) + "\n")
+}
@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+type registryType struct {
+	lock sync.RWMutex
+	list map[string]metric
+}
+
+var registry = &registryType{
+	list: make(map[string]metric),
+}
+
+//register replaces any previous metric with the same name
+func register[T metric](m T) T {
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+	registry.list[m.getName()] = m
+	return m
+}
+
+func NewCounter(name, help string) *Counter {
+	return register(&Counter{base: base{name, help}})
+}
+
+func NewGauge(name, help string) *Gauge {
+	return register(&Gauge{base: base{name, help}})
+}
+
+func NewGaugeFunc(name, help string, callback func() float64) *GaugeFunc {
+	return register(&GaugeFunc{base{name, help}, callback})
+}
+
+func NewCounterVec(name, help, label string) *CounterVec {
+	return register(&CounterVec{base: base{name, help}, label: label, counters: make(map[string]*atomic.Uint64)})
+}
+
+func NewHistogram(name, help string, buckets []float64) *Histogram {
+	return register(&Histogram{base: base{name, help}, buckets: buckets, counts: make([]uint64, len(buckets))})
+}
+
+//Export returns all the registered metrics in the Prometheus text exposition format
+func Export() []byte {
+
+	registry.lock.RLock()
+	list := make([]metric, 0, len(registry.list))
+	for _, m := range registry.list {
+		list = append(list, m)
+	}
+	registry.lock.RUnlock()
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].getName() < list[j].getName()
+	})
+
+	b := new(bytes.Buffer)
+	for _, m := range list {
+		m.write(b)
+	}
+	return b.Bytes()
+}
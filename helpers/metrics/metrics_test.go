@@ -0,0 +1,34 @@
+package metrics
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestExport(t *testing.T) {
+
+	counter := NewCounter("test_counter_total", "Test counter")
+	counter.Add(3)
+
+	gauge := NewGauge("test_gauge", "Test gauge")
+	gauge.Set(2)
+	gauge.Add(-0.5)
+
+	vec := NewCounterVec("test_vec_total", "Test vec", "route")
+	vec.Inc("a\"b")
+	vec.Inc("a\"b")
+
+	histogram := NewHistogram("test_histogram", "Test histogram", []float64{1, 5})
+	histogram.Observe(0.5)
+	histogram.Observe(3)
+	histogram.Observe(10)
+
+	out := string(Export())
+
+	assert.True(t, strings.Contains(out, "# TYPE test_counter_total counter\ntest_counter_total 3\n"), "counter")
+	assert.True(t, strings.Contains(out, "test_gauge 1.5\n"), "gauge")
+	assert.True(t, strings.Contains(out, "test_vec_total{route=\"a\\\"b\"} 2\n"), "vec")
+	assert.True(t, strings.Contains(out, "test_histogram_bucket{le=\"1\"} 1\ntest_histogram_bucket{le=\"5\"} 2\ntest_histogram_bucket{le=\"+Inf\"} 3\n"), "histogram buckets")
+	assert.True(t, strings.Contains(out, "test_histogram_sum 13.5\ntest_histogram_count 3\n"), "histogram sum")
+}
@@ -10,6 +10,7 @@ import (
 	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload/transaction_zether_payload_script"
 	"pandora-pay/config/config_fees"
 	"pandora-pay/gui"
+	"pandora-pay/gui/gui_logger"
 	"pandora-pay/helpers"
 	"pandora-pay/helpers/generics"
 	"pandora-pay/helpers/recovery"
@@ -37,6 +38,24 @@ type Mempool struct {
 	insertTransactionsCn      chan *MempoolWorkerInsertTxs
 	Txs                       *MempoolTxs
 	OnBroadcastNewTransaction func([]*transaction.Transaction, bool, bool, advanced_connection_types.UUID, context.Context) []error
+	OnStemNewTransaction      func(*transaction.Transaction) bool //returns true when the tx is relayed privately
+	OnGetStemTransactions     func() []*transaction.Transaction   //own txs which are still relayed privately
+}
+
+//GetPendingTxs returns the mempool txs together with the own txs relayed privately, so the new txs are built on top of them
+func (mempool *Mempool) GetPendingTxs() []*transaction.Transaction {
+
+	txs := mempool.Txs.GetTxsOnlyList()
+	if mempool.OnGetStemTransactions == nil {
+		return txs
+	}
+
+	for _, tx := range mempool.OnGetStemTransactions() {
+		if !mempool.Txs.Exists(tx.Bloom.HashStr) {
+			txs = append(txs, tx)
+		}
+	}
+	return txs
 }
 
 func (mempool *Mempool) ContinueProcessing(continueProcessingType ContinueProcessingType) {
@@ -51,7 +70,12 @@ func (mempool *Mempool) RemoveInsertedTxsFromBlockchain(txs []string) bool {
 
 func (mempool *Mempool) InsertRemovedTxsFromBlockchain(txs []*transaction.Transaction, height uint64) bool {
 
-	finalTxs, _ := mempool.processTxsToMempool(txs, height, context.Background())
+	for _, tx := range txs {
+		mempool.Txs.history.setOrphaned(tx.Bloom.HashStr)
+	}
+
+	finalTxs, errs := mempool.processTxsToMempool(txs, height, context.Background())
+	mempool.setHistoryErrors(txs, errs)
 
 	insertTxs := make([]*mempoolTx, len(finalTxs))
 	for i, it := range finalTxs {
@@ -71,6 +95,12 @@ func (mempool *Mempool) AddTxToMempool(tx *transaction.Transaction, height uint6
 	return result[0]
 }
 
+//ValidateTx verifies the tx like it would be added to the mempool, without adding it. The history is not changed
+func (mempool *Mempool) ValidateTx(tx *transaction.Transaction, height uint64) error {
+	_, errs := mempool.processTxsToMempool([]*transaction.Transaction{tx}, height, context.Background())
+	return errs[0]
+}
+
 func (mempool *Mempool) processTxsToMempool(txs []*transaction.Transaction, height uint64, ctx context.Context) (finalTxs []*mempoolTx, errs []error) {
 
 	finalTxs = make([]*mempoolTx, len(txs))
@@ -151,9 +181,28 @@ func (mempool *Mempool) processTxsToMempool(txs []*transaction.Transaction, heig
 	return
 }
 
+//setHistoryErrors records the txs rejected while inserting them in the mempool
+func (mempool *Mempool) setHistoryErrors(txs []*transaction.Transaction, errs []error) {
+	for i, err := range errs {
+		if err != nil && txs[i].Bloom != nil {
+			mempool.Txs.history.setError(txs[i].Bloom.HashStr, err)
+		}
+	}
+}
+
 func (mempool *Mempool) AddTxsToMempool(txs []*transaction.Transaction, height uint64, justCreated, awaitAnswer, awaitBroadcasting bool, exceptSocketUUID advanced_connection_types.UUID, ctx context.Context) []error {
 
 	finalTxs, errs := mempool.processTxsToMempool(txs, height, ctx)
+	mempool.setHistoryErrors(txs, errs)
+
+	//the new zether txs are relayed privately and they are added to the mempool only once they are diffused
+	if justCreated && mempool.OnStemNewTransaction != nil {
+		for i, finalTx := range finalTxs {
+			if finalTx != nil && finalTx.Tx.Version == transaction_type.TX_ZETHER && mempool.OnStemNewTransaction(finalTx.Tx) {
+				finalTxs[i] = nil
+			}
+		}
+	}
 
 	//making sure that the transaction is not inserted twice
 	if runtime.GOARCH != "wasm" {
@@ -235,7 +284,7 @@ func (mempool *Mempool) ContinueWork() {
 
 func CreateMempool() (*Mempool, error) {
 
-	gui.GUI.Log("Mempool init...")
+	gui.GUI.Log(gui_logger.SUBSYSTEM_MEMPOOL, "Mempool init...")
 
 	mempool := &Mempool{
 		&generics.Value[*MempoolResult]{},
@@ -247,6 +296,8 @@ func CreateMempool() (*Mempool, error) {
 		make(chan *MempoolWorkerInsertTxs),
 		createMempoolTxs(),
 		nil,
+		nil,
+		nil,
 	}
 
 	worker := new(mempoolWorker)
@@ -285,6 +285,9 @@ func (worker *mempoolWorker) processing(
 							txsList = slices.Delete(txsList, listIndex-1, listIndex)
 							listIndex--
 						}
+						if !exists {
+							txs.history.setError(tx.Tx.Bloom.HashStr, finalErr)
+						}
 						removeTxNow(tx, newAddTx == nil, exists)
 					}
 
@@ -7,7 +7,9 @@ import (
 	"pandora-pay/blockchain/transactions/transaction"
 	"pandora-pay/config"
 	"pandora-pay/gui"
+	"pandora-pay/gui/gui_logger"
 	"pandora-pay/helpers/generics"
+	"pandora-pay/helpers/metrics"
 	"pandora-pay/helpers/multicast"
 	"pandora-pay/helpers/recovery"
 	"strconv"
@@ -16,6 +18,11 @@ import (
 	"time"
 )
 
+var (
+	metricMempoolTxs   = metrics.NewGauge("pandora_mempool_txs", "Number of transactions in mempool")
+	metricMempoolBytes = metrics.NewGauge("pandora_mempool_bytes", "Size in bytes of the transactions in mempool")
+)
+
 type MempoolAccountTxs struct {
 	txs     map[string]*mempoolTx
 	deleted bool
@@ -27,12 +34,15 @@ type MempoolTxs struct {
 	txsMap                    *generics.Map[string, *mempoolTx]
 	accountsMapTxs            *generics.Map[string, *MempoolAccountTxs]
 	UpdateMempoolTransactions *multicast.MulticastChannel[*blockchain_types.MempoolTransactionUpdate]
+	history                   *mempoolTxsHistory
 }
 
 func (self *MempoolTxs) insertTx(tx *mempoolTx) bool {
 	_, loaded := self.txsMap.LoadOrStore(tx.Tx.Bloom.HashStr, tx)
 	if !loaded {
-		atomic.AddInt32(&self.count, 1)
+		self.history.clearError(tx.Tx.Bloom.HashStr)
+		metricMempoolTxs.Set(float64(atomic.AddInt32(&self.count, 1)))
+		metricMempoolBytes.Add(float64(tx.Tx.Bloom.Size))
 	}
 	return !loaded
 }
@@ -71,9 +81,11 @@ func (self *MempoolTxs) inserted(tx *mempoolTx) {
 }
 
 func (self *MempoolTxs) deleteTx(hashStr string) bool {
-	_, deleted := self.txsMap.LoadAndDelete(hashStr)
+	tx, deleted := self.txsMap.LoadAndDelete(hashStr)
 	if deleted {
-		atomic.AddInt32(&self.count, -1)
+		self.history.update(hashStr, func(history *MempoolTxHistory) {})
+		metricMempoolTxs.Set(float64(atomic.AddInt32(&self.count, -1)))
+		metricMempoolBytes.Add(-float64(tx.Tx.Bloom.Size))
 	}
 	return deleted
 }
@@ -186,6 +198,7 @@ func createMempoolTxs() (txs *MempoolTxs) {
 		&generics.Map[string, *mempoolTx]{},
 		&generics.Map[string, *MempoolAccountTxs]{},
 		multicast.NewMulticastChannel[*blockchain_types.MempoolTransactionUpdate](),
+		newMempoolTxsHistory(),
 	}
 
 	//printing from time to time the mempool
@@ -194,11 +207,11 @@ func createMempoolTxs() (txs *MempoolTxs) {
 			for {
 				transactions := txs.GetTxsFromMap()
 				if len(transactions) != 0 {
-					gui.GUI.Log("")
+					gui.GUI.Log(gui_logger.SUBSYSTEM_MEMPOOL, "")
 					for _, out := range transactions {
-						gui.GUI.Log(fmt.Sprintf("%12s %7d B %5d %15s", time.Unix(out.Added, 0).UTC().Format(time.RFC822), out.Tx.Bloom.Size, out.ChainHeight, base64.StdEncoding.EncodeToString(out.Tx.Bloom.Hash[0:15])))
+						gui.GUI.Log(gui_logger.SUBSYSTEM_MEMPOOL, fmt.Sprintf("%12s %7d B %5d %15s", time.Unix(out.Added, 0).UTC().Format(time.RFC822), out.Tx.Bloom.Size, out.ChainHeight, base64.StdEncoding.EncodeToString(out.Tx.Bloom.Hash[0:15])))
 					}
-					gui.GUI.Log("")
+					gui.GUI.Log(gui_logger.SUBSYSTEM_MEMPOOL, "")
 				}
 				time.Sleep(60 * time.Second)
 			}
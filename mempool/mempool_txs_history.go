@@ -0,0 +1,85 @@
+package mempool
+
+import (
+	"sync"
+)
+
+const MEMPOOL_TXS_HISTORY_MAX = 10000
+
+//MempoolTxHistory is what the mempool remembers about a tx which was rejected or removed
+type MempoolTxHistory struct {
+	Error    string //reason the mempool validation rejected the tx. It is cleared once the tx is inserted or orphaned
+	Orphaned bool   //the tx was removed from the blockchain by a reorg
+}
+
+//mempoolTxsHistory keeps only the last txs
+type mempoolTxsHistory struct {
+	list  map[string]*MempoolTxHistory
+	order []string
+	sync.Mutex
+}
+
+func (self *mempoolTxsHistory) update(hashStr string, callback func(history *MempoolTxHistory)) {
+
+	self.Lock()
+	defer self.Unlock()
+
+	history := self.list[hashStr]
+	if history == nil {
+		if len(self.order) >= MEMPOOL_TXS_HISTORY_MAX {
+			delete(self.list, self.order[0])
+			self.order = self.order[1:]
+		}
+		history = &MempoolTxHistory{}
+		self.list[hashStr] = history
+		self.order = append(self.order, hashStr)
+	}
+
+	callback(history)
+}
+
+//setError is called only when inserting the tx in the mempool failed
+func (self *mempoolTxsHistory) setError(hashStr string, err error) {
+	self.update(hashStr, func(history *MempoolTxHistory) {
+		history.Error = err.Error()
+	})
+}
+
+func (self *mempoolTxsHistory) setOrphaned(hashStr string) {
+	self.update(hashStr, func(history *MempoolTxHistory) {
+		history.Orphaned = true
+		history.Error = ""
+	})
+}
+
+//clearError is called when the tx was inserted in the mempool. The txs without history are skipped
+func (self *mempoolTxsHistory) clearError(hashStr string) {
+
+	self.Lock()
+	defer self.Unlock()
+
+	if history := self.list[hashStr]; history != nil {
+		history.Error = ""
+	}
+}
+
+//GetHistory returns nil in case the tx was never rejected or removed from the mempool
+func (self *MempoolTxs) GetHistory(hashStr string) *MempoolTxHistory {
+
+	self.history.Lock()
+	defer self.history.Unlock()
+
+	if history := self.history.list[hashStr]; history != nil {
+		out := *history
+		return &out
+	}
+	return nil
+}
+
+func newMempoolTxsHistory() *mempoolTxsHistory {
+	return &mempoolTxsHistory{
+		make(map[string]*MempoolTxHistory),
+		make([]string, 0),
+		sync.Mutex{},
+	}
+}
@@ -0,0 +1,27 @@
+package mempool
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMempoolTxsHistory(t *testing.T) {
+
+	history := newMempoolTxsHistory()
+
+	//the txs inserted without being rejected before have no history
+	history.clearError("tx")
+	assert.Nil(t, history.list["tx"])
+
+	history.setError("tx", errors.New("Transaction fee was not accepted"))
+	assert.Equal(t, "Transaction fee was not accepted", history.list["tx"].Error)
+
+	history.clearError("tx")
+	assert.Equal(t, "", history.list["tx"].Error, "Inserting the tx clears the error")
+
+	history.setError("tx", errors.New("Invalid Tx.Version"))
+	history.setOrphaned("tx")
+	assert.True(t, history.list["tx"].Orphaned)
+	assert.Equal(t, "", history.list["tx"].Error, "Orphaning the tx clears the error")
+}
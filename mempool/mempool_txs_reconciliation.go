@@ -0,0 +1,131 @@
+package mempool
+
+import (
+	"encoding/binary"
+	"errors"
+	"pandora-pay/cryptography"
+	"pandora-pay/helpers"
+)
+
+const (
+	MEMPOOL_RECONCILIATION_FILTER_HASHES      = 7  //hash functions
+	MEMPOOL_RECONCILIATION_FILTER_BITS_PER_TX = 10 //around 1% false positives
+	MEMPOOL_RECONCILIATION_FILTER_MIN_SIZE    = 64
+	MEMPOOL_RECONCILIATION_FILTER_MAX_SIZE    = 512 * 1024
+	MEMPOOL_RECONCILIATION_MAX_MISSING_HASHES = 1000
+)
+
+//MempoolTxsFilter is a salted bloom filter of the mempool tx hashes. A peer sends it to receive only the hashes it is missing
+//The salt is random every time, so a tx hidden by a false positive will be found in the next reconciliation
+type MempoolTxsFilter struct {
+	Salt   uint64 `json:"salt" msgpack:"salt"`
+	Hashes byte   `json:"hashes" msgpack:"hashes"`
+	Bits   []byte `json:"bits" msgpack:"bits"`
+}
+
+func (filter *MempoolTxsFilter) positions(hash []byte, callback func(byteIndex int, mask byte) bool) {
+
+	buf := make([]byte, 8+len(hash))
+	binary.LittleEndian.PutUint64(buf, filter.Salt)
+	copy(buf[8:], hash)
+
+	sum := cryptography.SHA3(buf)
+	h1 := binary.LittleEndian.Uint64(sum[0:8])
+	h2 := binary.LittleEndian.Uint64(sum[8:16]) | 1
+
+	size := uint64(len(filter.Bits)) * 8
+	for i := uint64(0); i < uint64(filter.Hashes); i++ {
+		position := (h1 + i*h2) % size
+		if !callback(int(position/8), byte(1)<<(position%8)) {
+			return
+		}
+	}
+}
+
+func (filter *MempoolTxsFilter) Add(hash []byte) {
+	filter.positions(hash, func(byteIndex int, mask byte) bool {
+		filter.Bits[byteIndex] |= mask
+		return true
+	})
+}
+
+func (filter *MempoolTxsFilter) Contains(hash []byte) (found bool) {
+	found = true
+	filter.positions(hash, func(byteIndex int, mask byte) bool {
+		if filter.Bits[byteIndex]&mask == 0 {
+			found = false
+		}
+		return found
+	})
+	return
+}
+
+func (filter *MempoolTxsFilter) Validate() error {
+	if len(filter.Bits) < MEMPOOL_RECONCILIATION_FILTER_MIN_SIZE || len(filter.Bits) > MEMPOOL_RECONCILIATION_FILTER_MAX_SIZE {
+		return errors.New("Invalid filter size")
+	}
+	if filter.Hashes == 0 || filter.Hashes > 2*MEMPOOL_RECONCILIATION_FILTER_HASHES {
+		return errors.New("Invalid filter hashes")
+	}
+	return nil
+}
+
+func NewMempoolTxsFilter(count int) *MempoolTxsFilter {
+
+	size := count * MEMPOOL_RECONCILIATION_FILTER_BITS_PER_TX / 8
+	if size < MEMPOOL_RECONCILIATION_FILTER_MIN_SIZE {
+		size = MEMPOOL_RECONCILIATION_FILTER_MIN_SIZE
+	}
+	if size > MEMPOOL_RECONCILIATION_FILTER_MAX_SIZE {
+		size = MEMPOOL_RECONCILIATION_FILTER_MAX_SIZE
+	}
+
+	return &MempoolTxsFilter{
+		helpers.RandomUint64(),
+		MEMPOOL_RECONCILIATION_FILTER_HASHES,
+		make([]byte, size),
+	}
+}
+
+//GetDigest returns the xor of all tx hashes. Two mempools with the same digest and count have the same txs
+func (self *MempoolTxs) GetDigest() ([]byte, int) {
+
+	digest := make([]byte, cryptography.HashSize)
+	count := 0
+
+	self.txsMap.Range(func(key string, tx *mempoolTx) bool {
+		for i := range digest {
+			digest[i] ^= tx.Tx.Bloom.Hash[i]
+		}
+		count += 1
+		return true
+	})
+
+	return digest, count
+}
+
+func (self *MempoolTxs) CreateFilter() *MempoolTxsFilter {
+
+	txs := self.GetTxsFromMap()
+
+	filter := NewMempoolTxsFilter(len(txs))
+	for _, tx := range txs {
+		filter.Add(tx.Tx.Bloom.Hash)
+	}
+
+	return filter
+}
+
+//GetMissingTxs returns the hashes of the txs which are not in the filter of the peer
+func (self *MempoolTxs) GetMissingTxs(filter *MempoolTxsFilter) [][]byte {
+
+	out := make([][]byte, 0)
+	self.txsMap.Range(func(key string, tx *mempoolTx) bool {
+		if !filter.Contains(tx.Tx.Bloom.Hash) {
+			out = append(out, tx.Tx.Bloom.Hash)
+		}
+		return len(out) < MEMPOOL_RECONCILIATION_MAX_MISSING_HASHES
+	})
+
+	return out
+}
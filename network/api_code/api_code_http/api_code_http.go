@@ -7,10 +7,59 @@ import (
 	"net/url"
 	"pandora-pay/helpers/urldecoder"
 	"pandora-pay/network/api_code/api_code_types"
+	"reflect"
 )
 
-func HandleAuthenticated[T any, B any](callback func(r *http.Request, args *T, reply *B, authenticated bool) error) func(values url.Values) (interface{}, error) {
-	return func(values url.Values) (interface{}, error) {
+//Route describes an HTTP route together with its request and reply types
+//Get or Post serves the HTTP request, while Call serves a JSON-RPC call having the params json encoded
+type Route struct {
+	Args          reflect.Type
+	Reply         reflect.Type
+	Authenticated bool
+	Get           func(values url.Values) (interface{}, error)
+	Post          func(values io.ReadCloser) (interface{}, error)
+	Call          func(params []byte) (interface{}, error)
+}
+
+//InvalidParamsError is returned by Call when the params could not be decoded
+type InvalidParamsError struct {
+	error
+}
+
+func (err *InvalidParamsError) Unwrap() error {
+	return err.error
+}
+
+func newRoute[T any, B any](authenticated bool) *Route {
+	return &Route{
+		Args:          reflect.TypeOf((*T)(nil)).Elem(),
+		Reply:         reflect.TypeOf((*B)(nil)).Elem(),
+		Authenticated: authenticated,
+	}
+}
+
+func handleCall[T any, B any](callback func(r *http.Request, args *T, reply *B, authenticated bool) error) func(params []byte) (interface{}, error) {
+	return func(params []byte) (interface{}, error) {
+
+		args := new(T)
+		if err := json.Unmarshal(params, args); err != nil {
+			return nil, &InvalidParamsError{err}
+		}
+
+		authenticated := new(api_code_types.APIAuthenticated[struct{}])
+		if err := json.Unmarshal(params, authenticated); err != nil {
+			return nil, &InvalidParamsError{err}
+		}
+
+		reply := new(B)
+		return reply, callback(nil, args, reply, authenticated.CheckAuthenticated())
+	}
+}
+
+func HandleAuthenticated[T any, B any](callback func(r *http.Request, args *T, reply *B, authenticated bool) error) *Route {
+
+	route := newRoute[T, B](true)
+	route.Get = func(values url.Values) (interface{}, error) {
 
 		authenticated := api_code_types.CheckAuthenticated(values)
 		values.Del("user")
@@ -24,10 +73,15 @@ func HandleAuthenticated[T any, B any](callback func(r *http.Request, args *T, r
 		reply := new(B)
 		return reply, callback(nil, args, reply, authenticated)
 	}
+	route.Call = handleCall[T, B](callback)
+
+	return route
 }
 
-func Handle[T any, B any](callback func(r *http.Request, args *T, reply *B) error) func(values url.Values) (interface{}, error) {
-	return func(values url.Values) (interface{}, error) {
+func Handle[T any, B any](callback func(r *http.Request, args *T, reply *B) error) *Route {
+
+	route := newRoute[T, B](false)
+	route.Get = func(values url.Values) (interface{}, error) {
 		args := new(T)
 		if err := urldecoder.Decoder.Decode(args, values); err != nil {
 			return nil, err
@@ -36,10 +90,17 @@ func Handle[T any, B any](callback func(r *http.Request, args *T, reply *B) erro
 		reply := new(B)
 		return reply, callback(nil, args, reply)
 	}
+	route.Call = handleCall[T, B](func(r *http.Request, args *T, reply *B, authenticated bool) error {
+		return callback(r, args, reply)
+	})
+
+	return route
 }
 
-func HandlePOSTAuthenticated[T any, B any](callback func(r *http.Request, args *T, reply *B, authenticated bool) error) func(values io.ReadCloser) (interface{}, error) {
-	return func(values io.ReadCloser) (interface{}, error) {
+func HandlePOSTAuthenticated[T any, B any](callback func(r *http.Request, args *T, reply *B, authenticated bool) error) *Route {
+
+	route := newRoute[T, B](true)
+	route.Post = func(values io.ReadCloser) (interface{}, error) {
 
 		authenticated := new(api_code_types.APIAuthenticated[T])
 		if err := json.NewDecoder(values).Decode(authenticated); err != nil {
@@ -49,10 +110,15 @@ func HandlePOSTAuthenticated[T any, B any](callback func(r *http.Request, args *
 		reply := new(B)
 		return reply, callback(nil, authenticated.Data, reply, authenticated.CheckAuthenticated())
 	}
+	route.Call = handleCall[T, B](callback)
+
+	return route
 }
 
-func HandlePOST[T any, B any](callback func(r *http.Request, args *T, reply *B) error) func(values io.ReadCloser) (interface{}, error) {
-	return func(values io.ReadCloser) (interface{}, error) {
+func HandlePOST[T any, B any](callback func(r *http.Request, args *T, reply *B) error) *Route {
+
+	route := newRoute[T, B](false)
+	route.Post = func(values io.ReadCloser) (interface{}, error) {
 		args := new(T)
 
 		if err := json.NewDecoder(values).Decode(args); err != nil {
@@ -62,4 +128,9 @@ func HandlePOST[T any, B any](callback func(r *http.Request, args *T, reply *B)
 		reply := new(B)
 		return reply, callback(nil, args, reply)
 	}
+	route.Call = handleCall[T, B](func(r *http.Request, args *T, reply *B, authenticated bool) error {
+		return callback(r, args, reply)
+	})
+
+	return route
 }
@@ -7,7 +7,7 @@ import (
 
 func CheckAuthenticated(args url.Values) bool {
 
-	user := network_config_auth.CONFIG_AUTH_USERS_MAP[args.Get("user")]
+	user := network_config_auth.GetUser(args.Get("user"))
 	if user == nil {
 		return false
 	}
@@ -22,7 +22,7 @@ type APIAuthenticated[T any] struct {
 }
 
 func (authenticated *APIAuthenticated[T]) CheckAuthenticated() bool {
-	user := network_config_auth.CONFIG_AUTH_USERS_MAP[authenticated.User]
+	user := network_config_auth.GetUser(authenticated.User)
 	if user == nil {
 		return false
 	}
@@ -13,6 +13,10 @@ const (
 	SUBSCRIPTION_ASSET
 	SUBSCRIPTION_REGISTRATION
 	SUBSCRIPTION_TRANSACTION
+	SUBSCRIPTION_BLOCKS
+	SUBSCRIPTION_REORGS
+	SUBSCRIPTION_MEMPOOL
+	SUBSCRIPTION_TX_STATUS
 )
 
 type APISubscriptionNotification struct {
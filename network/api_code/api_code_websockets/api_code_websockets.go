@@ -1,6 +1,7 @@
 package api_code_websockets
 
 import (
+	"errors"
 	"net/http"
 	"pandora-pay/helpers/msgpack"
 	"pandora-pay/helpers/multicast"
@@ -34,6 +35,20 @@ func Handle[T any, B any](callback func(r *http.Request, args *T, reply *B) erro
 	}
 }
 
+//HandleFeature rejects the requests of the peers which didn't negotiate the feature in the handshake
+//The peer can send requests before this node validated its handshake, so the handshake is awaited
+func HandleFeature(feature connection.FeaturesType, callback func(conn *connection.AdvancedConnection, values []byte) (interface{}, error)) func(conn *connection.AdvancedConnection, values []byte) (interface{}, error) {
+	return func(conn *connection.AdvancedConnection, values []byte) (interface{}, error) {
+		if err := conn.WaitInitialized(); err != nil {
+			return nil, err
+		}
+		if !conn.HasFeature(feature) {
+			return nil, errors.New("Feature was not negotiated")
+		}
+		return callback(conn, values)
+	}
+}
+
 func init() {
 	SubscriptionNotifications = multicast.NewMulticastChannel[*api_code_types.APISubscriptionNotification]()
 }
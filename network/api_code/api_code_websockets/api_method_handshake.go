@@ -2,10 +2,34 @@ package api_code_websockets
 
 import (
 	"pandora-pay/config"
+	"pandora-pay/helpers/msgpack"
 	"pandora-pay/network/network_config"
+	"pandora-pay/network/node_identity"
 	"pandora-pay/network/websocks/connection"
 )
 
 func Handshake(conn *connection.AdvancedConnection, values []byte) (interface{}, error) {
-	return &connection.ConnectionHandshake{config.NAME, config.VERSION_STRING, config.NETWORK_SELECTED, config.NODE_CONSENSUS, network_config.NETWORK_WEBSOCKET_ADDRESS_URL_STRING}, nil
+
+	handshake := &connection.ConnectionHandshake{config.NAME, config.VERSION_STRING, config.NETWORK_SELECTED, config.NODE_CONSENSUS, network_config.NETWORK_WEBSOCKET_ADDRESS_URL_STRING, nil, nil, nil, connection.PROTOCOL_VERSION_MIN, connection.PROTOCOL_VERSION, connection.PROTOCOL_FEATURES, network_config.NETWORK_P2P_ADDRESS_URL_STRING}
+
+	//old nodes send no nonce
+	request := &connection.ConnectionHandshakeRequest{}
+	if len(values) > 0 && node_identity.NodeIdentity != nil {
+		if err := msgpack.Unmarshal(values, request); err != nil {
+			return nil, err
+		}
+
+		//the node which requested the handshake is the server when this node is the client of the connection
+		nonce := node_identity.GenerateNonce()
+		signature, err := node_identity.NodeIdentity.SignHandshake(request.Nonce, request.PublicKey, nonce, !conn.ConnectionType)
+		if err != nil {
+			return nil, err
+		}
+
+		handshake.PublicKey = node_identity.NodeIdentity.PublicKey
+		handshake.Nonce = nonce
+		handshake.Signature = signature
+	}
+
+	return handshake, nil
 }
@@ -22,7 +22,7 @@ func Login(conn *connection.AdvancedConnection, values []byte) (interface{}, err
 	}
 	reply := &APILoginReply{}
 
-	user := network_config_auth.CONFIG_AUTH_USERS_MAP[args.Username]
+	user := network_config_auth.GetUser(args.Username)
 	if user == nil || user.Password != args.Password {
 		return reply, nil
 	}
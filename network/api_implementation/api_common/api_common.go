@@ -10,6 +10,7 @@ import (
 	"pandora-pay/helpers/recovery"
 	"pandora-pay/mempool"
 	"pandora-pay/network/api_implementation/api_common/api_delegator_node"
+	"pandora-pay/network/api_implementation/api_common/api_exchange"
 	"pandora-pay/network/api_implementation/api_common/api_faucet"
 	"pandora-pay/wallet"
 	"time"
@@ -24,11 +25,12 @@ type mempoolNewTxReply struct {
 type APICommon struct {
 	mempool                   *mempool.Mempool
 	chain                     *blockchain.Blockchain
-	wallet                    *wallet.Wallet
+	wallets                   *wallet.Wallets
 	localChain                *generics.Value[*APIBlockchain]
 	localChainSync            *generics.Value[*blockchain_sync.BlockchainSyncData]
 	Faucet                    *api_faucet.Faucet
 	DelegatorNode             *api_delegator_node.DelegatorNode
+	Exchange                  *api_exchange.Exchange
 	ApiStore                  *APIStore
 	mempoolProcessedThisBlock *generics.Value[*generics.Map[string, *mempoolNewTxReply]]
 	temporaryList             *generics.Value[*APINetworkNodesReply]
@@ -59,28 +61,36 @@ func (api *APICommon) readLocalBlockchainSync(newLocalSync *blockchain_sync.Bloc
 	api.localChainSync.Store(newLocalSync)
 }
 
-func NewAPICommon(mempool *mempool.Mempool, chain *blockchain.Blockchain, wallet *wallet.Wallet, apiStore *APIStore) (api *APICommon, err error) {
+func NewAPICommon(mempool *mempool.Mempool, chain *blockchain.Blockchain, wallets *wallet.Wallets, apiStore *APIStore) (api *APICommon, err error) {
 
 	var faucet *api_faucet.Faucet
 	if config.NETWORK_SELECTED == config.TEST_NET_NETWORK_BYTE || config.NETWORK_SELECTED == config.DEV_NET_NETWORK_BYTE {
-		if faucet, err = api_faucet.NewFaucet(mempool, chain, wallet); err != nil {
+		if faucet, err = api_faucet.NewFaucet(mempool, chain, wallets.GetDefaultWallet()); err != nil {
 			return
 		}
 	}
 
 	var delegatorNode *api_delegator_node.DelegatorNode
 	if config_nodes.DELEGATOR_ENABLED {
-		delegatorNode = api_delegator_node.NewDelegatorNode(chain, wallet)
+		delegatorNode = api_delegator_node.NewDelegatorNode(chain, wallets.GetDefaultWallet())
+	}
+
+	var exchange *api_exchange.Exchange
+	if config_nodes.EXCHANGE_ENABLED {
+		if exchange, err = api_exchange.NewExchange(mempool, chain, wallets); err != nil {
+			return
+		}
 	}
 
 	api = &APICommon{
 		mempool,
 		chain,
-		wallet,
+		wallets,
 		&generics.Value[*APIBlockchain]{},
 		&generics.Value[*blockchain_sync.BlockchainSyncData]{},
 		faucet,
 		delegatorNode,
+		exchange,
 		apiStore,
 		&generics.Value[*generics.Map[string, *mempoolNewTxReply]]{},
 		&generics.Value[*APINetworkNodesReply]{},
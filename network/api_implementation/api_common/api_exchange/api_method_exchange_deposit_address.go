@@ -0,0 +1,39 @@
+package api_exchange
+
+import (
+	"errors"
+	"net/http"
+	"pandora-pay/helpers"
+)
+
+type APIExchangeDepositAddressRequest struct {
+	AccountId string `json:"accountId" msgpack:"accountId"`
+}
+
+type APIExchangeDepositAddressReply struct {
+	AccountId string         `json:"accountId" msgpack:"accountId"`
+	PaymentID helpers.Base64 `json:"paymentID" msgpack:"paymentID"`
+	Address   string         `json:"address" msgpack:"address"`
+}
+
+func validateAccountId(accountId string) error {
+	if len(accountId) == 0 || len(accountId) > EXCHANGE_ACCOUNT_ID_LIMIT {
+		return errors.New("Invalid Account ID")
+	}
+	return nil
+}
+
+func (api *Exchange) GetExchangeDepositAddress(r *http.Request, args *APIExchangeDepositAddressRequest, reply *APIExchangeDepositAddressReply, authenticated bool) (err error) {
+
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	if err = validateAccountId(args.AccountId); err != nil {
+		return
+	}
+
+	reply.AccountId = args.AccountId
+	reply.PaymentID, reply.Address, err = api.getDepositAddress(args.AccountId)
+	return
+}
@@ -0,0 +1,30 @@
+package api_exchange
+
+import (
+	"errors"
+	"net/http"
+)
+
+type APIExchangeDepositsRequest struct {
+	AccountId string `json:"accountId" msgpack:"accountId"`
+	Start     int    `json:"start,omitempty" msgpack:"start,omitempty"`
+}
+
+type APIExchangeDepositsReply struct {
+	Count    int                `json:"count" msgpack:"count"`
+	Deposits []*ExchangeDeposit `json:"deposits" msgpack:"deposits"`
+}
+
+func (api *Exchange) GetExchangeDeposits(r *http.Request, args *APIExchangeDepositsRequest, reply *APIExchangeDepositsReply, authenticated bool) (err error) {
+
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	if err = validateAccountId(args.AccountId); err != nil {
+		return
+	}
+
+	reply.Deposits, reply.Count, err = api.getDeposits(args.AccountId, args.Start)
+	return
+}
@@ -0,0 +1,45 @@
+package api_exchange
+
+import (
+	"errors"
+	"net/http"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/helpers"
+)
+
+type APIExchangeWithdrawRequest struct {
+	RequestId string         `json:"requestId" msgpack:"requestId"`
+	Address   string         `json:"address" msgpack:"address"`
+	Asset     helpers.Base64 `json:"asset,omitempty" msgpack:"asset,omitempty"`
+	Amount    uint64         `json:"amount" msgpack:"amount"`
+}
+
+func (api *Exchange) ExchangeWithdraw(r *http.Request, args *APIExchangeWithdrawRequest, reply *ExchangeWithdrawal, authenticated bool) error {
+
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	if len(args.RequestId) == 0 || len(args.RequestId) > EXCHANGE_WITHDRAWAL_REQUEST_ID_LIMIT {
+		return errors.New("Invalid Request ID")
+	}
+
+	if args.Amount == 0 {
+		return errors.New("Amount must be positive")
+	}
+
+	if len(args.Asset) == 0 {
+		args.Asset = config_coins.NATIVE_ASSET_FULL
+	}
+	if len(args.Asset) != config_coins.ASSET_LENGTH {
+		return errors.New("Invalid Asset")
+	}
+
+	withdrawal, err := api.addWithdrawal(args.RequestId, args.Address, args.Asset, args.Amount)
+	if err != nil {
+		return err
+	}
+
+	*reply = *withdrawal
+	return nil
+}
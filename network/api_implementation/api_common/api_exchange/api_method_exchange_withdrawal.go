@@ -0,0 +1,28 @@
+package api_exchange
+
+import (
+	"errors"
+	"net/http"
+)
+
+type APIExchangeWithdrawalRequest struct {
+	RequestId string `json:"requestId" msgpack:"requestId"`
+}
+
+func (api *Exchange) GetExchangeWithdrawal(r *http.Request, args *APIExchangeWithdrawalRequest, reply *ExchangeWithdrawal, authenticated bool) error {
+
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	withdrawal, err := api.getWithdrawal(args.RequestId)
+	if err != nil {
+		return err
+	}
+	if withdrawal == nil {
+		return errors.New("Withdrawal was not found")
+	}
+
+	*reply = *withdrawal
+	return nil
+}
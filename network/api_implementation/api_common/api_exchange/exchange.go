@@ -0,0 +1,242 @@
+package api_exchange
+
+import (
+	"errors"
+	"pandora-pay/blockchain"
+	"pandora-pay/blockchain/blockchain_types"
+	"pandora-pay/config/config_nodes"
+	"pandora-pay/gui"
+	"pandora-pay/gui/gui_logger"
+	"pandora-pay/helpers/recovery"
+	"pandora-pay/mempool"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/wallet"
+	"strconv"
+	"sync"
+)
+
+const (
+	EXCHANGE_PAYMENT_ID_LENGTH           = 8
+	EXCHANGE_WITHDRAWAL_MAX_ATTEMPTS     = 10
+	EXCHANGE_WITHDRAWAL_REQUEST_ID_LIMIT = 128
+	EXCHANGE_ACCOUNT_ID_LIMIT            = 128
+	EXCHANGE_DEPOSITS_LIST_LIMIT         = 100
+	EXCHANGE_DEPOSITS_SCAN_MAX_BLOCKS    = 1000 //blocks scanned for deposits per chain update
+)
+
+//Exchange credits the deposits made to the integrated addresses of the accounts and sends the queued withdrawals using the exchange wallet
+type Exchange struct {
+	mempool     *mempool.Mempool
+	chain       *blockchain.Blockchain
+	wallets     *wallet.Wallets
+	walletName  string                         //resolved once, so selecting another wallet doesn't change the exchange wallet
+	deposits    map[string]*ExchangeDeposit    //pending deposits
+	credited    map[string]*ExchangeDeposit    //credited deposits which can still be removed by a reorg until the finality depth
+	withdrawals map[string]*ExchangeWithdrawal //withdrawals which are not confirmed or failed yet
+	notifyCn    chan struct{}
+	lock        sync.Mutex
+}
+
+func (exchange *Exchange) getWallet() (*wallet.Wallet, error) {
+	return exchange.wallets.GetWallet(exchange.walletName)
+}
+
+//resolveWallet pins the name of the exchange wallet. Without --exchange-wallet, the name stored at the first start is used, otherwise the wallet selected at the first start
+func (exchange *Exchange) resolveWallet() error {
+	return store.StoreSettings.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+
+		name := config_nodes.EXCHANGE_WALLET
+		if name == "" {
+			if data := writer.Get("exchange:wallet"); data != nil {
+				name = string(data)
+			} else {
+				name = exchange.wallets.GetSelectedWallet().Name
+			}
+		}
+
+		if name == "" {
+			return errors.New("Exchange wallet can't be resolved. Use --exchange-wallet")
+		}
+
+		exchange.walletName = name
+		writer.Put("exchange:wallet", []byte(name))
+		gui.GUI.Info(gui_logger.SUBSYSTEM_NETWORK, "Exchange wallet", name)
+		return nil
+	})
+}
+
+func (exchange *Exchange) notify() {
+	select {
+	case exchange.notifyCn <- struct{}{}:
+	default:
+	}
+}
+
+func (exchange *Exchange) processChainUpdate(update *blockchain_types.BlockchainUpdates) error {
+
+	start, err := getScannedHeight(update)
+	if err != nil {
+		return err
+	}
+
+	end := update.BlockHeight
+	if end > start+EXCHANGE_DEPOSITS_SCAN_MAX_BLOCKS {
+		end = start + EXCHANGE_DEPOSITS_SCAN_MAX_BLOCKS
+	}
+
+	var newDeposits []*ExchangeDeposit
+
+	//decrypting is done without locking the exchange. The blocks which were not scanned are scanned once the wallet is available
+	w, err := exchange.getWallet()
+	if err != nil {
+		gui.GUI.Error(gui_logger.SUBSYSTEM_NETWORK, "Exchange wallet is not available. Deposits can't be detected", err)
+		end = start
+	} else if newDeposits, err = exchange.getNewDeposits(w, update, start, end); err != nil {
+		gui.GUI.Error(gui_logger.SUBSYSTEM_NETWORK, "Exchange error detecting deposits", err)
+		end = start
+	}
+
+	return exchange.applyChainUpdate(update, newDeposits, end)
+}
+
+//applyChainUpdate updates the deposits and the withdrawals with the deposits detected in the blocks scanned until the scanned height
+func (exchange *Exchange) applyChainUpdate(update *blockchain_types.BlockchainUpdates, newDeposits []*ExchangeDeposit, scannedHeight uint64) error {
+
+	exchange.lock.Lock()
+	defer exchange.lock.Unlock()
+
+	return store.StoreSettings.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		writer.Put("exchange:deposits:scanned", []byte(strconv.FormatUint(scannedHeight, 10)))
+
+		detected := make(map[string]*ExchangeDeposit)
+		for _, deposit := range newDeposits {
+			detected[getDepositKey(deposit.TxHash, deposit.Payload)] = deposit
+		}
+
+		//deposits of the removed blocks are orphaned. In case their txs are included again, they will be detected again
+		//credited deposits of the removed blocks are reversed, unless their txs were included again by the reorg
+		if len(update.RemovedHeights) > 0 {
+			for key, deposit := range exchange.deposits {
+				if deposit.BlockHeight >= update.RemovedHeights[0] {
+					deposit.Status = EXCHANGE_DEPOSIT_ORPHANED
+					deposit.Confirmations = 0
+					if err = saveDeposit(writer, deposit); err != nil {
+						return
+					}
+					delete(exchange.deposits, key)
+				}
+			}
+			for key, deposit := range exchange.credited {
+				if deposit.BlockHeight < update.RemovedHeights[0] {
+					continue
+				}
+				if found := detected[key]; found != nil {
+					deposit.BlockHeight = found.BlockHeight
+				} else {
+					deposit.Status = EXCHANGE_DEPOSIT_REVERSED
+					deposit.Confirmations = 0
+					delete(exchange.credited, key)
+					gui.GUI.Warning(gui_logger.SUBSYSTEM_NETWORK, "Exchange credited deposit was reversed by a reorg", deposit.AccountId, deposit.Amount)
+				}
+				if err = saveDeposit(writer, deposit); err != nil {
+					return
+				}
+			}
+		}
+
+		//a credited deposit detected again is not credited twice
+		for key, deposit := range detected {
+			var stored *ExchangeDeposit
+			if stored, err = getDeposit(writer, key); err != nil {
+				return
+			}
+			if stored == nil || stored.Status != EXCHANGE_DEPOSIT_CREDITED {
+				exchange.deposits[key] = deposit
+			}
+		}
+
+		for key, deposit := range exchange.deposits {
+			if update.BlockHeight > deposit.BlockHeight {
+				deposit.Confirmations = update.BlockHeight - deposit.BlockHeight
+			}
+			if deposit.Confirmations >= config_nodes.EXCHANGE_CONFIRMATIONS {
+				deposit.Status = EXCHANGE_DEPOSIT_CREDITED
+				delete(exchange.deposits, key)
+				exchange.credited[key] = deposit
+				gui.GUI.Info(gui_logger.SUBSYSTEM_NETWORK, "Exchange deposit credited", deposit.AccountId, deposit.Amount)
+			}
+			if err = saveDeposit(writer, deposit); err != nil {
+				return
+			}
+		}
+
+		//reorgs deeper than the finality depth are rejected, so the old credited deposits are final
+		for key, deposit := range exchange.credited {
+			if update.BlockHeight >= deposit.BlockHeight+getStaleDepth() {
+				delete(exchange.credited, key)
+			}
+		}
+
+		if err = exchange.updateWithdrawals(writer, update); err != nil {
+			return
+		}
+
+		return exchange.saveActive(writer)
+	})
+}
+
+func NewExchange(mempool *mempool.Mempool, chain *blockchain.Blockchain, wallets *wallet.Wallets) (*Exchange, error) {
+
+	exchange := &Exchange{
+		mempool,
+		chain,
+		wallets,
+		"",
+		make(map[string]*ExchangeDeposit),
+		make(map[string]*ExchangeDeposit),
+		make(map[string]*ExchangeWithdrawal),
+		make(chan struct{}, 1),
+		sync.Mutex{},
+	}
+
+	if err := exchange.resolveWallet(); err != nil {
+		return nil, err
+	}
+
+	if err := exchange.load(); err != nil {
+		return nil, err
+	}
+
+	recovery.SafeGo(func() {
+
+		updateNewChainUpdateListener := chain.UpdateNewChainUpdate.AddListener()
+		defer chain.UpdateNewChainUpdate.RemoveChannel(updateNewChainUpdateListener)
+
+		for {
+			update, ok := <-updateNewChainUpdateListener
+			if !ok {
+				return
+			}
+
+			if err := exchange.processChainUpdate(update); err != nil {
+				gui.GUI.Error(gui_logger.SUBSYSTEM_NETWORK, "Exchange error processing the chain update", err)
+			}
+			exchange.notify()
+		}
+	})
+
+	recovery.SafeGo(func() {
+		for {
+			if _, ok := <-exchange.notifyCn; !ok {
+				return
+			}
+			exchange.processWithdrawals()
+		}
+	})
+
+	exchange.notify()
+
+	return exchange, nil
+}
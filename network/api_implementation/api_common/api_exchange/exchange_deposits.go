@@ -0,0 +1,254 @@
+package api_exchange
+
+import (
+	"errors"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/blockchain_types"
+	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/blockchain/transactions/transaction/transaction_type"
+	"pandora-pay/helpers"
+	"pandora-pay/helpers/advanced_buffers"
+	"pandora-pay/helpers/msgpack"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/wallet"
+	"strconv"
+)
+
+//getPaymentID returns the payment id of the account. A new unique one is generated the first time
+func getPaymentID(accountId string) (paymentID []byte, err error) {
+
+	err = store.StoreSettings.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+
+		if paymentID = writer.Get("exchange:account:" + accountId); paymentID != nil {
+			return nil
+		}
+
+		for {
+			paymentID = helpers.RandomBytes(EXCHANGE_PAYMENT_ID_LENGTH)
+			if !writer.Exists("exchange:paymentID:" + string(paymentID)) {
+				break
+			}
+		}
+
+		writer.Put("exchange:account:"+accountId, paymentID)
+		writer.Put("exchange:paymentID:"+string(paymentID), []byte(accountId))
+		return nil
+	})
+
+	return
+}
+
+//getDepositAddress integrates the payment id of the account in the first address of the exchange wallet
+func (exchange *Exchange) getDepositAddress(accountId string) ([]byte, string, error) {
+
+	w, err := exchange.getWallet()
+	if err != nil {
+		return nil, "", err
+	}
+
+	walletAddr, err := w.GetWalletAddress(0, true)
+	if err != nil {
+		return nil, "", err
+	}
+
+	paymentID, err := getPaymentID(accountId)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var addr *addresses.Address
+	if err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		dataStorage := data_storage.NewDataStorage(reader)
+
+		var isReg bool
+		if isReg, err = dataStorage.Regs.Exists(string(walletAddr.PublicKey)); err != nil {
+			return
+		}
+
+		if !isReg {
+			addr, err = walletAddr.PrivateKey.GenerateAddress(walletAddr.Staked, walletAddr.SpendPublicKey, true, paymentID, 0, nil)
+		} else {
+			addr, err = walletAddr.PrivateKey.GenerateAddress(false, nil, false, paymentID, 0, nil)
+		}
+		return
+	}); err != nil {
+		return nil, "", err
+	}
+
+	return paymentID, addr.EncodeAddr(), nil
+}
+
+//loadBlockTxs reads the txs of the block at the height from the blockchain store
+func loadBlockTxs(height uint64) (txs []*transaction.Transaction, err error) {
+	err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		data := reader.Get("blockTxs" + strconv.FormatUint(height, 10))
+		if data == nil {
+			return errors.New("Block txs were not found")
+		}
+
+		txHashes := [][]byte{}
+		if err = msgpack.Unmarshal(data, &txHashes); err != nil {
+			return
+		}
+
+		txs = make([]*transaction.Transaction, len(txHashes))
+		for i, txHash := range txHashes {
+			if data = reader.Get("tx:" + string(txHash)); data == nil {
+				return errors.New("Tx was not found")
+			}
+			txs[i] = &transaction.Transaction{}
+			if err = txs[i].Deserialize(advanced_buffers.NewBufferReader(helpers.CloneBytes(data))); err != nil {
+				return
+			}
+			if err = txs[i].BloomAll(); err != nil {
+				return
+			}
+		}
+		return
+	})
+	return
+}
+
+//getScannedHeight returns the first block which was not scanned for deposits. The blocks removed by a reorg are scanned again
+func getScannedHeight(update *blockchain_types.BlockchainUpdates) (height uint64, err error) {
+	err = store.StoreSettings.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		if data := reader.Get("exchange:deposits:scanned"); data != nil {
+			if height, err = strconv.ParseUint(string(data), 10, 64); err != nil {
+				return
+			}
+		} else {
+			//at the first start only the new blocks are scanned
+			height = update.BlockHeight
+			for _, blkComplete := range update.InsertedBlocks {
+				if blkComplete.Block.Height < height {
+					height = blkComplete.Block.Height
+				}
+			}
+		}
+
+		if len(update.RemovedHeights) > 0 && update.RemovedHeights[0] < height {
+			height = update.RemovedHeights[0]
+		}
+		return
+	})
+	return
+}
+
+//getNewDeposits decrypts the payloads received by the exchange address in the blocks [start, end). The payment id is the beginning of the message
+//The inserted blocks are used when available, the others are read from the store. Any error stops the scan, so the blocks are scanned again with the next update
+func (exchange *Exchange) getNewDeposits(w *wallet.Wallet, update *blockchain_types.BlockchainUpdates, start, end uint64) ([]*ExchangeDeposit, error) {
+
+	if start >= end {
+		return nil, nil
+	}
+
+	walletAddr, err := w.GetWalletAddress(0, true)
+	if err != nil {
+		return nil, err
+	}
+
+	inserted := make(map[uint64][]*transaction.Transaction)
+	for _, blkComplete := range update.InsertedBlocks {
+		inserted[blkComplete.Block.Height] = blkComplete.Txs
+	}
+
+	deposits := make([]*ExchangeDeposit, 0)
+
+	if err = store.StoreSettings.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		for height := start; height < end; height++ {
+
+			txs, ok := inserted[height]
+			if !ok {
+				if txs, err = loadBlockTxs(height); err != nil {
+					return
+				}
+			}
+
+			for _, tx := range txs {
+
+				if tx.Version != transaction_type.TX_ZETHER || !tx.GetAllKeys()[string(walletAddr.PublicKey)] {
+					continue
+				}
+
+				var decrypted *wallet.DecryptedTx
+				if decrypted, err = w.DecryptTx(tx, walletAddr.PublicKey); err != nil {
+					return
+				}
+				if decrypted.ZetherTx == nil {
+					continue
+				}
+
+				for t, payload := range decrypted.ZetherTx.Payloads {
+					if payload == nil || !payload.WhisperRecipientValid || payload.ReceivedAmount == 0 || len(payload.Message) < EXCHANGE_PAYMENT_ID_LENGTH {
+						continue
+					}
+
+					paymentID := payload.Message[:EXCHANGE_PAYMENT_ID_LENGTH]
+
+					accountId := reader.Get("exchange:paymentID:" + string(paymentID))
+					if accountId == nil {
+						continue
+					}
+
+					deposits = append(deposits, &ExchangeDeposit{
+						string(accountId),
+						helpers.CloneBytes(paymentID),
+						tx.Bloom.Hash,
+						t,
+						payload.Asset,
+						payload.ReceivedAmount,
+						height,
+						0,
+						EXCHANGE_DEPOSIT_PENDING,
+					})
+				}
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return deposits, nil
+}
+
+func (exchange *Exchange) getDeposits(accountId string, start int) (list []*ExchangeDeposit, count int, err error) {
+
+	err = store.StoreSettings.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		if count, err = getAccountDepositsCount(reader, accountId); err != nil {
+			return
+		}
+
+		if start < 0 || (start >= count && count > 0) {
+			return errors.New("Invalid start")
+		}
+
+		end := start + EXCHANGE_DEPOSITS_LIST_LIMIT
+		if end > count {
+			end = count
+		}
+
+		list = make([]*ExchangeDeposit, 0, end-start)
+		for i := start; i < end; i++ {
+			var deposit *ExchangeDeposit
+			if deposit, err = getDeposit(reader, string(reader.Get("exchange:account:deposit:"+accountId+":"+strconv.Itoa(i)))); err != nil {
+				return
+			}
+			if deposit != nil {
+				list = append(list, deposit)
+			}
+		}
+
+		return
+	})
+
+	return
+}
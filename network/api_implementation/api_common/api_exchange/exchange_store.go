@@ -0,0 +1,184 @@
+package api_exchange
+
+import (
+	"errors"
+	"pandora-pay/gui"
+	"pandora-pay/gui/gui_logger"
+	"pandora-pay/helpers/msgpack"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"strconv"
+)
+
+//only the pending deposits, the credited deposits which are not final and the active withdrawals are kept in memory. The others are read from the settings store
+
+func getDepositKey(txHash []byte, payload int) string {
+	return "exchange:deposit:" + string(txHash) + ":" + strconv.Itoa(payload)
+}
+
+func getDeposit(reader store_db_interface.StoreDBTransactionInterface, key string) (*ExchangeDeposit, error) {
+	data := reader.Get(key)
+	if data == nil {
+		return nil, nil
+	}
+	deposit := &ExchangeDeposit{}
+	if err := msgpack.Unmarshal(data, deposit); err != nil {
+		return nil, err
+	}
+	return deposit, nil
+}
+
+func getWithdrawal(reader store_db_interface.StoreDBTransactionInterface, requestId string) (*ExchangeWithdrawal, error) {
+	data := reader.Get("exchange:withdrawal:" + requestId)
+	if data == nil {
+		return nil, nil
+	}
+	withdrawal := &ExchangeWithdrawal{}
+	if err := msgpack.Unmarshal(data, withdrawal); err != nil {
+		return nil, err
+	}
+	return withdrawal, nil
+}
+
+func getAccountDepositsCount(reader store_db_interface.StoreDBTransactionInterface, accountId string) (count int, err error) {
+	data := reader.Get("exchange:account:deposits:" + accountId)
+	if data == nil {
+		return 0, nil
+	}
+	return strconv.Atoi(string(data))
+}
+
+//saveDeposit stores the deposit and indexes it for the account the first time it is seen. A credited deposit can only be reversed
+func saveDeposit(writer store_db_interface.StoreDBTransactionInterface, deposit *ExchangeDeposit) error {
+
+	key := getDepositKey(deposit.TxHash, deposit.Payload)
+
+	stored, err := getDeposit(writer, key)
+	if err != nil {
+		return err
+	}
+
+	if stored != nil && stored.Status == EXCHANGE_DEPOSIT_CREDITED && deposit.Status != EXCHANGE_DEPOSIT_CREDITED && deposit.Status != EXCHANGE_DEPOSIT_REVERSED {
+		return errors.New("Credited deposit can't be downgraded")
+	}
+
+	if stored == nil {
+		count, err := getAccountDepositsCount(writer, deposit.AccountId)
+		if err != nil {
+			return err
+		}
+		writer.Put("exchange:account:deposit:"+deposit.AccountId+":"+strconv.Itoa(count), []byte(key))
+		writer.Put("exchange:account:deposits:"+deposit.AccountId, []byte(strconv.Itoa(count+1)))
+	}
+
+	marshal, err := msgpack.Marshal(deposit)
+	if err != nil {
+		return err
+	}
+	writer.Put(key, marshal)
+	return nil
+}
+
+func saveWithdrawal(writer store_db_interface.StoreDBTransactionInterface, withdrawal *ExchangeWithdrawal) error {
+	marshal, err := msgpack.Marshal(withdrawal)
+	if err != nil {
+		return err
+	}
+	writer.Put("exchange:withdrawal:"+withdrawal.RequestId, marshal)
+	return nil
+}
+
+func (exchange *Exchange) saveActive(writer store_db_interface.StoreDBTransactionInterface) error {
+
+	deposits := make([]string, 0, len(exchange.deposits))
+	for key := range exchange.deposits {
+		deposits = append(deposits, key)
+	}
+
+	credited := make([]string, 0, len(exchange.credited))
+	for key := range exchange.credited {
+		credited = append(credited, key)
+	}
+
+	withdrawals := make([]string, 0, len(exchange.withdrawals))
+	for requestId := range exchange.withdrawals {
+		withdrawals = append(withdrawals, requestId)
+	}
+
+	marshal, err := msgpack.Marshal(deposits)
+	if err != nil {
+		return err
+	}
+	writer.Put("exchange:deposits:pending", marshal)
+
+	if marshal, err = msgpack.Marshal(credited); err != nil {
+		return err
+	}
+	writer.Put("exchange:deposits:credited", marshal)
+
+	if marshal, err = msgpack.Marshal(withdrawals); err != nil {
+		return err
+	}
+	writer.Put("exchange:withdrawals:active", marshal)
+
+	return nil
+}
+
+func (exchange *Exchange) load() error {
+	return store.StoreSettings.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		var deposits, credited, withdrawals []string
+
+		if data := reader.Get("exchange:deposits:pending"); data != nil {
+			if err = msgpack.Unmarshal(data, &deposits); err != nil {
+				return
+			}
+		}
+		if data := reader.Get("exchange:deposits:credited"); data != nil {
+			if err = msgpack.Unmarshal(data, &credited); err != nil {
+				return
+			}
+		}
+		if data := reader.Get("exchange:withdrawals:active"); data != nil {
+			if err = msgpack.Unmarshal(data, &withdrawals); err != nil {
+				return
+			}
+		}
+
+		for _, key := range deposits {
+			var deposit *ExchangeDeposit
+			if deposit, err = getDeposit(reader, key); err != nil {
+				return
+			}
+			if deposit != nil {
+				exchange.deposits[key] = deposit
+			}
+		}
+
+		for _, key := range credited {
+			var deposit *ExchangeDeposit
+			if deposit, err = getDeposit(reader, key); err != nil {
+				return
+			}
+			if deposit != nil && deposit.Status == EXCHANGE_DEPOSIT_CREDITED {
+				exchange.credited[key] = deposit
+			}
+		}
+
+		for _, requestId := range withdrawals {
+			var withdrawal *ExchangeWithdrawal
+			if withdrawal, err = getWithdrawal(reader, requestId); err != nil {
+				return
+			}
+			if withdrawal != nil {
+				exchange.withdrawals[requestId] = withdrawal
+			}
+		}
+
+		if len(exchange.deposits) > 0 || len(exchange.withdrawals) > 0 {
+			gui.GUI.Log(gui_logger.SUBSYSTEM_NETWORK, "Exchange loaded "+strconv.Itoa(len(exchange.deposits))+" pending deposits and "+strconv.Itoa(len(exchange.withdrawals))+" active withdrawals")
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,306 @@
+package api_exchange
+
+import (
+	"encoding/binary"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/blockchain_types"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/config"
+	"pandora-pay/config/config_nodes"
+	"pandora-pay/gui"
+	"pandora-pay/gui/gui_non_interactive"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/store/store_db/store_db_memory"
+	"pandora-pay/wallet"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+//createTestExchange uses memory stores and a wallet which is not opened, so no deposits are detected from the blocks
+func createTestExchange(t *testing.T) *Exchange {
+
+	gui.GUI, _ = gui_non_interactive.CreateGUINonInteractive(nil)
+
+	settings, _ := store_db_memory.CreateStoreDBMemory("settings")
+	chain, _ := store_db_memory.CreateStoreDBMemory("blockchain")
+	store.StoreSettings = &store.Store{Name: "settings", Opened: true, DB: settings}
+	store.StoreBlockchain = &store.Store{Name: "blockchain", Opened: true, DB: chain}
+
+	config_nodes.EXCHANGE_CONFIRMATIONS = 3
+
+	return &Exchange{
+		nil,
+		nil,
+		&wallet.Wallets{},
+		"exchange",
+		make(map[string]*ExchangeDeposit),
+		make(map[string]*ExchangeDeposit),
+		make(map[string]*ExchangeWithdrawal),
+		make(chan struct{}, 1),
+		sync.Mutex{},
+	}
+}
+
+func includeTestTx(t *testing.T, txHash []byte, blockHeight uint64) {
+	assert.Nil(t, store.StoreBlockchain.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+		buf := make([]byte, binary.MaxVarintLen64)
+		writer.Put("txBlock:"+string(txHash), buf[:binary.PutUvarint(buf, blockHeight)])
+		return nil
+	}))
+}
+
+func removeTestTx(t *testing.T, txHash []byte) {
+	assert.Nil(t, store.StoreBlockchain.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+		writer.Delete("txBlock:" + string(txHash))
+		return nil
+	}))
+}
+
+func readTestDeposit(t *testing.T, deposit *ExchangeDeposit) (out *ExchangeDeposit) {
+	assert.Nil(t, store.StoreSettings.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+		out, err = getDeposit(reader, getDepositKey(deposit.TxHash, deposit.Payload))
+		return
+	}))
+	return
+}
+
+func TestDepositConfirmations(t *testing.T) {
+
+	exchange := createTestExchange(t)
+
+	deposit := &ExchangeDeposit{AccountId: "account", TxHash: []byte("tx"), Amount: 100, BlockHeight: 10, Status: EXCHANGE_DEPOSIT_PENDING}
+	exchange.deposits[getDepositKey(deposit.TxHash, deposit.Payload)] = deposit
+
+	assert.Nil(t, exchange.processChainUpdate(&blockchain_types.BlockchainUpdates{BlockHeight: 10}))
+	assert.Equal(t, uint64(0), readTestDeposit(t, deposit).Confirmations)
+
+	assert.Nil(t, exchange.processChainUpdate(&blockchain_types.BlockchainUpdates{BlockHeight: 12}))
+	stored := readTestDeposit(t, deposit)
+	assert.Equal(t, uint64(2), stored.Confirmations)
+	assert.Equal(t, EXCHANGE_DEPOSIT_PENDING, stored.Status)
+	assert.Equal(t, 1, len(exchange.deposits))
+
+	assert.Nil(t, exchange.processChainUpdate(&blockchain_types.BlockchainUpdates{BlockHeight: 13}))
+	stored = readTestDeposit(t, deposit)
+	assert.Equal(t, uint64(3), stored.Confirmations)
+	assert.Equal(t, EXCHANGE_DEPOSIT_CREDITED, stored.Status)
+	assert.Equal(t, 0, len(exchange.deposits))
+
+	//the deposits of the account are indexed only once
+	assert.Nil(t, store.StoreSettings.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+		count, err := getAccountDepositsCount(reader, "account")
+		assert.Equal(t, 1, count)
+		return err
+	}))
+}
+
+func TestDepositReorg(t *testing.T) {
+
+	exchange := createTestExchange(t)
+
+	orphaned := &ExchangeDeposit{AccountId: "account", TxHash: []byte("tx1"), Amount: 100, BlockHeight: 10, Status: EXCHANGE_DEPOSIT_PENDING}
+	kept := &ExchangeDeposit{AccountId: "account", TxHash: []byte("tx2"), Amount: 200, BlockHeight: 9, Status: EXCHANGE_DEPOSIT_PENDING}
+	exchange.deposits[getDepositKey(orphaned.TxHash, orphaned.Payload)] = orphaned
+	exchange.deposits[getDepositKey(kept.TxHash, kept.Payload)] = kept
+
+	assert.Nil(t, exchange.processChainUpdate(&blockchain_types.BlockchainUpdates{BlockHeight: 11}))
+	assert.Equal(t, uint64(1), readTestDeposit(t, orphaned).Confirmations)
+
+	//the blocks 10 and 11 are replaced by a single block
+	assert.Nil(t, exchange.processChainUpdate(&blockchain_types.BlockchainUpdates{BlockHeight: 10, RemovedHeights: []uint64{10, 11}}))
+
+	stored := readTestDeposit(t, orphaned)
+	assert.Equal(t, EXCHANGE_DEPOSIT_ORPHANED, stored.Status)
+	assert.Equal(t, uint64(0), stored.Confirmations)
+
+	stored = readTestDeposit(t, kept)
+	assert.Equal(t, EXCHANGE_DEPOSIT_PENDING, stored.Status)
+	assert.Equal(t, uint64(1), stored.Confirmations)
+
+	assert.Equal(t, 1, len(exchange.deposits))
+	assert.NotNil(t, exchange.deposits[getDepositKey(kept.TxHash, kept.Payload)])
+}
+
+func TestDepositDeepReorg(t *testing.T) {
+
+	exchange := createTestExchange(t)
+	config.FINALITY_DEPTH = 100
+
+	deposit := &ExchangeDeposit{AccountId: "account", TxHash: []byte("tx"), Amount: 100, BlockHeight: 10, Status: EXCHANGE_DEPOSIT_PENDING}
+	key := getDepositKey(deposit.TxHash, deposit.Payload)
+	detected := func(blockHeight uint64) []*ExchangeDeposit {
+		return []*ExchangeDeposit{{AccountId: "account", TxHash: []byte("tx"), Amount: 100, BlockHeight: blockHeight, Status: EXCHANGE_DEPOSIT_PENDING}}
+	}
+
+	assert.Nil(t, exchange.applyChainUpdate(&blockchain_types.BlockchainUpdates{BlockHeight: 10}, []*ExchangeDeposit{deposit}, 10))
+	assert.Nil(t, exchange.applyChainUpdate(&blockchain_types.BlockchainUpdates{BlockHeight: 13}, nil, 13))
+	assert.Equal(t, EXCHANGE_DEPOSIT_CREDITED, readTestDeposit(t, deposit).Status)
+	assert.NotNil(t, exchange.credited[key])
+
+	//a credited deposit detected again is not credited twice
+	assert.Nil(t, exchange.applyChainUpdate(&blockchain_types.BlockchainUpdates{BlockHeight: 14}, detected(10), 14))
+	assert.Equal(t, EXCHANGE_DEPOSIT_CREDITED, readTestDeposit(t, deposit).Status)
+	assert.Equal(t, 0, len(exchange.deposits))
+
+	//a reorg deeper than the confirmations includes the tx again in another block. The deposit stays credited
+	assert.Nil(t, exchange.applyChainUpdate(&blockchain_types.BlockchainUpdates{BlockHeight: 12, RemovedHeights: []uint64{9, 10, 11, 12, 13, 14}}, detected(11), 12))
+	stored := readTestDeposit(t, deposit)
+	assert.Equal(t, EXCHANGE_DEPOSIT_CREDITED, stored.Status)
+	assert.Equal(t, uint64(11), stored.BlockHeight)
+	assert.Equal(t, 0, len(exchange.deposits))
+
+	//a reorg removing the tx reverses the credited deposit
+	assert.Nil(t, exchange.applyChainUpdate(&blockchain_types.BlockchainUpdates{BlockHeight: 13, RemovedHeights: []uint64{11, 12}}, nil, 13))
+	stored = readTestDeposit(t, deposit)
+	assert.Equal(t, EXCHANGE_DEPOSIT_REVERSED, stored.Status)
+	assert.Equal(t, uint64(0), stored.Confirmations)
+	assert.Nil(t, exchange.credited[key])
+
+	//the reversed deposit is credited again once its tx is included again
+	assert.Nil(t, exchange.applyChainUpdate(&blockchain_types.BlockchainUpdates{BlockHeight: 14}, detected(14), 14))
+	assert.Equal(t, EXCHANGE_DEPOSIT_PENDING, readTestDeposit(t, deposit).Status)
+	assert.Nil(t, exchange.applyChainUpdate(&blockchain_types.BlockchainUpdates{BlockHeight: 17}, nil, 17))
+	assert.Equal(t, EXCHANGE_DEPOSIT_CREDITED, readTestDeposit(t, deposit).Status)
+
+	//after the finality depth the credited deposit is not tracked anymore
+	assert.Nil(t, exchange.applyChainUpdate(&blockchain_types.BlockchainUpdates{BlockHeight: 114}, nil, 114))
+	assert.Equal(t, 0, len(exchange.credited))
+
+	//the stored credited deposit can't be downgraded
+	assert.Nil(t, store.StoreSettings.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+		assert.NotNil(t, saveDeposit(writer, &ExchangeDeposit{AccountId: "account", TxHash: []byte("tx"), Status: EXCHANGE_DEPOSIT_PENDING}))
+		return nil
+	}))
+}
+
+func readTestScannedHeight(t *testing.T) (height uint64) {
+	assert.Nil(t, store.StoreSettings.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+		height, err = strconv.ParseUint(string(reader.Get("exchange:deposits:scanned")), 10, 64)
+		return
+	}))
+	return
+}
+
+func TestDepositsRescan(t *testing.T) {
+
+	exchange := createTestExchange(t)
+
+	//at the first start only the new blocks are scanned
+	assert.Nil(t, exchange.processChainUpdate(&blockchain_types.BlockchainUpdates{BlockHeight: 10}))
+	assert.Equal(t, uint64(10), readTestScannedHeight(t))
+
+	//the wallet is not available, so the new blocks are scanned later
+	assert.Nil(t, exchange.processChainUpdate(&blockchain_types.BlockchainUpdates{BlockHeight: 12}))
+	assert.Equal(t, uint64(10), readTestScannedHeight(t))
+
+	start, err := getScannedHeight(&blockchain_types.BlockchainUpdates{BlockHeight: 13})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(10), start)
+
+	//the blocks scanned and removed by a reorg are scanned again
+	assert.Nil(t, exchange.applyChainUpdate(&blockchain_types.BlockchainUpdates{BlockHeight: 13}, nil, 13))
+	start, err = getScannedHeight(&blockchain_types.BlockchainUpdates{BlockHeight: 12, RemovedHeights: []uint64{11, 12}})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(11), start)
+}
+
+func TestWithdrawalsOrder(t *testing.T) {
+
+	exchange := createTestExchange(t)
+
+	addr, err := addresses.GenerateNewPrivateKey().GenerateAddress(false, nil, false, nil, 0, nil)
+	assert.Nil(t, err)
+
+	//the withdrawals created in the same second are ordered by sequence
+	list := make([]*ExchangeWithdrawal, 0)
+	for i := 0; i < 20; i++ {
+		withdrawal, err := exchange.addWithdrawal("request"+strconv.Itoa(i), addr.EncodeAddr(), nil, 100)
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(i), withdrawal.Sequence)
+		withdrawal.Created = 1
+		list = append(list, withdrawal)
+	}
+
+	for i := 0; i < 5; i++ {
+		rand.Shuffle(len(list), func(a, b int) {
+			list[a], list[b] = list[b], list[a]
+		})
+		sortWithdrawals(list)
+		for j, withdrawal := range list {
+			assert.Equal(t, "request"+strconv.Itoa(j), withdrawal.RequestId)
+		}
+	}
+}
+
+func TestWithdrawalConfirmed(t *testing.T) {
+
+	exchange := createTestExchange(t)
+	exchange.withdrawals["request"] = &ExchangeWithdrawal{RequestId: "request", Amount: 100, Status: EXCHANGE_WITHDRAWAL_QUEUED}
+
+	tx := &transaction.Transaction{Bloom: &transaction.TransactionBloom{Serialized: []byte("serialized"), Hash: []byte("hash")}}
+	exchange.saveBuiltWithdrawal("request", tx, nil)
+
+	withdrawal, err := exchange.getWithdrawal("request")
+	assert.Nil(t, err)
+	assert.Equal(t, EXCHANGE_WITHDRAWAL_BROADCAST, withdrawal.Status)
+	assert.Equal(t, []byte("hash"), []byte(withdrawal.TxHash))
+	assert.Equal(t, 1, withdrawal.Attempts)
+
+	//the tx is not included yet
+	assert.Nil(t, exchange.processChainUpdate(&blockchain_types.BlockchainUpdates{BlockHeight: 20}))
+	withdrawal, _ = exchange.getWithdrawal("request")
+	assert.Equal(t, EXCHANGE_WITHDRAWAL_BROADCAST, withdrawal.Status)
+
+	includeTestTx(t, tx.Bloom.Hash, 21)
+	assert.Nil(t, exchange.processChainUpdate(&blockchain_types.BlockchainUpdates{BlockHeight: 22}))
+	withdrawal, _ = exchange.getWithdrawal("request")
+	assert.Equal(t, EXCHANGE_WITHDRAWAL_INCLUDED, withdrawal.Status)
+	assert.Equal(t, uint64(21), withdrawal.BlockHeight)
+	assert.Equal(t, uint64(1), withdrawal.Confirmations)
+
+	//a reorg removes the tx
+	removeTestTx(t, tx.Bloom.Hash)
+	assert.Nil(t, exchange.processChainUpdate(&blockchain_types.BlockchainUpdates{BlockHeight: 21, RemovedHeights: []uint64{21, 22}}))
+	withdrawal, _ = exchange.getWithdrawal("request")
+	assert.Equal(t, EXCHANGE_WITHDRAWAL_BROADCAST, withdrawal.Status)
+	assert.Equal(t, uint64(0), withdrawal.Confirmations)
+
+	includeTestTx(t, tx.Bloom.Hash, 22)
+	assert.Nil(t, exchange.processChainUpdate(&blockchain_types.BlockchainUpdates{BlockHeight: 25}))
+	withdrawal, _ = exchange.getWithdrawal("request")
+	assert.Equal(t, EXCHANGE_WITHDRAWAL_CONFIRMED, withdrawal.Status)
+	assert.Nil(t, withdrawal.Tx)
+	assert.Equal(t, 0, len(exchange.withdrawals))
+}
+
+func TestWithdrawalFailed(t *testing.T) {
+
+	exchange := createTestExchange(t)
+	exchange.withdrawals["request"] = &ExchangeWithdrawal{RequestId: "request", Amount: 100, Status: EXCHANGE_WITHDRAWAL_QUEUED}
+
+	for i := 1; i < EXCHANGE_WITHDRAWAL_MAX_ATTEMPTS; i++ {
+		exchange.saveBuiltWithdrawal("request", nil, errors.New("Not enough funds"))
+	}
+
+	withdrawal, err := exchange.getWithdrawal("request")
+	assert.Nil(t, err)
+	assert.Equal(t, EXCHANGE_WITHDRAWAL_QUEUED, withdrawal.Status)
+	assert.Equal(t, "Not enough funds", withdrawal.Error)
+
+	exchange.saveBuiltWithdrawal("request", nil, errors.New("Not enough funds"))
+
+	withdrawal, _ = exchange.getWithdrawal("request")
+	assert.Equal(t, EXCHANGE_WITHDRAWAL_FAILED, withdrawal.Status)
+	assert.Equal(t, EXCHANGE_WITHDRAWAL_MAX_ATTEMPTS, withdrawal.Attempts)
+	assert.Equal(t, 0, len(exchange.withdrawals))
+
+	//a failed withdrawal is not changed anymore
+	exchange.saveBuiltWithdrawal("request", &transaction.Transaction{Bloom: &transaction.TransactionBloom{Hash: []byte("hash")}}, nil)
+	withdrawal, _ = exchange.getWithdrawal("request")
+	assert.Equal(t, EXCHANGE_WITHDRAWAL_FAILED, withdrawal.Status)
+}
@@ -0,0 +1,54 @@
+package api_exchange
+
+import (
+	"pandora-pay/helpers"
+)
+
+type ExchangeDepositStatus string
+
+const (
+	EXCHANGE_DEPOSIT_PENDING  ExchangeDepositStatus = "pending"
+	EXCHANGE_DEPOSIT_CREDITED ExchangeDepositStatus = "credited"
+	EXCHANGE_DEPOSIT_ORPHANED ExchangeDepositStatus = "orphaned"
+	EXCHANGE_DEPOSIT_REVERSED ExchangeDepositStatus = "reversed" //credited deposit removed by a reorg. The amount must be debited from the account
+)
+
+type ExchangeWithdrawalStatus string
+
+const (
+	EXCHANGE_WITHDRAWAL_QUEUED    ExchangeWithdrawalStatus = "queued"
+	EXCHANGE_WITHDRAWAL_BROADCAST ExchangeWithdrawalStatus = "broadcast"
+	EXCHANGE_WITHDRAWAL_INCLUDED  ExchangeWithdrawalStatus = "included"
+	EXCHANGE_WITHDRAWAL_CONFIRMED ExchangeWithdrawalStatus = "confirmed"
+	EXCHANGE_WITHDRAWAL_FAILED    ExchangeWithdrawalStatus = "failed"
+)
+
+type ExchangeDeposit struct {
+	AccountId     string                `json:"accountId" msgpack:"accountId"`
+	PaymentID     helpers.Base64        `json:"paymentID" msgpack:"paymentID"`
+	TxHash        helpers.Base64        `json:"txHash" msgpack:"txHash"`
+	Payload       int                   `json:"payload" msgpack:"payload"`
+	Asset         helpers.Base64        `json:"asset" msgpack:"asset"`
+	Amount        uint64                `json:"amount" msgpack:"amount"`
+	BlockHeight   uint64                `json:"blockHeight" msgpack:"blockHeight"`
+	Confirmations uint64                `json:"confirmations" msgpack:"confirmations"`
+	Status        ExchangeDepositStatus `json:"status" msgpack:"status"`
+}
+
+type ExchangeWithdrawal struct {
+	RequestId        string                   `json:"requestId" msgpack:"requestId"`
+	Address          string                   `json:"address" msgpack:"address"`
+	Asset            helpers.Base64           `json:"asset" msgpack:"asset"`
+	Amount           uint64                   `json:"amount" msgpack:"amount"`
+	Status           ExchangeWithdrawalStatus `json:"status" msgpack:"status"`
+	TxHash           helpers.Base64           `json:"txHash,omitempty" msgpack:"txHash,omitempty"`
+	PreviousTxHashes []helpers.Base64         `json:"previousTxHashes,omitempty" msgpack:"previousTxHashes,omitempty"` //txs dropped and built again
+	BlockHeight      uint64                   `json:"blockHeight,omitempty" msgpack:"blockHeight,omitempty"`
+	Confirmations    uint64                   `json:"confirmations" msgpack:"confirmations"`
+	StaleHeight      uint64                   `json:"staleHeight,omitempty" msgpack:"staleHeight,omitempty"` //height when the tx was found not includable anymore
+	Attempts         int                      `json:"attempts" msgpack:"attempts"`
+	Error            string                   `json:"error,omitempty" msgpack:"error,omitempty"`
+	Created          int64                    `json:"created" msgpack:"created"`
+	Sequence         uint64                   `json:"-" msgpack:"sequence"`     //order of the requests created in the same second
+	Tx               []byte                   `json:"-" msgpack:"tx,omitempty"` //serialized tx used for rebroadcasting
+}
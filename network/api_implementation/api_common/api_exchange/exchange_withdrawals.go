@@ -0,0 +1,424 @@
+package api_exchange
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/blockchain_types"
+	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/blockchain/data_storage/accounts"
+	"pandora-pay/blockchain/data_storage/accounts/account"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether"
+	"pandora-pay/config"
+	"pandora-pay/config/config_nodes"
+	"pandora-pay/cryptography/crypto"
+	"pandora-pay/gui"
+	"pandora-pay/gui/gui_logger"
+	"pandora-pay/helpers/advanced_buffers"
+	"pandora-pay/network/dandelion"
+	"pandora-pay/network/websocks/connection/advanced_connection_types"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/txs_builder"
+	"pandora-pay/txs_builder/txs_builder_zether_helper"
+	"pandora-pay/txs_builder/wizard"
+	"pandora-pay/wallet"
+	"pandora-pay/wallet/wallet_address"
+	"sort"
+	"strconv"
+	"time"
+)
+
+//addWithdrawal is idempotent. The same request id returns the existing withdrawal as long as the parameters are the same
+func (exchange *Exchange) addWithdrawal(requestId, address string, asset []byte, amount uint64) (*ExchangeWithdrawal, error) {
+
+	if _, err := addresses.DecodeAddr(address); err != nil {
+		return nil, err
+	}
+
+	exchange.lock.Lock()
+	defer exchange.lock.Unlock()
+
+	var withdrawal *ExchangeWithdrawal
+
+	if err := store.StoreSettings.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		if withdrawal = exchange.withdrawals[requestId]; withdrawal == nil {
+			if withdrawal, err = getWithdrawal(writer, requestId); err != nil {
+				return
+			}
+		}
+
+		if withdrawal != nil {
+			if withdrawal.Address != address || !bytes.Equal(withdrawal.Asset, asset) || withdrawal.Amount != amount {
+				return errors.New("Request ID was already used with different parameters")
+			}
+			return
+		}
+
+		var sequence uint64
+		if data := writer.Get("exchange:withdrawals:sequence"); data != nil {
+			if sequence, err = strconv.ParseUint(string(data), 10, 64); err != nil {
+				return
+			}
+		}
+		writer.Put("exchange:withdrawals:sequence", []byte(strconv.FormatUint(sequence+1, 10)))
+
+		withdrawal = &ExchangeWithdrawal{
+			RequestId: requestId,
+			Address:   address,
+			Asset:     asset,
+			Amount:    amount,
+			Status:    EXCHANGE_WITHDRAWAL_QUEUED,
+			Created:   time.Now().Unix(),
+			Sequence:  sequence,
+		}
+		exchange.withdrawals[requestId] = withdrawal
+
+		if err = saveWithdrawal(writer, withdrawal); err != nil {
+			return
+		}
+		return exchange.saveActive(writer)
+	}); err != nil {
+		return nil, err
+	}
+
+	exchange.notify()
+
+	out := *withdrawal
+	return &out, nil
+}
+
+func (exchange *Exchange) getWithdrawal(requestId string) (withdrawal *ExchangeWithdrawal, err error) {
+
+	exchange.lock.Lock()
+	defer exchange.lock.Unlock()
+
+	//a copy is returned as the chain updates change the active withdrawals
+	if active := exchange.withdrawals[requestId]; active != nil {
+		out := *active
+		return &out, nil
+	}
+
+	err = store.StoreSettings.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+		withdrawal, err = getWithdrawal(reader, requestId)
+		return
+	})
+	return
+}
+
+//updateWithdrawals updates the confirmations of the broadcast withdrawals. It is called with the lock acquired
+func (exchange *Exchange) updateWithdrawals(writer store_db_interface.StoreDBTransactionInterface, update *blockchain_types.BlockchainUpdates) error {
+
+	return store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		for requestId, withdrawal := range exchange.withdrawals {
+
+			if withdrawal.Status != EXCHANGE_WITHDRAWAL_BROADCAST && withdrawal.Status != EXCHANGE_WITHDRAWAL_INCLUDED {
+				continue
+			}
+
+			//a reorg removes the tx and the withdrawal will be broadcast again
+			data := reader.Get("txBlock:" + string(withdrawal.TxHash))
+			if data == nil {
+				withdrawal.Status = EXCHANGE_WITHDRAWAL_BROADCAST
+				withdrawal.BlockHeight = 0
+				withdrawal.Confirmations = 0
+			} else {
+				withdrawal.Status = EXCHANGE_WITHDRAWAL_INCLUDED
+				withdrawal.BlockHeight, _ = binary.Uvarint(data)
+				if update.BlockHeight > withdrawal.BlockHeight {
+					withdrawal.Confirmations = update.BlockHeight - withdrawal.BlockHeight
+				}
+				if withdrawal.Confirmations >= config_nodes.EXCHANGE_CONFIRMATIONS {
+					withdrawal.Status = EXCHANGE_WITHDRAWAL_CONFIRMED
+					withdrawal.Tx = nil
+					delete(exchange.withdrawals, requestId)
+					gui.GUI.Info(gui_logger.SUBSYSTEM_NETWORK, "Exchange withdrawal confirmed", withdrawal.RequestId)
+				}
+			}
+
+			if err = saveWithdrawal(writer, withdrawal); err != nil {
+				return
+			}
+		}
+
+		return
+	})
+}
+
+//saveWithdrawalChanges applies the changes done by the worker in case the withdrawal was not changed in the meantime
+func (exchange *Exchange) saveWithdrawalChanges(requestId string, status ExchangeWithdrawalStatus, txHash []byte, callback func(withdrawal *ExchangeWithdrawal)) {
+
+	exchange.lock.Lock()
+	defer exchange.lock.Unlock()
+
+	withdrawal := exchange.withdrawals[requestId]
+	if withdrawal == nil || withdrawal.Status != status || !bytes.Equal(withdrawal.TxHash, txHash) {
+		return
+	}
+
+	callback(withdrawal)
+
+	if withdrawal.Status == EXCHANGE_WITHDRAWAL_FAILED {
+		delete(exchange.withdrawals, requestId)
+	}
+
+	if err := store.StoreSettings.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+		if err = saveWithdrawal(writer, withdrawal); err != nil {
+			return
+		}
+		return exchange.saveActive(writer)
+	}); err != nil {
+		gui.GUI.Error(gui_logger.SUBSYSTEM_NETWORK, "Exchange error saving withdrawal", requestId, err)
+	}
+}
+
+func (exchange *Exchange) buildWithdrawal(requestId, address string, asset []byte, amount uint64) {
+
+	w, err := exchange.getWallet()
+	if err != nil {
+		gui.GUI.Error(gui_logger.SUBSYSTEM_NETWORK, "Exchange wallet is not available. Withdrawals can't be sent", err)
+		return
+	}
+
+	sender, err := w.GetWalletAddress(0, true)
+	if err != nil {
+		gui.GUI.Error(gui_logger.SUBSYSTEM_NETWORK, "Exchange error reading the wallet address", err)
+		return
+	}
+
+	//an integrated payment id is sent to the recipient, like exchanges deposits require
+	var data *wizard.WizardTransactionData
+	recipient, err := addresses.DecodeAddr(address)
+	if err == nil && recipient.IsIntegratedPaymentID() {
+		data = &wizard.WizardTransactionData{recipient.PaymentID, true}
+	}
+
+	var tx *transaction.Transaction
+	if err == nil {
+		txData := &txs_builder.TxBuilderCreateZetherTxData{
+			Payloads: []*txs_builder.TxBuilderCreateZetherTxPayload{{
+				txs_builder_zether_helper.TxsBuilderZetherTxPayloadBase{
+					sender.AddressEncoded,
+					address,
+					128,
+					nil,
+				},
+				asset,
+				amount,
+				0,
+				&txs_builder.ZetherRingConfiguration{&txs_builder.ZetherSenderRingType{}, &txs_builder.ZetherRecipientRingType{}},
+				0,
+				data,
+				&wizard.WizardZetherTransactionFee{&wizard.WizardTransactionFee{0, 0, 0, true}, false, 0, 0},
+				nil,
+			}},
+			Wallet: exchange.walletName,
+		}
+
+		tx, err = txs_builder.TxsBuilder.CreateZetherTx(txData, nil, true, true, false, false, context.Background(), func(string) {})
+	}
+
+	exchange.saveBuiltWithdrawal(requestId, tx, err)
+}
+
+//saveBuiltWithdrawal marks the withdrawal as broadcast once the tx was built and added to the mempool. After too many errors the withdrawal fails
+func (exchange *Exchange) saveBuiltWithdrawal(requestId string, tx *transaction.Transaction, err error) {
+	exchange.saveWithdrawalChanges(requestId, EXCHANGE_WITHDRAWAL_QUEUED, nil, func(withdrawal *ExchangeWithdrawal) {
+		withdrawal.Attempts += 1
+		if err != nil {
+			withdrawal.Error = err.Error()
+			if withdrawal.Attempts >= EXCHANGE_WITHDRAWAL_MAX_ATTEMPTS {
+				withdrawal.Status = EXCHANGE_WITHDRAWAL_FAILED
+			}
+			gui.GUI.Error(gui_logger.SUBSYSTEM_NETWORK, "Exchange error sending withdrawal", requestId, err)
+			return
+		}
+		withdrawal.Error = ""
+		withdrawal.Status = EXCHANGE_WITHDRAWAL_BROADCAST
+		withdrawal.TxHash = tx.Bloom.Hash
+		withdrawal.Tx = tx.Bloom.Serialized
+	})
+}
+
+//getStaleDepth returns the blocks the balance of the exchange address must stay moved before an old tx is considered dead. Reorgs deeper than the finality depth are rejected
+func getStaleDepth() uint64 {
+	if config.FINALITY_DEPTH > 0 {
+		return config.FINALITY_DEPTH
+	}
+	return config_nodes.EXCHANGE_CONFIRMATIONS
+}
+
+//senderBalanceMoved returns true when the on chain balance of the sender is not the one the tx was built on, so the tx can't be included on the current chain
+func (exchange *Exchange) senderBalanceMoved(tx *transaction.Transaction, sender []byte) (moved bool, err error) {
+
+	base, ok := tx.TransactionBaseInterface.(*transaction_zether.TransactionZether)
+	if !ok {
+		return false, errors.New("Withdrawal tx is not a zether tx")
+	}
+	if base.Bloom == nil {
+		return true, nil
+	}
+
+	err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		dataStorage := data_storage.NewDataStorage(reader)
+
+		for payloadIndex, payload := range base.Payloads {
+
+			var accs *accounts.Accounts
+			if accs, err = dataStorage.AccsCollection.GetMap(payload.Asset); err != nil {
+				return
+			}
+
+			for i, publicKey := range base.Bloom.PublicKeyLists[payloadIndex] {
+
+				if (i%2 == 0) != payload.Parity || !bytes.Equal(publicKey, sender) {
+					continue
+				}
+
+				var acc *account.Account
+				if acc, err = accs.Get(string(publicKey)); err != nil {
+					return
+				}
+				if acc == nil {
+					moved = true
+					return
+				}
+
+				balance := acc.GetBalance().Add(crypto.ConstructElGamal(payload.Statement.C[i], payload.Statement.D))
+				if payload.Statement.CLn[i].String() != balance.Left.String() || payload.Statement.CRn[i].String() != balance.Right.String() {
+					moved = true
+					return
+				}
+			}
+		}
+
+		return
+	})
+	return
+}
+
+//rebroadcastWithdrawal adds the tx to the mempool again in case it was dropped. A rejected tx is retried with the same bytes as it can still be included
+//The withdrawal is built again only when the tx is dead: the balance of the exchange address moved for more than the stale depth and no older withdrawal is pending, which could bring the balance back
+func (exchange *Exchange) rebroadcastWithdrawal(requestId string, txHash, serialized []byte, olderPending bool) {
+
+	//a tx in the stem phase is not in the mempool until the embargo expires
+	if exchange.mempool.Txs.Exists(string(txHash)) || (dandelion.Dandelion != nil && dandelion.Dandelion.Exists(string(txHash))) {
+		return
+	}
+
+	included, err := exchange.chain.OpenExistsTx(txHash)
+	if err != nil || included {
+		return
+	}
+
+	tx := &transaction.Transaction{}
+	if err = tx.Deserialize(advanced_buffers.NewBufferReader(serialized)); err == nil {
+		if err = tx.BloomAll(); err == nil {
+			err = exchange.mempool.AddTxToMempool(tx, exchange.chain.GetChainData().Height, false, true, false, advanced_connection_types.UUID_ALL, context.Background())
+		}
+	}
+
+	if err == nil {
+		gui.GUI.Info(gui_logger.SUBSYSTEM_NETWORK, "Exchange withdrawal rebroadcast", requestId)
+		exchange.saveWithdrawalChanges(requestId, EXCHANGE_WITHDRAWAL_BROADCAST, txHash, func(withdrawal *ExchangeWithdrawal) {
+			withdrawal.StaleHeight = 0
+			withdrawal.Error = ""
+		})
+		return
+	}
+
+	//a tx that can't be deserialized or bloomed will never be included
+	moved := true
+	if tx.Bloom != nil {
+		var w *wallet.Wallet
+		var sender *wallet_address.WalletAddress
+		if w, err = exchange.getWallet(); err == nil {
+			if sender, err = w.GetWalletAddress(0, true); err == nil {
+				moved, err = exchange.senderBalanceMoved(tx, sender.PublicKey)
+			}
+		}
+		if err != nil {
+			gui.GUI.Error(gui_logger.SUBSYSTEM_NETWORK, "Exchange error checking the withdrawal tx", requestId, err)
+			return
+		}
+	}
+
+	height := exchange.chain.GetChainData().Height
+
+	exchange.saveWithdrawalChanges(requestId, EXCHANGE_WITHDRAWAL_BROADCAST, txHash, func(withdrawal *ExchangeWithdrawal) {
+
+		withdrawal.Error = "Tx was rejected by the mempool"
+
+		if !moved {
+			withdrawal.StaleHeight = 0
+			return
+		}
+
+		if withdrawal.StaleHeight == 0 {
+			withdrawal.StaleHeight = height
+		}
+
+		if olderPending || height < withdrawal.StaleHeight+getStaleDepth() {
+			return
+		}
+
+		gui.GUI.Warning(gui_logger.SUBSYSTEM_NETWORK, "Exchange withdrawal tx can't be included anymore. It will be built again", requestId)
+		withdrawal.PreviousTxHashes = append(withdrawal.PreviousTxHashes, withdrawal.TxHash)
+		withdrawal.Status = EXCHANGE_WITHDRAWAL_QUEUED
+		withdrawal.TxHash = nil
+		withdrawal.Tx = nil
+		withdrawal.StaleHeight = 0
+	})
+}
+
+//sortWithdrawals sorts the withdrawals in the order they were requested
+func sortWithdrawals(list []*ExchangeWithdrawal) {
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Created != list[j].Created {
+			return list[i].Created < list[j].Created
+		}
+		return list[i].Sequence < list[j].Sequence
+	})
+}
+
+//processWithdrawals sends the withdrawals in the order they were requested
+func (exchange *Exchange) processWithdrawals() {
+
+	exchange.lock.Lock()
+	list := make([]*ExchangeWithdrawal, 0, len(exchange.withdrawals))
+	for _, withdrawal := range exchange.withdrawals {
+		if withdrawal.Status == EXCHANGE_WITHDRAWAL_QUEUED || withdrawal.Status == EXCHANGE_WITHDRAWAL_BROADCAST {
+			list = append(list, &ExchangeWithdrawal{
+				RequestId: withdrawal.RequestId,
+				Address:   withdrawal.Address,
+				Asset:     withdrawal.Asset,
+				Amount:    withdrawal.Amount,
+				Status:    withdrawal.Status,
+				TxHash:    withdrawal.TxHash,
+				Created:   withdrawal.Created,
+				Sequence:  withdrawal.Sequence,
+				Tx:        withdrawal.Tx,
+			})
+		}
+	}
+	exchange.lock.Unlock()
+
+	sortWithdrawals(list)
+
+	//the txs are built on top of the pending ones, so an older pending tx can make a newer one valid again
+	olderPending := false
+	for _, withdrawal := range list {
+		if withdrawal.Status == EXCHANGE_WITHDRAWAL_QUEUED {
+			exchange.buildWithdrawal(withdrawal.RequestId, withdrawal.Address, withdrawal.Asset, withdrawal.Amount)
+		} else {
+			exchange.rebroadcastWithdrawal(withdrawal.RequestId, withdrawal.TxHash, withdrawal.Tx, olderPending)
+			olderPending = true
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package api_common
+
+import (
+	"net/http"
+	"pandora-pay/config"
+)
+
+type APIFinalityReply struct {
+	Height        uint64               `json:"height" msgpack:"height"` //finalized height
+	Hash          []byte               `json:"hash" msgpack:"hash"`
+	FinalityDepth uint64               `json:"finalityDepth" msgpack:"finalityDepth"`
+	Checkpoints   []*config.Checkpoint `json:"checkpoints" msgpack:"checkpoints"`
+}
+
+func (api *APICommon) GetFinality(r *http.Request, args *struct{}, reply *APIFinalityReply) (err error) {
+
+	reply.Height = api.chain.GetFinalizedHeight()
+	if reply.Hash, err = api.chain.OpenLoadBlockHash(reply.Height); err != nil {
+		return
+	}
+
+	reply.FinalityDepth = config.FINALITY_DEPTH
+	reply.Checkpoints = config.NETWORK_SELECTED_CHECKPOINTS
+	return
+}
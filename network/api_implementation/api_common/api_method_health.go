@@ -0,0 +1,109 @@
+package api_common
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"pandora-pay/app"
+	"pandora-pay/config"
+	"pandora-pay/config/config_forging"
+	"pandora-pay/network/websocks"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"time"
+)
+
+//the node is not ready if the last block is older than HEALTH_MAX_BLOCK_AGE_MULTIPLIER * BLOCK_TIME
+const HEALTH_MAX_BLOCK_AGE_MULTIPLIER = 10
+
+const HEALTH_STORE_TIMEOUT = 2 * time.Second
+
+type APIHealthCheck struct {
+	Name    string `json:"name" msgpack:"name"`
+	Status  bool   `json:"status" msgpack:"status"`
+	Message string `json:"message,omitempty" msgpack:"message,omitempty"`
+}
+
+type APIHealthReply struct {
+	Status bool              `json:"status" msgpack:"status"`
+	Checks []*APIHealthCheck `json:"checks,omitempty" msgpack:"checks,omitempty"`
+}
+
+//GetHTTPStatus is used by the HTTP server to answer 503 when the node is not healthy
+func (reply *APIHealthReply) GetHTTPStatus() int {
+	if reply.Status {
+		return http.StatusOK
+	}
+	return http.StatusServiceUnavailable
+}
+
+func (reply *APIHealthReply) addCheck(name string, status bool, message string) {
+	reply.Checks = append(reply.Checks, &APIHealthCheck{name, status, message})
+	reply.Status = reply.Status && status
+}
+
+func (api *APICommon) checkStoreHealth() error {
+
+	done := make(chan error, 1)
+	go func() {
+		done <- store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+			reader.Get("chainHash")
+			return nil
+		})
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(HEALTH_STORE_TIMEOUT):
+		return errors.New("Store didn't answer in time")
+	}
+}
+
+//GetHealthLive answers as long as the node is running
+func (api *APICommon) GetHealthLive(r *http.Request, args *struct{}, reply *APIHealthReply) error {
+	reply.Status = true
+	return nil
+}
+
+//GetHealthReady verifies that the node is synchronized and able to serve requests
+func (api *APICommon) GetHealthReady(r *http.Request, args *struct{}, reply *APIHealthReply) error {
+
+	reply.Status = true
+
+	syncData := api.chain.Sync.GetSyncData()
+	if syncData.Sync {
+		reply.addCheck("sync", true, "")
+	} else {
+		reply.addCheck("sync", false, fmt.Sprintf("Not synchronized. Blocks changed last interval %d", syncData.BlocksChangedLastInterval))
+	}
+
+	if websocks.Websockets != nil && websocks.Websockets.ReadyCnClosed.IsSet() {
+		reply.addCheck("network", true, "")
+	} else {
+		reply.addCheck("network", false, "Not enough connections")
+	}
+
+	chainData := api.chain.GetChainData()
+	if now := uint64(time.Now().Unix()); chainData.Timestamp+HEALTH_MAX_BLOCK_AGE_MULTIPLIER*config.BLOCK_TIME < now {
+		reply.addCheck("last-block", false, fmt.Sprintf("Last block %d is %d seconds old", chainData.Height, now-chainData.Timestamp))
+	} else {
+		reply.addCheck("last-block", true, "")
+	}
+
+	if err := api.checkStoreHealth(); err != nil {
+		reply.addCheck("store", false, err.Error())
+	} else {
+		reply.addCheck("store", true, "")
+	}
+
+	if !config_forging.FORGING_ENABLED {
+		reply.addCheck("forging", true, "Disabled")
+	} else if app.Forging != nil && app.Forging.IsForging() {
+		reply.addCheck("forging", true, "")
+	} else {
+		reply.addCheck("forging", false, "Forging is enabled but it is not running")
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package api_common
+
+import (
+	"bytes"
+	"net/http"
+	"pandora-pay/helpers"
+	"pandora-pay/mempool"
+)
+
+type APIMempoolReconcileRequest struct {
+	Digest helpers.Base64            `json:"digest" msgpack:"digest"`
+	Count  int                       `json:"count" msgpack:"count"`
+	Filter *mempool.MempoolTxsFilter `json:"filter,omitempty" msgpack:"filter,omitempty"`
+}
+
+type APIMempoolReconcileReply struct {
+	Synced bool     `json:"synced" msgpack:"synced"`
+	Count  int      `json:"count" msgpack:"count"`
+	Hashes [][]byte `json:"hashes,omitempty" msgpack:"hashes,omitempty"` //txs which are missing from the filter
+}
+
+//GetMempoolReconcile compares the digest of the mempools. In case they are different, the peer sends the filter of its mempool to get the missing txs
+func (api *APICommon) GetMempoolReconcile(r *http.Request, args *APIMempoolReconcileRequest, reply *APIMempoolReconcileReply) error {
+
+	digest, count := api.mempool.Txs.GetDigest()
+
+	reply.Count = count
+	if count == args.Count && bytes.Equal(digest, args.Digest) {
+		reply.Synced = true
+		return nil
+	}
+
+	if args.Filter == nil || count == 0 {
+		return nil
+	}
+
+	if err := args.Filter.Validate(); err != nil {
+		return err
+	}
+
+	reply.Hashes = api.mempool.Txs.GetMissingTxs(args.Filter)
+	return nil
+}
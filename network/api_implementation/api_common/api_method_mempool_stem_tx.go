@@ -0,0 +1,58 @@
+package api_common
+
+import (
+	"context"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/cryptography"
+	"pandora-pay/helpers/advanced_buffers"
+	"pandora-pay/helpers/msgpack"
+	"pandora-pay/network/dandelion"
+	"pandora-pay/network/websocks/connection"
+	"pandora-pay/network/websocks/connection/advanced_connection_types"
+)
+
+//MempoolStemTx receives a tx in the stem phase. The tx is relayed further or it is diffused
+func (api *APICommon) MempoolStemTx(conn *connection.AdvancedConnection, values []byte) (interface{}, error) {
+
+	args := &dandelion.APIMempoolStemTxRequest{}
+	if err := msgpack.Unmarshal(values, args); err != nil {
+		return nil, err
+	}
+
+	reply := &APIMempoolNewTxReply{true}
+
+	//the duplicates are detected before validating the tx
+	hashStr := string(cryptography.SHA3(args.Tx))
+	if api.mempool.Txs.Exists(hashStr) {
+		return reply, nil
+	}
+	if dandelion.Dandelion != nil && dandelion.Dandelion.Loop(hashStr) {
+		return reply, nil
+	}
+
+	tx := &transaction.Transaction{}
+	if err := tx.Deserialize(advanced_buffers.NewBufferReader(args.Tx)); err != nil {
+		return nil, err
+	}
+
+	height := api.chain.GetChainData().Height
+	if err := api.mempool.ValidateTx(tx, height); err != nil {
+		return nil, err
+	}
+
+	if dandelion.Dandelion != nil {
+		stemmed, err := dandelion.Dandelion.Stem(tx, conn.UUID)
+		if err != nil {
+			return nil, err
+		}
+		if stemmed {
+			return reply, nil
+		}
+	}
+
+	if err := api.mempool.AddTxToMempool(tx, height, false, true, false, advanced_connection_types.UUID_ALL, context.Background()); err != nil {
+		return nil, err
+	}
+
+	return reply, nil
+}
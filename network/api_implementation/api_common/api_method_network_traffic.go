@@ -0,0 +1,64 @@
+package api_common
+
+import (
+	"errors"
+	"net/http"
+	"pandora-pay/network/bandwidth"
+	"pandora-pay/network/connected_nodes"
+	"pandora-pay/network/network_config"
+	"pandora-pay/network/websocks/connection"
+	"sync/atomic"
+)
+
+type APINetworkTrafficConnection struct {
+	RemoteAddr string                                        `json:"remoteAddr" msgpack:"remoteAddr"`
+	URL        string                                        `json:"url,omitempty" msgpack:"url,omitempty"`
+	NodeId     string                                        `json:"nodeId,omitempty" msgpack:"nodeId,omitempty"`
+	Server     bool                                          `json:"server" msgpack:"server"` //accepted by the node
+	Sent       uint64                                        `json:"sent" msgpack:"sent"`
+	Received   uint64                                        `json:"received" msgpack:"received"`
+	Routes     map[string]*connection.ConnectionRouteTraffic `json:"routes" msgpack:"routes"`
+}
+
+type APINetworkTrafficReply struct {
+	UploadLimit     int64                          `json:"uploadLimit" msgpack:"uploadLimit"` //bytes per second. 0 is unlimited
+	DownloadLimit   int64                          `json:"downloadLimit" msgpack:"downloadLimit"`
+	PeerUploadLimit int64                          `json:"peerUploadLimit" msgpack:"peerUploadLimit"`
+	Sent            map[string]uint64              `json:"sent" msgpack:"sent"` //by priority
+	Received        map[string]uint64              `json:"received" msgpack:"received"`
+	Connections     []*APINetworkTrafficConnection `json:"connections" msgpack:"connections"`
+}
+
+func (api *APICommon) GetNetworkTraffic(r *http.Request, args *struct{}, reply *APINetworkTrafficReply, authenticated bool) error {
+
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	reply.UploadLimit = atomic.LoadInt64(&network_config.NETWORK_UPLOAD_LIMIT)
+	reply.DownloadLimit = atomic.LoadInt64(&network_config.NETWORK_DOWNLOAD_LIMIT)
+	reply.PeerUploadLimit = atomic.LoadInt64(&network_config.NETWORK_PEER_UPLOAD_LIMIT)
+	reply.Sent, reply.Received = bandwidth.Bandwidth.GetTraffic()
+
+	list := connected_nodes.ConnectedNodes.AllList.Get()
+	reply.Connections = make([]*APINetworkTrafficConnection, len(list))
+	for i, conn := range list {
+
+		url := ""
+		if conn.KnownNode != nil {
+			url = conn.KnownNode.URL
+		}
+
+		reply.Connections[i] = &APINetworkTrafficConnection{
+			conn.RemoteAddr,
+			url,
+			conn.NodeId,
+			conn.ConnectionType,
+			conn.Traffic.Sent.Load(),
+			conn.Traffic.Received.Load(),
+			conn.Traffic.GetRoutes(),
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package api_common
+
+import (
+	"errors"
+	"net/http"
+	"pandora-pay/blockchain/info"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/helpers"
+)
+
+type APIStatsAssetRequest struct {
+	APIStatsRangeRequest
+	Asset helpers.Base64 `json:"asset,omitempty" msgpack:"asset,omitempty"`
+}
+
+type APIStatsAssetReply struct {
+	History []*info.AssetStats `json:"history" msgpack:"history"`
+}
+
+func (api *APICommon) GetStatsAsset(r *http.Request, args *APIStatsAssetRequest, reply *APIStatsAssetReply) (err error) {
+
+	if len(args.Asset) == 0 {
+		args.Asset = config_coins.NATIVE_ASSET_FULL
+	}
+	if len(args.Asset) != config_coins.ASSET_LENGTH {
+		return errors.New("Invalid asset")
+	}
+
+	reply.History, err = api.chain.OpenLoadAssetStats(args.Asset, args.StartHeight, args.EndHeight, args.StartTime, args.EndTime)
+	return
+}
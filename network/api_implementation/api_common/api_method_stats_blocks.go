@@ -0,0 +1,27 @@
+package api_common
+
+import (
+	"net/http"
+	"pandora-pay/blockchain/info"
+)
+
+type APIStatsRangeRequest struct {
+	StartHeight uint64 `json:"startHeight,omitempty" msgpack:"startHeight,omitempty"`
+	EndHeight   uint64 `json:"endHeight,omitempty" msgpack:"endHeight,omitempty"` //inclusive
+	StartTime   uint64 `json:"startTime,omitempty" msgpack:"startTime,omitempty"`
+	EndTime     uint64 `json:"endTime,omitempty" msgpack:"endTime,omitempty"` //inclusive
+}
+
+type APIStatsBlocksRequest struct {
+	APIStatsRangeRequest
+	Step uint64 `json:"step,omitempty" msgpack:"step,omitempty"`
+}
+
+type APIStatsBlocksReply struct {
+	Blocks []*info.BlockStats `json:"blocks" msgpack:"blocks"`
+}
+
+func (api *APICommon) GetStatsBlocks(r *http.Request, args *APIStatsBlocksRequest, reply *APIStatsBlocksReply) (err error) {
+	reply.Blocks, err = api.chain.OpenLoadBlocksStats(args.StartHeight, args.EndHeight, args.StartTime, args.EndTime, args.Step)
+	return
+}
@@ -0,0 +1,28 @@
+package api_common
+
+import (
+	"errors"
+	"net/http"
+	"pandora-pay/blockchain/info"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/helpers"
+)
+
+type APIStatsFeeLiquidityRequest struct {
+	Asset helpers.Base64 `json:"asset" msgpack:"asset"`
+	Count int            `json:"count,omitempty" msgpack:"count,omitempty"`
+}
+
+type APIStatsFeeLiquidityReply struct {
+	Providers []*info.FeeLiquidityProvider `json:"providers" msgpack:"providers"`
+}
+
+func (api *APICommon) GetStatsFeeLiquidity(r *http.Request, args *APIStatsFeeLiquidityRequest, reply *APIStatsFeeLiquidityReply) (err error) {
+
+	if len(args.Asset) != config_coins.ASSET_LENGTH {
+		return errors.New("Invalid asset")
+	}
+
+	reply.Providers, err = api.chain.OpenLoadFeeLiquidityProviders(args.Asset, args.Count)
+	return
+}
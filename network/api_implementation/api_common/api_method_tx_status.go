@@ -0,0 +1,28 @@
+package api_common
+
+import (
+	"errors"
+	"net/http"
+	"pandora-pay/blockchain/blockchain_types"
+	"pandora-pay/cryptography"
+	"pandora-pay/helpers"
+)
+
+type APITxStatusRequest struct {
+	Hash helpers.Base64 `json:"hash"  msgpack:"hash"`
+}
+
+func (api *APICommon) GetTxStatus(r *http.Request, args *APITxStatusRequest, reply *blockchain_types.TxStatus) error {
+
+	if len(args.Hash) != cryptography.HashSize {
+		return errors.New("Invalid hash")
+	}
+
+	status, err := api.chain.OpenLoadTxStatus(args.Hash)
+	if err != nil {
+		return err
+	}
+
+	*reply = *status
+	return nil
+}
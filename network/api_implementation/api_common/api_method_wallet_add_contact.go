@@ -0,0 +1,39 @@
+package api_common
+
+import (
+	"errors"
+	"net/http"
+	"pandora-pay/helpers"
+	"pandora-pay/network/api_implementation/api_common/api_types"
+	"pandora-pay/wallet/wallet_contact"
+)
+
+type APIWalletAddContactRequest struct {
+	api_types.APIWalletBaseRequest
+	Label          string         `json:"label" msgpack:"label"`
+	AddressEncoded string         `json:"addressEncoded" msgpack:"addressEncoded"`
+	Asset          helpers.Base64 `json:"asset,omitempty" msgpack:"asset,omitempty"`
+	PaymentID      helpers.Base64 `json:"paymentID,omitempty" msgpack:"paymentID,omitempty"`
+}
+
+type APIWalletAddContactReply struct {
+	Status bool `json:"status" msgpack:"status"`
+}
+
+func (api *APICommon) GetWalletAddContact(r *http.Request, args *APIWalletAddContactRequest, reply *APIWalletAddContactReply, authenticated bool) error {
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	wallet, err := api.wallets.GetWallet(args.Wallet)
+	if err != nil {
+		return err
+	}
+
+	if err = wallet.AddContact(&wallet_contact.WalletContact{Label: args.Label, AddressEncoded: args.AddressEncoded, Asset: args.Asset, PaymentID: args.PaymentID}, true); err != nil {
+		return err
+	}
+
+	reply.Status = true
+	return nil
+}
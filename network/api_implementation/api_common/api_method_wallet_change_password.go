@@ -0,0 +1,36 @@
+package api_common
+
+import (
+	"errors"
+	"net/http"
+	"pandora-pay/network/api_implementation/api_common/api_types"
+)
+
+type APIWalletChangePasswordRequest struct {
+	api_types.APIWalletBaseRequest
+	OldPassword string `json:"oldPassword" msgpack:"oldPassword"`
+	NewPassword string `json:"newPassword" msgpack:"newPassword"`
+	Difficulty  int    `json:"difficulty" msgpack:"difficulty"`
+}
+
+type APIWalletChangePasswordReply struct {
+	Status bool `json:"status" msgpack:"status"`
+}
+
+func (api *APICommon) WalletChangePassword(r *http.Request, args *APIWalletChangePasswordRequest, reply *APIWalletChangePasswordReply, authenticated bool) error {
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	wallet, err := api.wallets.GetWallet(args.Wallet)
+	if err != nil {
+		return err
+	}
+
+	if err = wallet.Encryption.ChangeEncryption(args.OldPassword, args.NewPassword, args.Difficulty); err != nil {
+		return err
+	}
+
+	reply.Status = true
+	return nil
+}
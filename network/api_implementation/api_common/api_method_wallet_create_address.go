@@ -4,10 +4,12 @@ import (
 	"errors"
 	"net/http"
 	"pandora-pay/helpers/generics"
+	"pandora-pay/network/api_implementation/api_common/api_types"
 	"pandora-pay/wallet/wallet_address"
 )
 
 type APIWalletCreateAddressRequest struct {
+	api_types.APIWalletBaseRequest
 	Name          string `json:"name" msgpack:"name"`
 	Staked        bool   `json:"staked" msgpack:"staked"`
 	SpendRequired bool   `json:"spendRequired" msgpack:"spendRequired"`
@@ -22,7 +24,12 @@ func (api *APICommon) GetWalletCreateAddress(r *http.Request, args *APIWalletCre
 		return errors.New("Invalid User or Password")
 	}
 
-	addr, err := api.wallet.AddNewAddress(true, args.Name, args.Staked, args.SpendRequired, true)
+	wallet, err := api.wallets.GetWallet(args.Wallet)
+	if err != nil {
+		return err
+	}
+
+	addr, err := wallet.AddNewAddress(true, args.Name, args.Staked, args.SpendRequired, true)
 	if err != nil {
 		return err
 	}
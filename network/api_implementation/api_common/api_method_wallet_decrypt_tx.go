@@ -15,6 +15,7 @@ import (
 
 type APIWalletDecryptTxRequest struct {
 	api_types.APIAccountBaseRequest
+	api_types.APIWalletBaseRequest
 	Hash helpers.Base64 `json:"hash" msgpack:"hash"`
 }
 
@@ -34,6 +35,11 @@ func (api *APICommon) GetWalletDecryptTx(r *http.Request, args *APIWalletDecrypt
 		return
 	}
 
+	wallet, err := api.wallets.GetWallet(args.Wallet)
+	if err != nil {
+		return
+	}
+
 	var txSerialized []byte
 	if err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
 
@@ -64,7 +70,7 @@ func (api *APICommon) GetWalletDecryptTx(r *http.Request, args *APIWalletDecrypt
 		return
 	}
 
-	reply.Decrypted, err = api.wallet.DecryptTx(tx, publicKey)
+	reply.Decrypted, err = wallet.DecryptTx(tx, publicKey)
 
 	return
 }
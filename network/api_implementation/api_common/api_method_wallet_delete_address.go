@@ -8,6 +8,7 @@ import (
 
 type APIWalletDeleteAddressRequest struct {
 	api_types.APIAccountBaseRequest
+	api_types.APIWalletBaseRequest
 }
 
 type APIWalletDeleteAddressReply struct {
@@ -24,6 +25,11 @@ func (api *APICommon) GetWalletDeleteAddress(r *http.Request, args *APIWalletDel
 		return err
 	}
 
-	reply.Status, err = api.wallet.RemoveAddressByPublicKey(publicKey, true)
+	wallet, err := api.wallets.GetWallet(args.Wallet)
+	if err != nil {
+		return err
+	}
+
+	reply.Status, err = wallet.RemoveAddressByPublicKey(publicKey, true)
 	return err
 }
@@ -0,0 +1,30 @@
+package api_common
+
+import (
+	"errors"
+	"net/http"
+	"pandora-pay/network/api_implementation/api_common/api_types"
+)
+
+type APIWalletDeleteContactRequest struct {
+	api_types.APIWalletBaseRequest
+	Label string `json:"label" msgpack:"label"`
+}
+
+type APIWalletDeleteContactReply struct {
+	Status bool `json:"status" msgpack:"status"`
+}
+
+func (api *APICommon) GetWalletDeleteContact(r *http.Request, args *APIWalletDeleteContactRequest, reply *APIWalletDeleteContactReply, authenticated bool) error {
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	wallet, err := api.wallets.GetWallet(args.Wallet)
+	if err != nil {
+		return err
+	}
+
+	reply.Status, err = wallet.RemoveContact(args.Label, true)
+	return err
+}
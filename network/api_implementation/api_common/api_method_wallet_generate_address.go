@@ -13,6 +13,7 @@ import (
 
 type APIWalletGenerateAddressRequest struct {
 	api_types.APIAccountBaseRequest
+	api_types.APIWalletBaseRequest
 	PaymentID     helpers.Base64 `json:"paymentID" msgpack:"paymentID"`
 	PaymentAmount uint64         `json:"paymentAmount" msgpack:"paymentAmount"`
 	PaymentAsset  helpers.Base64 `json:"paymentAsset" msgpack:"paymentAsset"`
@@ -33,7 +34,12 @@ func (api *APICommon) GetWalletGenerateAddress(r *http.Request, args *APIWalletG
 		return err
 	}
 
-	walletAddr := api.wallet.GetWalletAddressByPublicKey(publicKey, true)
+	wallet, err := api.wallets.GetWallet(args.Wallet)
+	if err != nil {
+		return err
+	}
+
+	walletAddr := wallet.GetWalletAddressByPublicKey(publicKey, true)
 	if walletAddr == nil {
 		return errors.New("address doesn't exist in your waallet")
 	}
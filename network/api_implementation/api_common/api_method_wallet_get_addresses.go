@@ -4,30 +4,42 @@ import (
 	"errors"
 	"net/http"
 	"pandora-pay/helpers/generics"
+	"pandora-pay/network/api_implementation/api_common/api_types"
 	"pandora-pay/wallet"
 	"pandora-pay/wallet/wallet_address"
 )
 
+type APIWalletGetAddressesRequest struct {
+	api_types.APIWalletBaseRequest
+}
+
 type APIWalletGetAccountsReply struct {
+	Wallet    string                          `json:"wallet" msgpack:"wallet"`
 	Version   wallet.Version                  `json:"version" msgpack:"version"`
 	Encrypted wallet.EncryptedVersion         `json:"encrypted" msgpack:"encrypted"`
 	Addresses []*wallet_address.WalletAddress `json:"addresses" msgpack:"addresses"`
 }
 
-func (api *APICommon) GetWalletAddresses(r *http.Request, args *struct{}, reply *APIWalletGetAccountsReply, authenticated bool) (err error) {
+func (api *APICommon) GetWalletAddresses(r *http.Request, args *APIWalletGetAddressesRequest, reply *APIWalletGetAccountsReply, authenticated bool) (err error) {
 
 	if !authenticated {
 		return errors.New("Invalid User or Password")
 	}
 
-	api.wallet.Lock.RLock()
-	defer api.wallet.Lock.RUnlock()
+	wallet, err := api.wallets.GetWallet(args.Wallet)
+	if err != nil {
+		return
+	}
+
+	wallet.Lock.RLock()
+	defer wallet.Lock.RUnlock()
 
-	reply.Version = api.wallet.Version
-	reply.Encrypted = api.wallet.Encryption.Encrypted
+	reply.Wallet = wallet.Name
+	reply.Version = wallet.Version
+	reply.Encrypted = wallet.Encryption.Encrypted
 
-	reply.Addresses = make([]*wallet_address.WalletAddress, len(api.wallet.Addresses))
-	for i, addr := range api.wallet.Addresses {
+	reply.Addresses = make([]*wallet_address.WalletAddress, len(wallet.Addresses))
+	for i, addr := range wallet.Addresses {
 		if reply.Addresses[i], err = generics.Clone[*wallet_address.WalletAddress](addr, new(wallet_address.WalletAddress)); err != nil {
 			return
 		}
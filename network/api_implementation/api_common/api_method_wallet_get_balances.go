@@ -15,6 +15,7 @@ import (
 )
 
 type APIWalletGetBalanceRequest struct {
+	api_types.APIWalletBaseRequest
 	List []*api_types.APIAccountBaseRequest `json:"list" msgpack:"list"`
 }
 
@@ -47,9 +48,14 @@ func (api *APICommon) GetWalletBalances(r *http.Request, args *APIWalletGetBalan
 		}
 	}
 
+	wallet, err := api.wallets.GetWallet(args.Wallet)
+	if err != nil {
+		return
+	}
+
 	walletAddresses := make([]*wallet_address.WalletAddress, len(publicKeys))
 	for i, publicKey := range publicKeys {
-		if walletAddresses[i] = api.wallet.GetWalletAddressByPublicKey(publicKey, true); walletAddresses[i] == nil {
+		if walletAddresses[i] = wallet.GetWalletAddressByPublicKey(publicKey, true); walletAddresses[i] == nil {
 			return errors.New(fmt.Sprintf("input %d doesn't exist in your wallet", i))
 		}
 	}
@@ -113,7 +119,7 @@ func (api *APICommon) GetWalletBalances(r *http.Request, args *APIWalletGetBalan
 	for i, publicKey := range publicKeys {
 		for _, data := range reply.Results[i].Balances {
 
-			if data.Amount, err = api.wallet.DecryptBalanceByPublicKey(publicKey, data.Balance, data.Asset, false, 0, true, true, nil, func(status string) {}); err != nil {
+			if data.Amount, err = wallet.DecryptBalanceByPublicKey(publicKey, data.Balance, data.Asset, false, 0, true, true, nil, func(status string) {}); err != nil {
 				return
 			}
 		}
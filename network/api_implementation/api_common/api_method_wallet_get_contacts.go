@@ -0,0 +1,30 @@
+package api_common
+
+import (
+	"errors"
+	"net/http"
+	"pandora-pay/network/api_implementation/api_common/api_types"
+	"pandora-pay/wallet/wallet_contact"
+)
+
+type APIWalletGetContactsRequest struct {
+	api_types.APIWalletBaseRequest
+}
+
+type APIWalletGetContactsReply struct {
+	Contacts []*wallet_contact.WalletContact `json:"contacts" msgpack:"contacts"`
+}
+
+func (api *APICommon) GetWalletContacts(r *http.Request, args *APIWalletGetContactsRequest, reply *APIWalletGetContactsReply, authenticated bool) error {
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	wallet, err := api.wallets.GetWallet(args.Wallet)
+	if err != nil {
+		return err
+	}
+
+	reply.Contacts = wallet.GetContacts(true)
+	return nil
+}
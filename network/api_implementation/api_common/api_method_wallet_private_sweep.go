@@ -0,0 +1,43 @@
+package api_common
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"pandora-pay/network/api_implementation/api_common/api_types"
+	"pandora-pay/txs_builder"
+)
+
+type APIWalletPrivateSweepRequest struct {
+	api_types.APIWalletBaseRequest
+	Data      *txs_builder.TxBuilderSweepData `json:"data" msgpack:"data"`
+	DryRun    bool                            `json:"dryRun" msgpack:"dryRun"`
+	Propagate bool                            `json:"propagate" msgpack:"propagate"`
+}
+
+type APIWalletPrivateSweepReply struct {
+	Result bool                            `json:"result" msgpack:"result"`
+	Txs    []*txs_builder.TxBuilderSweepTx `json:"txs" msgpack:"txs"`
+}
+
+func (api *APICommon) WalletPrivateSweep(r *http.Request, args *APIWalletPrivateSweepRequest, reply *APIWalletPrivateSweepReply, authenticated bool) (err error) {
+
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	if args.Data == nil {
+		return errors.New("Data is missing")
+	}
+	if args.Wallet != "" {
+		args.Data.Wallet = args.Wallet
+	}
+
+	if reply.Txs, err = txs_builder.TxsBuilder.SweepZether(args.Data, args.DryRun, args.Propagate, true, true, context.Background(), func(string) {}); err != nil {
+		return
+	}
+
+	reply.Result = true
+
+	return
+}
@@ -5,10 +5,12 @@ import (
 	"errors"
 	"net/http"
 	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/network/api_implementation/api_common/api_types"
 	"pandora-pay/txs_builder"
 )
 
 type APIWalletPrivateTransferRequest struct {
+	api_types.APIWalletBaseRequest
 	Data      *txs_builder.TxBuilderCreateZetherTxData `json:"data" msgpack:"data"`
 	Propagate bool                                     `json:"propagate" msgpack:"propagate"`
 }
@@ -24,6 +26,13 @@ func (api *APICommon) WalletPrivateTransfer(r *http.Request, args *APIWalletPriv
 		return errors.New("Invalid User or Password")
 	}
 
+	if args.Data == nil {
+		return errors.New("Data is missing")
+	}
+	if args.Wallet != "" {
+		args.Data.Wallet = args.Wallet
+	}
+
 	if reply.Tx, err = txs_builder.TxsBuilder.CreateZetherTx(args.Data, nil, args.Propagate, true, true, false, context.Background(), func(string) {}); err != nil {
 		return
 	}
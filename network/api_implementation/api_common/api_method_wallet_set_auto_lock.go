@@ -0,0 +1,34 @@
+package api_common
+
+import (
+	"errors"
+	"net/http"
+	"pandora-pay/network/api_implementation/api_common/api_types"
+)
+
+type APIWalletSetAutoLockRequest struct {
+	api_types.APIWalletBaseRequest
+	Timeout uint64 `json:"timeout" msgpack:"timeout"`
+}
+
+type APIWalletSetAutoLockReply struct {
+	Status bool `json:"status" msgpack:"status"`
+}
+
+func (api *APICommon) GetWalletSetAutoLock(r *http.Request, args *APIWalletSetAutoLockRequest, reply *APIWalletSetAutoLockReply, authenticated bool) error {
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	wallet, err := api.wallets.GetWallet(args.Wallet)
+	if err != nil {
+		return err
+	}
+
+	if err = wallet.Encryption.SetAutoLockTimeout(args.Timeout); err != nil {
+		return err
+	}
+
+	reply.Status = true
+	return nil
+}
@@ -0,0 +1,45 @@
+package api_common
+
+import (
+	"errors"
+	"net/http"
+	"pandora-pay/helpers"
+	"pandora-pay/network/api_implementation/api_common/api_types"
+	"pandora-pay/wallet/wallet_contact"
+)
+
+type APIWalletUpdateContactRequest struct {
+	api_types.APIWalletBaseRequest
+	Label          string         `json:"label" msgpack:"label"`
+	NewLabel       string         `json:"newLabel,omitempty" msgpack:"newLabel,omitempty"`
+	AddressEncoded string         `json:"addressEncoded" msgpack:"addressEncoded"`
+	Asset          helpers.Base64 `json:"asset,omitempty" msgpack:"asset,omitempty"`
+	PaymentID      helpers.Base64 `json:"paymentID,omitempty" msgpack:"paymentID,omitempty"`
+}
+
+type APIWalletUpdateContactReply struct {
+	Status bool `json:"status" msgpack:"status"`
+}
+
+func (api *APICommon) GetWalletUpdateContact(r *http.Request, args *APIWalletUpdateContactRequest, reply *APIWalletUpdateContactReply, authenticated bool) error {
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	wallet, err := api.wallets.GetWallet(args.Wallet)
+	if err != nil {
+		return err
+	}
+
+	newLabel := args.Label
+	if args.NewLabel != "" {
+		newLabel = args.NewLabel
+	}
+
+	if err = wallet.UpdateContact(args.Label, &wallet_contact.WalletContact{Label: newLabel, AddressEncoded: args.AddressEncoded, Asset: args.Asset, PaymentID: args.PaymentID}, true); err != nil {
+		return err
+	}
+
+	reply.Status = true
+	return nil
+}
@@ -0,0 +1,27 @@
+package api_common
+
+import (
+	"errors"
+	"net/http"
+)
+
+type APIWalletsCloseRequest struct {
+	Name string `json:"name" msgpack:"name"`
+}
+
+type APIWalletsCloseReply struct {
+	Result bool `json:"result" msgpack:"result"`
+}
+
+func (api *APICommon) GetWalletsClose(r *http.Request, args *APIWalletsCloseRequest, reply *APIWalletsCloseReply, authenticated bool) error {
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	if err := api.wallets.CloseWallet(args.Name); err != nil {
+		return err
+	}
+
+	reply.Result = true
+	return nil
+}
@@ -0,0 +1,27 @@
+package api_common
+
+import (
+	"errors"
+	"net/http"
+)
+
+type APIWalletsCreateRequest struct {
+	Name string `json:"name" msgpack:"name"`
+}
+
+type APIWalletsCreateReply struct {
+	Result bool `json:"result" msgpack:"result"`
+}
+
+func (api *APICommon) GetWalletsCreate(r *http.Request, args *APIWalletsCreateRequest, reply *APIWalletsCreateReply, authenticated bool) error {
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	if _, err := api.wallets.CreateWallet(args.Name); err != nil {
+		return err
+	}
+
+	reply.Result = true
+	return nil
+}
@@ -0,0 +1,20 @@
+package api_common
+
+import (
+	"errors"
+	"net/http"
+	"pandora-pay/wallet"
+)
+
+type APIWalletsListReply struct {
+	Wallets []*wallet.WalletInfo `json:"wallets" msgpack:"wallets"`
+}
+
+func (api *APICommon) GetWalletsList(r *http.Request, args *struct{}, reply *APIWalletsListReply, authenticated bool) error {
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	reply.Wallets = api.wallets.GetWalletsInfo()
+	return nil
+}
@@ -0,0 +1,34 @@
+package api_common
+
+import (
+	"errors"
+	"net/http"
+)
+
+type APIWalletsOpenRequest struct {
+	Name     string `json:"name" msgpack:"name"`
+	Password string `json:"password" msgpack:"password"`
+}
+
+type APIWalletsOpenReply struct {
+	Result bool `json:"result" msgpack:"result"`
+	Loaded bool `json:"loaded" msgpack:"loaded"`
+}
+
+func (api *APICommon) GetWalletsOpen(r *http.Request, args *APIWalletsOpenRequest, reply *APIWalletsOpenReply, authenticated bool) error {
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	wallet, err := api.wallets.OpenWallet(args.Name, args.Password)
+	if err != nil {
+		return err
+	}
+
+	wallet.Lock.RLock()
+	reply.Loaded = wallet.Loaded
+	wallet.Lock.RUnlock()
+
+	reply.Result = true
+	return nil
+}
@@ -32,3 +32,7 @@ func (request *APIAccountBaseRequest) GetPublicKey(required bool) ([]byte, error
 
 	return publicKey, nil
 }
+
+type APIWalletBaseRequest struct {
+	Wallet string `json:"wallet,omitempty" msgpack:"wallet,omitempty"`
+}
@@ -51,3 +51,22 @@ type APISubscriptionNotificationTxExtra struct {
 	Blockchain *APISubscriptionNotificationTxExtraBlockchain `json:"blockchain,omitempty" msgpack:"blockchain,omitempty"`
 	Mempool    *APISubscriptionNotificationTxExtraMempool    `json:"mempool,omitempty" msgpack:"mempool,omitempty"`
 }
+
+type APISubscriptionNotificationBlockExtra struct {
+	Height   uint64 `json:"height" msgpack:"height"`
+	Hash     []byte `json:"hash" msgpack:"hash"`
+	TxsCount uint64 `json:"txsCount" msgpack:"txsCount"`
+}
+
+type APISubscriptionNotificationReorgExtra struct {
+	RemovedHeights []uint64 `json:"removedHeights" msgpack:"removedHeights"`
+	AddedHeights   []uint64 `json:"addedHeights" msgpack:"addedHeights"`
+	Height         uint64   `json:"height" msgpack:"height"`
+	Hash           []byte   `json:"hash" msgpack:"hash"`
+}
+
+type APISubscriptionNotificationMempoolExtra struct {
+	Hash     []byte `json:"hash" msgpack:"hash"`
+	Inserted bool   `json:"inserted,omitempty" msgpack:"inserted,omitempty"`
+	Included bool   `json:"included,omitempty" msgpack:"included,omitempty"`
+}
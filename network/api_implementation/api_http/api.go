@@ -1,22 +1,22 @@
 package api_http
 
 import (
-	"io"
-	"net/url"
 	"pandora-pay/blockchain"
 	"pandora-pay/blockchain/blockchain_sync"
+	"pandora-pay/blockchain/blockchain_types"
 	"pandora-pay/blockchain/info"
 	"pandora-pay/config"
 	"pandora-pay/network/api_code/api_code_http"
 	"pandora-pay/network/api_implementation/api_common"
 	"pandora-pay/network/api_implementation/api_common/api_delegator_node"
+	"pandora-pay/network/api_implementation/api_common/api_exchange"
 	"pandora-pay/network/api_implementation/api_common/api_faucet"
 	"pandora-pay/network/network_config"
 )
 
 type API struct {
-	GetMap    map[string]func(values url.Values) (interface{}, error)
-	PostMap   map[string]func(values io.ReadCloser) (interface{}, error)
+	GetMap    map[string]*api_code_http.Route
+	PostMap   map[string]*api_code_http.Route
 	chain     *blockchain.Blockchain
 	apiCommon *api_common.APICommon
 	apiStore  *api_common.APIStore
@@ -32,8 +32,10 @@ func NewAPI(apiStore *api_common.APIStore, apiCommon *api_common.APICommon, chai
 		apiCommon: apiCommon,
 	}
 
-	api.GetMap = map[string]func(values url.Values) (interface{}, error){
+	api.GetMap = map[string]*api_code_http.Route{
 		"ping":                    api_code_http.Handle[struct{}, api_common.APIPingReply](api.apiCommon.GetPing),
+		"health/live":             api_code_http.Handle[struct{}, api_common.APIHealthReply](api.apiCommon.GetHealthLive),
+		"health/ready":            api_code_http.Handle[struct{}, api_common.APIHealthReply](api.apiCommon.GetHealthReady),
 		"":                        api_code_http.Handle[struct{}, api_common.APIInfoReply](api.apiCommon.GetInfo),
 		"chain":                   api_code_http.Handle[struct{}, api_common.APIBlockchain](api.apiCommon.GetBlockchain),
 		"blockchain":              api_code_http.Handle[struct{}, api_common.APIBlockchain](api.apiCommon.GetBlockchain),
@@ -41,6 +43,7 @@ func NewAPI(apiStore *api_common.APIStore, apiCommon *api_common.APICommon, chai
 		"blockchain/genesis-info": api_code_http.Handle[api_common.APIGenesisInfoRequest, api_common.APIGenesisInfoReply](api.apiCommon.GetGenesisInfo),
 		"blockchain/supply":       api_code_http.Handle[struct{}, api_common.APISupply](api.apiCommon.GetSupply),
 		"blockchain/supply-only":  api_code_http.Handle[struct{}, uint64](api.apiCommon.GetSupplyOnly),
+		"blockchain/finality":     api_code_http.Handle[struct{}, api_common.APIFinalityReply](api.apiCommon.GetFinality),
 		"sync":                    api_code_http.Handle[struct{}, blockchain_sync.BlockchainSyncData](api.apiCommon.GetBlockchainSync),
 		"block-hash":              api_code_http.Handle[api_common.APIBlockHashRequest, api_common.APIBlockHashReply](api.apiCommon.GetBlockHash),
 		"block/exists":            api_code_http.Handle[api_common.APIBlockExistsRequest, api_common.APIBlockExistsReply](api.apiCommon.GetBlockExists),
@@ -49,6 +52,7 @@ func NewAPI(apiStore *api_common.APIStore, apiCommon *api_common.APICommon, chai
 		"tx-hash":                 api_code_http.Handle[api_common.APITxHashRequest, api_common.APITxHashReply](api.apiCommon.GetTxHash),
 		"tx":                      api_code_http.Handle[api_common.APITxRequest, api_common.APITxReply](api.apiCommon.GetTx),
 		"tx/exists":               api_code_http.Handle[api_common.APITxExistsRequest, api_common.APITxExistsReply](api.apiCommon.GetTxExists),
+		"tx/status":               api_code_http.Handle[api_common.APITxStatusRequest, blockchain_types.TxStatus](api.apiCommon.GetTxStatus),
 		"tx-raw":                  api_code_http.Handle[api_common.APITxRawRequest, api_common.APITxRawReply](api.apiCommon.GetTxRaw),
 		"account":                 api_code_http.Handle[api_common.APIAccountRequest, api_common.APIAccountReply](api.apiCommon.GetAccount),
 		"accounts/count":          api_code_http.Handle[api_common.APIAccountsCountRequest, api_common.APIAccountsCountReply](api.apiCommon.GetAccountsCount),
@@ -61,16 +65,28 @@ func NewAPI(apiStore *api_common.APIStore, apiCommon *api_common.APICommon, chai
 		"mempool/tx-exists":       api_code_http.Handle[api_common.APIMempoolExistsRequest, api_common.APIMempoolExistsReply](api.apiCommon.GetMempoolExists),
 		"mempool/new-tx":          api_code_http.Handle[api_common.APIMempoolNewTxRequest, api_common.APIMempoolNewTxReply](api.apiCommon.MempoolNewTx),
 		"network/nodes":           api_code_http.Handle[struct{}, api_common.APINetworkNodesReply](api.apiCommon.GetNetworkNodes),
-		"wallet/get-addresses":    api_code_http.HandleAuthenticated[struct{}, api_common.APIWalletGetAccountsReply](api.apiCommon.GetWalletAddresses),
+		"network/traffic":         api_code_http.HandleAuthenticated[struct{}, api_common.APINetworkTrafficReply](api.apiCommon.GetNetworkTraffic),
+		"wallet/get-addresses":    api_code_http.HandleAuthenticated[api_common.APIWalletGetAddressesRequest, api_common.APIWalletGetAccountsReply](api.apiCommon.GetWalletAddresses),
 		"wallet/generate-address": api_code_http.HandleAuthenticated[api_common.APIWalletGenerateAddressRequest, api_common.APIWalletGenerateAddressReply](api.apiCommon.GetWalletGenerateAddress),
 		"wallet/create-address":   api_code_http.HandleAuthenticated[api_common.APIWalletCreateAddressRequest, api_common.APIWalletCreateAddressReply](api.apiCommon.GetWalletCreateAddress),
 		"wallet/delete-address":   api_code_http.HandleAuthenticated[api_common.APIWalletDeleteAddressRequest, api_common.APIWalletDeleteAddressReply](api.apiCommon.GetWalletDeleteAddress),
 		"wallet/get-balances":     api_code_http.HandleAuthenticated[api_common.APIWalletGetBalanceRequest, api_common.APIWalletGetBalancesReply](api.apiCommon.GetWalletBalances),
 		"wallet/decrypt-tx":       api_code_http.HandleAuthenticated[api_common.APIWalletDecryptTxRequest, api_common.APIWalletDecryptTxReply](api.apiCommon.GetWalletDecryptTx),
+		"wallet/get-contacts":     api_code_http.HandleAuthenticated[api_common.APIWalletGetContactsRequest, api_common.APIWalletGetContactsReply](api.apiCommon.GetWalletContacts),
+		"wallet/add-contact":      api_code_http.HandleAuthenticated[api_common.APIWalletAddContactRequest, api_common.APIWalletAddContactReply](api.apiCommon.GetWalletAddContact),
+		"wallet/update-contact":   api_code_http.HandleAuthenticated[api_common.APIWalletUpdateContactRequest, api_common.APIWalletUpdateContactReply](api.apiCommon.GetWalletUpdateContact),
+		"wallet/delete-contact":   api_code_http.HandleAuthenticated[api_common.APIWalletDeleteContactRequest, api_common.APIWalletDeleteContactReply](api.apiCommon.GetWalletDeleteContact),
+		"wallet/set-auto-lock":    api_code_http.HandleAuthenticated[api_common.APIWalletSetAutoLockRequest, api_common.APIWalletSetAutoLockReply](api.apiCommon.GetWalletSetAutoLock),
+		"wallets/list":            api_code_http.HandleAuthenticated[struct{}, api_common.APIWalletsListReply](api.apiCommon.GetWalletsList),
+		"wallets/create":          api_code_http.HandleAuthenticated[api_common.APIWalletsCreateRequest, api_common.APIWalletsCreateReply](api.apiCommon.GetWalletsCreate),
+		"wallets/open":            api_code_http.HandleAuthenticated[api_common.APIWalletsOpenRequest, api_common.APIWalletsOpenReply](api.apiCommon.GetWalletsOpen),
+		"wallets/close":           api_code_http.HandleAuthenticated[api_common.APIWalletsCloseRequest, api_common.APIWalletsCloseReply](api.apiCommon.GetWalletsClose),
 	}
 
-	api.PostMap = map[string]func(values io.ReadCloser) (interface{}, error){
+	api.PostMap = map[string]*api_code_http.Route{
+		"wallet/change-password":  api_code_http.HandlePOSTAuthenticated[api_common.APIWalletChangePasswordRequest, api_common.APIWalletChangePasswordReply](api.apiCommon.WalletChangePassword),
 		"wallet/private-transfer": api_code_http.HandlePOSTAuthenticated[api_common.APIWalletPrivateTransferRequest, api_common.APIWalletPrivateTransferReply](api.apiCommon.WalletPrivateTransfer),
+		"wallet/private-sweep":    api_code_http.HandlePOSTAuthenticated[api_common.APIWalletPrivateSweepRequest, api_common.APIWalletPrivateSweepReply](api.apiCommon.WalletPrivateSweep),
 	}
 
 	if config.NODE_PROVIDE_EXTENDED_INFO_APP {
@@ -83,6 +99,12 @@ func NewAPI(apiStore *api_common.APIStore, apiCommon *api_common.APICommon, chai
 		api.GetMap["account/mempool-nonce"] = api_code_http.Handle[api_common.APIAccountMempoolNonceRequest, api_common.APIAccountMempoolNonceReply](api.apiCommon.GetAccountMempoolNonce)
 	}
 
+	if config.NODE_PROVIDE_ANALYTICS {
+		api.GetMap["stats/blocks"] = api_code_http.Handle[api_common.APIStatsBlocksRequest, api_common.APIStatsBlocksReply](api.apiCommon.GetStatsBlocks)
+		api.GetMap["stats/asset"] = api_code_http.Handle[api_common.APIStatsAssetRequest, api_common.APIStatsAssetReply](api.apiCommon.GetStatsAsset)
+		api.GetMap["stats/fee-liquidity"] = api_code_http.Handle[api_common.APIStatsFeeLiquidityRequest, api_common.APIStatsFeeLiquidityReply](api.apiCommon.GetStatsFeeLiquidity)
+	}
+
 	if api.apiCommon.Faucet != nil {
 		api.GetMap["faucet/info"] = api_code_http.Handle[struct{}, api_faucet.APIFaucetInfo](api.apiCommon.Faucet.GetFaucetInfo)
 		if network_config.FAUCET_TESTNET_ENABLED {
@@ -95,6 +117,13 @@ func NewAPI(apiStore *api_common.APIStore, apiCommon *api_common.APICommon, chai
 		api.GetMap["delegator-node/notify"] = api_code_http.HandleAuthenticated[api_delegator_node.ApiDelegatorNodeNotifyRequest, api_delegator_node.ApiDelegatorNodeNotifyReply](api.apiCommon.DelegatorNode.DelegatorNotify)
 	}
 
+	if api.apiCommon.Exchange != nil {
+		api.GetMap["exchange/deposit-address"] = api_code_http.HandleAuthenticated[api_exchange.APIExchangeDepositAddressRequest, api_exchange.APIExchangeDepositAddressReply](api.apiCommon.Exchange.GetExchangeDepositAddress)
+		api.GetMap["exchange/deposits"] = api_code_http.HandleAuthenticated[api_exchange.APIExchangeDepositsRequest, api_exchange.APIExchangeDepositsReply](api.apiCommon.Exchange.GetExchangeDeposits)
+		api.PostMap["exchange/withdraw"] = api_code_http.HandlePOSTAuthenticated[api_exchange.APIExchangeWithdrawRequest, api_exchange.ExchangeWithdrawal](api.apiCommon.Exchange.ExchangeWithdraw)
+		api.GetMap["exchange/withdrawal"] = api_code_http.HandleAuthenticated[api_exchange.APIExchangeWithdrawalRequest, api_exchange.ExchangeWithdrawal](api.apiCommon.Exchange.GetExchangeWithdrawal)
+	}
+
 	if ConfigureAPIRoutes != nil {
 		ConfigureAPIRoutes(api)
 	}
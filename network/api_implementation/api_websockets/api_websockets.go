@@ -3,12 +3,14 @@ package api_websockets
 import (
 	"pandora-pay/blockchain"
 	"pandora-pay/blockchain/blockchain_sync"
+	"pandora-pay/blockchain/blockchain_types"
 	"pandora-pay/blockchain/info"
 	"pandora-pay/config"
 	"pandora-pay/mempool"
 	"pandora-pay/network/api_code/api_code_websockets"
 	"pandora-pay/network/api_implementation/api_common"
 	"pandora-pay/network/api_implementation/api_common/api_delegator_node"
+	"pandora-pay/network/api_implementation/api_common/api_exchange"
 	"pandora-pay/network/api_implementation/api_common/api_faucet"
 	"pandora-pay/network/api_implementation/api_websockets/consensus"
 	"pandora-pay/network/network_config"
@@ -42,6 +44,8 @@ func NewWebsocketsAPI(apiStore *api_common.APIStore, apiCommon *api_common.APICo
 
 	api.GetMap = map[string]func(conn *connection.AdvancedConnection, values []byte) (interface{}, error){
 		"ping":                    api_code_websockets.Handle[struct{}, api_common.APIPingReply](api.apiCommon.GetPing),
+		"health/live":             api_code_websockets.Handle[struct{}, api_common.APIHealthReply](api.apiCommon.GetHealthLive),
+		"health/ready":            api_code_websockets.Handle[struct{}, api_common.APIHealthReply](api.apiCommon.GetHealthReady),
 		"":                        api_code_websockets.Handle[struct{}, api_common.APIInfoReply](api.apiCommon.GetInfo),
 		"chain":                   api_code_websockets.Handle[struct{}, api_common.APIBlockchain](api.apiCommon.GetBlockchain),
 		"blockchain":              api_code_websockets.Handle[struct{}, api_common.APIBlockchain](api.apiCommon.GetBlockchain),
@@ -49,6 +53,7 @@ func NewWebsocketsAPI(apiStore *api_common.APIStore, apiCommon *api_common.APICo
 		"blockchain/genesis-info": api_code_websockets.Handle[api_common.APIGenesisInfoRequest, api_common.APIGenesisInfoReply](api.apiCommon.GetGenesisInfo),
 		"blockchain/supply":       api_code_websockets.Handle[struct{}, api_common.APISupply](api.apiCommon.GetSupply),
 		"blockchain/supply-only":  api_code_websockets.Handle[struct{}, uint64](api.apiCommon.GetSupplyOnly),
+		"blockchain/finality":     api_code_websockets.Handle[struct{}, api_common.APIFinalityReply](api.apiCommon.GetFinality),
 		"sync":                    api_code_websockets.Handle[struct{}, blockchain_sync.BlockchainSyncData](api.apiCommon.GetBlockchainSync),
 		"block-hash":              api_code_websockets.Handle[api_common.APIBlockHashRequest, api_common.APIBlockHashReply](api.apiCommon.GetBlockHash),
 		"block":                   api_code_websockets.Handle[api_common.APIBlockRequest, api_common.APIBlockReply](api.apiCommon.GetBlock),
@@ -57,6 +62,7 @@ func NewWebsocketsAPI(apiStore *api_common.APIStore, apiCommon *api_common.APICo
 		"tx-hash":                 api_code_websockets.Handle[api_common.APITxHashRequest, api_common.APITxHashReply](api.apiCommon.GetTxHash),
 		"tx":                      api_code_websockets.Handle[api_common.APITxRequest, api_common.APITxReply](api.apiCommon.GetTx),
 		"tx/exists":               api_code_websockets.Handle[api_common.APITxExistsRequest, api_common.APITxExistsReply](api.apiCommon.GetTxExists),
+		"tx/status":               api_code_websockets.Handle[api_common.APITxStatusRequest, blockchain_types.TxStatus](api.apiCommon.GetTxStatus),
 		"tx-raw":                  api_code_websockets.Handle[api_common.APITxRawRequest, api_common.APITxRawReply](api.apiCommon.GetTxRaw),
 		"account":                 api_code_websockets.Handle[api_common.APIAccountRequest, api_common.APIAccountReply](api.apiCommon.GetAccount),
 		"accounts/count":          api_code_websockets.Handle[api_common.APIAccountsCountRequest, api_common.APIAccountsCountReply](api.apiCommon.GetAccountsCount),
@@ -69,23 +75,39 @@ func NewWebsocketsAPI(apiStore *api_common.APIStore, apiCommon *api_common.APICo
 		"mempool/tx-exists":       api_code_websockets.Handle[api_common.APIMempoolExistsRequest, api_common.APIMempoolExistsReply](api.apiCommon.GetMempoolExists),
 		"mempool/new-tx":          api_code_websockets.Handle[api_common.APIMempoolNewTxRequest, api_common.APIMempoolNewTxReply](api.apiCommon.MempoolNewTx),
 		"network/nodes":           api_code_websockets.Handle[struct{}, api_common.APINetworkNodesReply](api.apiCommon.GetNetworkNodes),
-		"wallet/get-addresses":    api_code_websockets.HandleAuthenticated[struct{}, api_common.APIWalletGetAccountsReply](api.apiCommon.GetWalletAddresses),
+		"network/traffic":         api_code_websockets.HandleAuthenticated[struct{}, api_common.APINetworkTrafficReply](api.apiCommon.GetNetworkTraffic),
+		"wallet/get-addresses":    api_code_websockets.HandleAuthenticated[api_common.APIWalletGetAddressesRequest, api_common.APIWalletGetAccountsReply](api.apiCommon.GetWalletAddresses),
 		"wallet/generate-address": api_code_websockets.HandleAuthenticated[api_common.APIWalletGenerateAddressRequest, api_common.APIWalletGenerateAddressReply](api.apiCommon.GetWalletGenerateAddress),
 		"wallet/create-address":   api_code_websockets.HandleAuthenticated[api_common.APIWalletCreateAddressRequest, api_common.APIWalletCreateAddressReply](api.apiCommon.GetWalletCreateAddress),
 		"wallet/delete-address":   api_code_websockets.HandleAuthenticated[api_common.APIWalletDeleteAddressRequest, api_common.APIWalletDeleteAddressReply](api.apiCommon.GetWalletDeleteAddress),
 		"wallet/get-balances":     api_code_websockets.HandleAuthenticated[api_common.APIWalletGetBalanceRequest, api_common.APIWalletGetBalancesReply](api.apiCommon.GetWalletBalances),
 		"wallet/decrypt-tx":       api_code_websockets.HandleAuthenticated[api_common.APIWalletDecryptTxRequest, api_common.APIWalletDecryptTxReply](api.apiCommon.GetWalletDecryptTx),
+		"wallet/get-contacts":     api_code_websockets.HandleAuthenticated[api_common.APIWalletGetContactsRequest, api_common.APIWalletGetContactsReply](api.apiCommon.GetWalletContacts),
+		"wallet/add-contact":      api_code_websockets.HandleAuthenticated[api_common.APIWalletAddContactRequest, api_common.APIWalletAddContactReply](api.apiCommon.GetWalletAddContact),
+		"wallet/update-contact":   api_code_websockets.HandleAuthenticated[api_common.APIWalletUpdateContactRequest, api_common.APIWalletUpdateContactReply](api.apiCommon.GetWalletUpdateContact),
+		"wallet/delete-contact":   api_code_websockets.HandleAuthenticated[api_common.APIWalletDeleteContactRequest, api_common.APIWalletDeleteContactReply](api.apiCommon.GetWalletDeleteContact),
+		"wallet/set-auto-lock":    api_code_websockets.HandleAuthenticated[api_common.APIWalletSetAutoLockRequest, api_common.APIWalletSetAutoLockReply](api.apiCommon.GetWalletSetAutoLock),
+		"wallets/list":            api_code_websockets.HandleAuthenticated[struct{}, api_common.APIWalletsListReply](api.apiCommon.GetWalletsList),
+		"wallets/create":          api_code_websockets.HandleAuthenticated[api_common.APIWalletsCreateRequest, api_common.APIWalletsCreateReply](api.apiCommon.GetWalletsCreate),
+		"wallets/open":            api_code_websockets.HandleAuthenticated[api_common.APIWalletsOpenRequest, api_common.APIWalletsOpenReply](api.apiCommon.GetWalletsOpen),
+		"wallets/close":           api_code_websockets.HandleAuthenticated[api_common.APIWalletsCloseRequest, api_common.APIWalletsCloseReply](api.apiCommon.GetWalletsClose),
+		"wallet/change-password":  api_code_websockets.HandleAuthenticated[api_common.APIWalletChangePasswordRequest, api_common.APIWalletChangePasswordReply](api.apiCommon.WalletChangePassword),
 		"wallet/private-transfer": api_code_websockets.HandleAuthenticated[api_common.APIWalletPrivateTransferRequest, api_common.APIWalletPrivateTransferReply](api.apiCommon.WalletPrivateTransfer),
+		"wallet/private-sweep":    api_code_websockets.HandleAuthenticated[api_common.APIWalletPrivateSweepRequest, api_common.APIWalletPrivateSweepReply](api.apiCommon.WalletPrivateSweep),
 		//below are ONLY websockets API
-		"block-miss-txs":    api_code_websockets.Handle[consensus.APIBlockCompleteMissingTxsRequest, consensus.APIBlockCompleteMissingTxsReply](api.Consensus.GetBlockCompleteMissingTxs),
-		"handshake":         api_code_websockets.Handshake,
-		"mempool/new-tx-id": api.apiCommon.MempoolNewTxId,
-		"get-chain":         api.Consensus.GetChain,
-		"chain-update":      api.Consensus.ChainUpdate,
-		"login":             api_code_websockets.Login,
-		"logout":            api_code_websockets.Logout,
-		"sub":               api_code_websockets.Subscribe,
-		"unsub":             api_code_websockets.Unsubscribe,
+		"block-miss-txs":       api_code_websockets.Handle[consensus.APIBlockCompleteMissingTxsRequest, consensus.APIBlockCompleteMissingTxsReply](api.Consensus.GetBlockCompleteMissingTxs),
+		"block-compact":        api_code_websockets.HandleFeature(connection.FEATURE_COMPACT_BLOCKS, api_code_websockets.Handle[consensus.APIBlockCompactRequest, consensus.CompactBlock](api.Consensus.GetBlockCompact)),
+		"handshake":            api_code_websockets.Handshake,
+		"mempool/new-tx-id":    api.apiCommon.MempoolNewTxId,
+		"mempool/stem-tx":      api_code_websockets.HandleFeature(connection.FEATURE_DANDELION, api.apiCommon.MempoolStemTx),
+		"mempool/reconcile":    api_code_websockets.HandleFeature(connection.FEATURE_MEMPOOL_RECONCILIATION, api_code_websockets.Handle[api_common.APIMempoolReconcileRequest, api_common.APIMempoolReconcileReply](api.apiCommon.GetMempoolReconcile)),
+		"get-chain":            api.Consensus.GetChain,
+		"chain-update":         api.Consensus.ChainUpdate,
+		"chain-update-compact": api_code_websockets.HandleFeature(connection.FEATURE_COMPACT_BLOCKS, api.Consensus.ChainUpdateCompact),
+		"login":                api_code_websockets.Login,
+		"logout":               api_code_websockets.Logout,
+		"sub":                  api_code_websockets.Subscribe,
+		"unsub":                api_code_websockets.Unsubscribe,
 	}
 
 	if config.NODE_PROVIDE_EXTENDED_INFO_APP {
@@ -102,6 +124,12 @@ func NewWebsocketsAPI(apiStore *api_common.APIStore, apiCommon *api_common.APICo
 		api.GetMap["sub/notify"] = api_code_websockets.SubscribedNotificationReceived
 	}
 
+	if config.NODE_PROVIDE_ANALYTICS {
+		api.GetMap["stats/blocks"] = api_code_websockets.Handle[api_common.APIStatsBlocksRequest, api_common.APIStatsBlocksReply](api.apiCommon.GetStatsBlocks)
+		api.GetMap["stats/asset"] = api_code_websockets.Handle[api_common.APIStatsAssetRequest, api_common.APIStatsAssetReply](api.apiCommon.GetStatsAsset)
+		api.GetMap["stats/fee-liquidity"] = api_code_websockets.Handle[api_common.APIStatsFeeLiquidityRequest, api_common.APIStatsFeeLiquidityReply](api.apiCommon.GetStatsFeeLiquidity)
+	}
+
 	if api.apiCommon.Faucet != nil {
 		api.GetMap["faucet/info"] = api_code_websockets.Handle[struct{}, api_faucet.APIFaucetInfo](api.apiCommon.Faucet.GetFaucetInfo)
 		if network_config.FAUCET_TESTNET_ENABLED {
@@ -114,6 +142,13 @@ func NewWebsocketsAPI(apiStore *api_common.APIStore, apiCommon *api_common.APICo
 		api.GetMap["delegator-node/notify"] = api_code_websockets.HandleAuthenticated[api_delegator_node.ApiDelegatorNodeNotifyRequest, api_delegator_node.ApiDelegatorNodeNotifyReply](api.apiCommon.DelegatorNode.DelegatorNotify)
 	}
 
+	if api.apiCommon.Exchange != nil {
+		api.GetMap["exchange/deposit-address"] = api_code_websockets.HandleAuthenticated[api_exchange.APIExchangeDepositAddressRequest, api_exchange.APIExchangeDepositAddressReply](api.apiCommon.Exchange.GetExchangeDepositAddress)
+		api.GetMap["exchange/deposits"] = api_code_websockets.HandleAuthenticated[api_exchange.APIExchangeDepositsRequest, api_exchange.APIExchangeDepositsReply](api.apiCommon.Exchange.GetExchangeDeposits)
+		api.GetMap["exchange/withdraw"] = api_code_websockets.HandleAuthenticated[api_exchange.APIExchangeWithdrawRequest, api_exchange.ExchangeWithdrawal](api.apiCommon.Exchange.ExchangeWithdraw)
+		api.GetMap["exchange/withdrawal"] = api_code_websockets.HandleAuthenticated[api_exchange.APIExchangeWithdrawalRequest, api_exchange.ExchangeWithdrawal](api.apiCommon.Exchange.GetExchangeWithdrawal)
+	}
+
 	if ConfigureAPIRoutes != nil {
 		ConfigureAPIRoutes(api)
 	}
@@ -0,0 +1,33 @@
+package consensus
+
+import (
+	"net/http"
+	"pandora-pay/helpers"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+)
+
+type APIBlockCompactRequest struct {
+	Height uint64         `json:"height,omitempty" msgpack:"height,omitempty"`
+	Hash   helpers.Base64 `json:"hash,omitempty" msgpack:"hash,omitempty"`
+}
+
+func (api *Consensus) GetBlockCompact(r *http.Request, args *APIBlockCompactRequest, reply *CompactBlock) error {
+	return store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		if len(args.Hash) == 0 {
+			if args.Hash, err = api.chain.LoadBlockHash(reader, args.Height); err != nil {
+				return
+			}
+		}
+
+		compactBlock, err := loadCompactBlock(reader, args.Hash)
+		if err != nil {
+			return
+		}
+
+		*reply = *compactBlock
+		reply.priority = api.getBlockPriority(args.Hash)
+		return
+	})
+}
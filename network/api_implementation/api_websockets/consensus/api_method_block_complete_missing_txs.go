@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"pandora-pay/helpers"
 	"pandora-pay/helpers/msgpack"
+	"pandora-pay/network/bandwidth"
 	"pandora-pay/store"
 	"pandora-pay/store/store_db/store_db_interface"
 	"strconv"
@@ -16,7 +17,12 @@ type APIBlockCompleteMissingTxsRequest struct {
 }
 
 type APIBlockCompleteMissingTxsReply struct {
-	Txs [][]byte `json:"txs,omitempty" msgpack:"txs,omitempty"`
+	Txs      [][]byte `json:"txs,omitempty" msgpack:"txs,omitempty"`
+	priority bandwidth.PriorityType
+}
+
+func (reply *APIBlockCompleteMissingTxsReply) GetPriority() bandwidth.PriorityType {
+	return reply.priority
 }
 
 func (api *Consensus) GetBlockCompleteMissingTxs(r *http.Request, args *APIBlockCompleteMissingTxsRequest, reply *APIBlockCompleteMissingTxsReply) error {
@@ -42,6 +48,9 @@ func (api *Consensus) GetBlockCompleteMissingTxs(r *http.Request, args *APIBlock
 			return
 		}
 
+		//the missing txs of the tip are requested right after its announcement
+		reply.priority = api.getBlockPriority(args.Hash)
+
 		reply.Txs = make([][]byte, len(args.MissingTxs))
 		for i, txMissingIndex := range args.MissingTxs {
 			if txMissingIndex >= 0 && txMissingIndex < len(txHashes) {
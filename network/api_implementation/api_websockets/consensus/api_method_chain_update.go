@@ -16,6 +16,8 @@ func (consensus *Consensus) ChainUpdateProcess(conn *connection.AdvancedConnecti
 		return nil, errors.New("Chain Update Hash Length is invalid")
 	}
 
+	consensus.tipReceived(conn, chainUpdateNotification)
+
 	chainLastUpdate := consensus.chain.GetChainData()
 	if bytes.Equal(chainLastUpdate.Hash, chainUpdateNotification.Hash) {
 		return nil, nil
@@ -50,7 +52,7 @@ func (consensus *Consensus) ChainUpdateProcess(conn *connection.AdvancedConnecti
 
 	} else {
 		//let's notify him tha we have a better chain
-		conn.SendJSON([]byte("chain-update"), consensus.GetUpdateNotification(nil), 0)
+		consensus.AnnounceTip(conn, consensus.GetUpdateNotification(nil), true, 0)
 	}
 
 	return nil, nil
@@ -58,6 +60,10 @@ func (consensus *Consensus) ChainUpdateProcess(conn *connection.AdvancedConnecti
 }
 
 func (consensus *Consensus) ChainUpdate(conn *connection.AdvancedConnection, data []byte) (interface{}, error) {
+
+	metricTipMessages.Inc("announce_received")
+	metricTipBytes.Add("announce_received", uint64(len(data)))
+
 	chainUpdateNotification := &ChainUpdateNotification{}
 	if err := msgpack.Unmarshal(data, chainUpdateNotification); err != nil {
 		return nil, err
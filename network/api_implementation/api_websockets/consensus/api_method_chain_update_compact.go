@@ -0,0 +1,86 @@
+package consensus
+
+import (
+	"bytes"
+	"errors"
+	"pandora-pay/blockchain/blocks/block_complete"
+	"pandora-pay/config"
+	"pandora-pay/cryptography"
+	"pandora-pay/gui"
+	"pandora-pay/gui/gui_logger"
+	"pandora-pay/helpers/msgpack"
+	"pandora-pay/network/websocks/connection"
+	"pandora-pay/txs_validator"
+)
+
+type ChainUpdateCompactNotification struct {
+	Chain *ChainUpdateNotification `json:"chain" msgpack:"chain"`
+	Block *CompactBlock            `json:"block" msgpack:"block"`
+}
+
+//includeCompactBlock includes the block only when it extends the current chain. Otherwise, the block is downloaded as a fork
+func (consensus *Consensus) includeCompactBlock(conn *connection.AdvancedConnection, notification *ChainUpdateCompactNotification) error {
+
+	chainData := consensus.chain.GetChainData()
+	if notification.Chain.End != chainData.Height+1 || !bytes.Equal(notification.Chain.PrevHash, chainData.Hash) || chainData.BigTotalDifficulty.Cmp(notification.Chain.BigTotalDifficulty) >= 0 {
+		return nil
+	}
+
+	blkComplete, err := reconstructBlockComplete(conn, consensus.mempool, notification.Block)
+	if err != nil {
+		return err
+	}
+
+	if err = txs_validator.TxsValidator.ValidateTxs(blkComplete.Txs); err != nil {
+		return err
+	}
+
+	if err = blkComplete.BloomAll(); err != nil {
+		return err
+	}
+
+	if !bytes.Equal(blkComplete.Bloom.Hash, notification.Chain.Hash) {
+		return errors.New("Compact block hash is not matching")
+	}
+
+	//the peer has the block already
+	consensus.tipReceived(conn, notification.Chain)
+
+	if _, err = consensus.chain.AddBlocks([]*block_complete.BlockComplete{blkComplete}, false, conn.UUID); err != nil {
+		return err
+	}
+
+	metricCompactBlocks.Inc("reconstructed")
+	return nil
+}
+
+func (consensus *Consensus) ChainUpdateCompact(conn *connection.AdvancedConnection, data []byte) (interface{}, error) {
+
+	metricCompactBlocks.Inc("announce_received")
+	metricTipBytes.Add("announce_compact_received", uint64(len(data)))
+
+	notification := &ChainUpdateCompactNotification{}
+	if err := msgpack.Unmarshal(data, notification); err != nil {
+		return nil, err
+	}
+
+	if notification.Chain == nil || notification.Chain.BigTotalDifficulty == nil || notification.Block == nil {
+		return nil, errors.New("Compact block notification is invalid")
+	}
+
+	if len(notification.Chain.Hash) != cryptography.HashSize {
+		return nil, errors.New("Chain Update Hash Length is invalid")
+	}
+
+	if config.NODE_CONSENSUS == config.NODE_CONSENSUS_TYPE_FULL {
+		if err := consensus.includeCompactBlock(conn, notification); err != nil {
+			metricCompactBlocks.Inc("failed")
+			if config.DEBUG {
+				gui.GUI.Error(gui_logger.SUBSYSTEM_NETWORK, "Compact block was not included", err)
+			}
+		}
+	}
+
+	//the block was included or it will be downloaded as a fork
+	return consensus.ChainUpdateProcess(conn, notification.Chain)
+}
@@ -5,12 +5,14 @@ import (
 	"pandora-pay/helpers/generics"
 	"pandora-pay/helpers/recovery"
 	"pandora-pay/mempool"
+	"pandora-pay/network/websocks/connection/advanced_connection_types"
 )
 
 type Consensus struct {
 	chain   *blockchain.Blockchain
 	mempool *mempool.Mempool
 	forks   *Forks
+	tips    *chainTips
 }
 
 func (consensus *Consensus) execute() {
@@ -27,6 +29,9 @@ func NewConsensus(chain *blockchain.Blockchain, mempool *mempool.Mempool) *Conse
 		&Forks{
 			hashes: &generics.Map[string, *Fork]{},
 		},
+		&chainTips{
+			peers: make(map[advanced_connection_types.UUID]*peerChainTip),
+		},
 	}
 
 	consensus.execute()
@@ -0,0 +1,191 @@
+package consensus
+
+import (
+	"bytes"
+	"errors"
+	"pandora-pay/blockchain/blocks/block"
+	"pandora-pay/blockchain/blocks/block_complete"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/config"
+	"pandora-pay/cryptography"
+	"pandora-pay/helpers"
+	"pandora-pay/helpers/advanced_buffers"
+	"pandora-pay/helpers/metrics"
+	"pandora-pay/helpers/msgpack"
+	"pandora-pay/mempool"
+	"pandora-pay/network/bandwidth"
+	"pandora-pay/network/websocks/connection"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"strconv"
+	"time"
+)
+
+const COMPACT_BLOCK_SHORT_ID_SIZE = 6
+
+var (
+	metricCompactBlocks   = metrics.NewCounterVec("pandora_compact_blocks_total", "Compact blocks per kind: announce_sent, announce_received, reconstructed, failed and fallback", "kind")
+	metricCompactBlockTxs = metrics.NewCounterVec("pandora_compact_block_txs_total", "Txs of the reconstructed compact blocks per source: mempool and downloaded", "source")
+)
+
+//CompactBlock is the serialized block with the short ids of its txs instead of the txs
+type CompactBlock struct {
+	Block    []byte `json:"block" msgpack:"block"`
+	ShortIds []byte `json:"shortIds" msgpack:"shortIds"` //COMPACT_BLOCK_SHORT_ID_SIZE bytes each
+	priority bandwidth.PriorityType
+}
+
+func (compactBlock *CompactBlock) GetPriority() bandwidth.PriorityType {
+	return compactBlock.priority
+}
+
+//getBlockPriority returns the consensus priority only for the tip. The other blocks are served to the syncing peers
+func (consensus *Consensus) getBlockPriority(hash []byte) bandwidth.PriorityType {
+	if bytes.Equal(consensus.chain.GetChainData().Hash, hash) {
+		return bandwidth.PRIORITY_CONSENSUS
+	}
+	return bandwidth.PRIORITY_HISTORICAL
+}
+
+//getShortTxId salts the tx hash with the block hash, so the short ids can't be precomputed to collide
+func getShortTxId(blockHash, txHash []byte) []byte {
+	return cryptography.SHA3(append(helpers.CloneBytes(blockHash), txHash...))[:COMPACT_BLOCK_SHORT_ID_SIZE]
+}
+
+func loadCompactBlock(reader store_db_interface.StoreDBTransactionInterface, hash []byte) (*CompactBlock, error) {
+
+	blockData := reader.Get("block_ByHash" + string(hash))
+	if blockData == nil {
+		return nil, errors.New("Block was not found")
+	}
+
+	blk := block.CreateEmptyBlock()
+	if err := blk.Deserialize(advanced_buffers.NewBufferReader(blockData)); err != nil {
+		return nil, err
+	}
+
+	txHashes := [][]byte{}
+	if err := msgpack.Unmarshal(reader.Get("blockTxs"+strconv.FormatUint(blk.Height, 10)), &txHashes); err != nil {
+		return nil, err
+	}
+
+	shortIds := make([]byte, 0, len(txHashes)*COMPACT_BLOCK_SHORT_ID_SIZE)
+	for _, txHash := range txHashes {
+		shortIds = append(shortIds, getShortTxId(hash, txHash)...)
+	}
+
+	return &CompactBlock{helpers.CloneBytes(blockData), shortIds, bandwidth.PRIORITY_HISTORICAL}, nil
+}
+
+func (consensus *Consensus) OpenLoadCompactBlock(hash []byte) (compactBlock *CompactBlock, err error) {
+	err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+		compactBlock, err = loadCompactBlock(reader, hash)
+		return
+	})
+	return
+}
+
+//downloadBlockMissingTxs requests from the peer the txs which are nil
+func downloadBlockMissingTxs(conn *connection.AdvancedConnection, blockHash []byte, txs []*transaction.Transaction) error {
+
+	missingTxs := make([]int, 0)
+	for i, tx := range txs {
+		if tx == nil {
+			missingTxs = append(missingTxs, i)
+		}
+	}
+
+	if len(missingTxs) == 0 {
+		return nil
+	}
+
+	blkCompleteMissingTxs, err := connection.SendJSONAwaitAnswer[APIBlockCompleteMissingTxsReply](conn, []byte("block-miss-txs"), &APIBlockCompleteMissingTxsRequest{blockHash, missingTxs}, nil, 0)
+	if err != nil {
+		return err
+	}
+
+	if len(blkCompleteMissingTxs.Txs) != len(missingTxs) {
+		return errors.New("blkCompleteMissingTxs.Txs length is not matching")
+	}
+
+	for _, missingTx := range blkCompleteMissingTxs.Txs {
+		if missingTx == nil {
+			return errors.New("blkCompleteMissingTxs.Tx is null")
+		}
+	}
+
+	for i, missingTx := range missingTxs {
+		tx := &transaction.Transaction{}
+		if err = tx.Deserialize(advanced_buffers.NewBufferReader(blkCompleteMissingTxs.Txs[i])); err != nil {
+			return err
+		}
+		txs[missingTx] = tx
+	}
+
+	return nil
+}
+
+//reconstructBlockComplete rebuilds the block using the mempool txs. Only the txs which are not found are downloaded from the peer
+func reconstructBlockComplete(conn *connection.AdvancedConnection, mempool *mempool.Mempool, compactBlock *CompactBlock) (*block_complete.BlockComplete, error) {
+
+	if len(compactBlock.ShortIds)%COMPACT_BLOCK_SHORT_ID_SIZE != 0 || uint64(len(compactBlock.ShortIds)) > config.BLOCK_MAX_SIZE {
+		return nil, errors.New("Compact block short ids are invalid")
+	}
+
+	blk := block.CreateEmptyBlock()
+	if err := blk.Deserialize(advanced_buffers.NewBufferReader(compactBlock.Block)); err != nil {
+		return nil, err
+	}
+
+	//txs with colliding short ids are downloaded
+	mempoolTxs := make(map[string]*transaction.Transaction)
+	for _, tx := range mempool.Txs.GetTxsOnlyList() {
+		shortId := string(getShortTxId(blk.Bloom.Hash, tx.Bloom.Hash))
+		if _, exists := mempoolTxs[shortId]; exists {
+			mempoolTxs[shortId] = nil
+		} else {
+			mempoolTxs[shortId] = tx
+		}
+	}
+
+	txs := make([]*transaction.Transaction, len(compactBlock.ShortIds)/COMPACT_BLOCK_SHORT_ID_SIZE)
+	for i := range txs {
+		txs[i] = mempoolTxs[string(compactBlock.ShortIds[i*COMPACT_BLOCK_SHORT_ID_SIZE:(i+1)*COMPACT_BLOCK_SHORT_ID_SIZE])]
+	}
+
+	found := uint64(0)
+	for _, tx := range txs {
+		if tx != nil {
+			found += 1
+		}
+	}
+
+	metricCompactBlockTxs.Add("mempool", found)
+	metricCompactBlockTxs.Add("downloaded", uint64(len(txs))-found)
+
+	if err := downloadBlockMissingTxs(conn, blk.Bloom.Hash, txs); err != nil {
+		return nil, err
+	}
+
+	blkComplete := block_complete.CreateEmptyBlockComplete()
+	blkComplete.Block = blk
+	blkComplete.Txs = txs
+
+	return blkComplete, nil
+}
+
+//marshalCompactBlockAnnouncement returns the tip together with the compact block, so the full peers can include the block without downloading it. It returns nil when the block is not stored
+func (consensus *Consensus) marshalCompactBlockAnnouncement(notification *ChainUpdateNotification) ([]byte, error) {
+	compactBlock, err := consensus.OpenLoadCompactBlock(notification.Hash)
+	if err != nil || compactBlock == nil {
+		return nil, nil
+	}
+	return msgpack.Marshal(&ChainUpdateCompactNotification{notification, compactBlock})
+}
+
+//announceCompactBlock sends the marshaled compact block announcement to a full peer supporting compact blocks
+func (consensus *Consensus) announceCompactBlock(conn *connection.AdvancedConnection, announcement *TipAnnouncement, ctxDuration time.Duration) error {
+	metricCompactBlocks.Inc("announce_sent")
+	metricTipBytes.Add("announce_compact_sent", uint64(len(announcement.Compact)))
+	return conn.Send([]byte("chain-update-compact"), announcement.Compact, ctxDuration)
+}
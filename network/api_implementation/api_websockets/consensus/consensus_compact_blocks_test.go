@@ -0,0 +1,104 @@
+package consensus
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/blockchain"
+	"pandora-pay/helpers"
+	"pandora-pay/helpers/generics"
+	"pandora-pay/helpers/msgpack"
+	"pandora-pay/network/network_config"
+	"pandora-pay/network/websocks/connection"
+	"pandora-pay/network/websocks/connection/advanced_connection_types"
+	"pandora-pay/network/websocks/websock"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type testTransport struct {
+	reads     chan []byte
+	writes    chan []byte
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func (transport *testTransport) ReadMessage() (int, []byte, error) {
+	select {
+	case data := <-transport.reads:
+		return websock.BinaryMessage, data, nil
+	case <-transport.closed:
+		return 0, nil, errors.New("Closed")
+	}
+}
+
+func (transport *testTransport) WriteMessage(messageType int, data []byte) error {
+	transport.writes <- data
+	return nil
+}
+
+func (transport *testTransport) SetReadLimit(limit int64)                  {}
+func (transport *testTransport) SetReadDeadline(t time.Time) error         { return nil }
+func (transport *testTransport) SetWriteDeadline(t time.Time) error        { return nil }
+func (transport *testTransport) SetPongHandler(handler func(string) error) {}
+
+func (transport *testTransport) Close() error {
+	transport.closeOnce.Do(func() {
+		close(transport.closed)
+	})
+	return nil
+}
+
+//request sends the request to the connection and returns how long it took to receive the answer
+func (transport *testTransport) request(t *testing.T, id uint32, route string, data []byte) time.Duration {
+
+	message, err := msgpack.Marshal(&advanced_connection_types.AdvancedConnectionMessage{id, false, true, []byte(route), data})
+	assert.Nil(t, err)
+
+	start := time.Now()
+	transport.reads <- message
+
+	select {
+	case <-transport.writes:
+	case <-time.After(10 * time.Second):
+		assert.Fail(t, "Answer was not received")
+	}
+	return time.Since(start)
+}
+
+func TestCompactBlockPriority(t *testing.T) {
+
+	atomic.StoreInt64(&network_config.NETWORK_PEER_UPLOAD_LIMIT, 1000)
+	defer atomic.StoreInt64(&network_config.NETWORK_PEER_UPLOAD_LIMIT, 0)
+
+	tipHash := helpers.RandomBytes(32)
+
+	chain := &blockchain.Blockchain{ChainData: &generics.Value[*blockchain.BlockchainData]{}}
+	chain.ChainData.Store(&blockchain.BlockchainData{Hash: tipHash})
+	consensus := &Consensus{chain: chain}
+
+	getMap := map[string]func(conn *connection.AdvancedConnection, values []byte) (any, error){
+		"chain-update": func(conn *connection.AdvancedConnection, values []byte) (any, error) {
+			return make([]byte, 3000), nil
+		},
+		"block-compact": func(conn *connection.AdvancedConnection, values []byte) (any, error) {
+			return &CompactBlock{nil, nil, consensus.getBlockPriority(values)}, nil
+		},
+	}
+
+	transport := &testTransport{make(chan []byte), make(chan []byte), make(chan struct{}), sync.Once{}}
+	conn, err := connection.NewAdvancedConnection(transport, "test", nil, getMap, true, nil, nil, func(*connection.AdvancedConnection) {}, func(*connection.AdvancedConnection, int32) bool { return true })
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	go conn.ReadPump()
+
+	//the consensus messages are never delayed, they put the peer limit in debt
+	assert.Less(t, transport.request(t, 1, "chain-update", nil), 500*time.Millisecond, "Consensus message was delayed")
+	assert.Less(t, transport.request(t, 2, "block-compact", tipHash), 500*time.Millisecond, "Compact tip was delayed")
+
+	//the compact blocks below the tip are served to the syncing peers
+	assert.Greater(t, transport.request(t, 3, "block-compact", helpers.RandomBytes(32)), time.Second, "Historical compact block was not throttled")
+
+}
@@ -11,6 +11,7 @@ import (
 	"pandora-pay/config/globals"
 	"pandora-pay/cryptography"
 	"pandora-pay/gui"
+	"pandora-pay/gui/gui_logger"
 	"pandora-pay/helpers/advanced_buffers"
 	"pandora-pay/mempool"
 	"pandora-pay/network/api_code/api_code_types"
@@ -40,79 +41,60 @@ func (thread *ConsensusProcessForksThread) downloadBlockHash(conn *connection.Ad
 	return answer.Hash, nil
 }
 
-func (thread *ConsensusProcessForksThread) downloadBlockComplete(conn *connection.AdvancedConnection, fork *Fork, height uint64) (*block_complete.BlockComplete, error) {
+//validateBlockComplete checks the txs and the merkle root of the downloaded block
+func validateBlockComplete(blkComplete *block_complete.BlockComplete) error {
+	if err := txs_validator.TxsValidator.ValidateTxs(blkComplete.Txs); err != nil {
+		return err
+	}
+	return blkComplete.BloomAll()
+}
 
-	blkWithTx, err := connection.SendJSONAwaitAnswer[api_common.APIBlockReply](conn, []byte("block"), &api_common.APIBlockRequest{height, nil, api_code_types.RETURN_SERIALIZED}, nil, 0)
-	if err != nil {
-		return nil, err
+//downloadBlockComplete falls back to the block with the full tx hashes when the compact block can't be reconstructed
+func (thread *ConsensusProcessForksThread) downloadBlockComplete(conn *connection.AdvancedConnection, fork *Fork, height uint64) (blkComplete *block_complete.BlockComplete, err error) {
+
+	if conn.HasFeature(connection.FEATURE_COMPACT_BLOCKS) {
+
+		var compactBlock *CompactBlock
+		if compactBlock, err = connection.SendJSONAwaitAnswer[CompactBlock](conn, []byte("block-compact"), &APIBlockCompactRequest{height, nil}, nil, 0); err != nil {
+			return
+		}
+
+		if blkComplete, err = reconstructBlockComplete(conn, thread.mempool, compactBlock); err == nil {
+			if err = validateBlockComplete(blkComplete); err == nil {
+				return
+			}
+		}
+
+		//a short id can collide with another mempool tx, so the block is downloaded again with the full tx hashes
+		metricCompactBlocks.Inc("fallback")
+	}
+
+	var blkWithTx *api_common.APIBlockReply
+	if blkWithTx, err = connection.SendJSONAwaitAnswer[api_common.APIBlockReply](conn, []byte("block"), &api_common.APIBlockRequest{height, nil, api_code_types.RETURN_SERIALIZED}, nil, 0); err != nil {
+		return
 	}
 
 	blkWithTx.Block = block.CreateEmptyBlock()
 	if err = blkWithTx.Block.Deserialize(advanced_buffers.NewBufferReader(blkWithTx.BlockSerialized)); err != nil {
-		return nil, err
+		return
 	}
 
-	txsFound := 0
 	txs := make([]*transaction.Transaction, len(blkWithTx.Txs))
 	for i := range txs {
 		if tx := thread.mempool.Txs.Get(string(blkWithTx.Txs[i])); tx != nil {
 			txs[i] = tx.Tx
-			txsFound++
-			continue
 		}
 	}
 
-	blkComplete := block_complete.CreateEmptyBlockComplete()
-	blkComplete.Block = blkWithTx.Block
-
-	missingTxsCount := 0
-	for _, tx := range txs {
-		if tx == nil {
-			missingTxsCount += 1
-		}
-	}
-
-	if missingTxsCount > 0 {
-		missingTxs := make([]int, missingTxsCount)
-		c := 0
-		for i, tx := range txs {
-			if tx == nil {
-				missingTxs[c] = i
-				c++
-			}
-		}
-
-		blkCompleteMissingTxs, err := connection.SendJSONAwaitAnswer[APIBlockCompleteMissingTxsReply](conn, []byte("block-miss-txs"), &APIBlockCompleteMissingTxsRequest{blkWithTx.Block.Bloom.Hash, missingTxs}, nil, 0)
-		if err != nil {
-			return nil, err
-		}
-
-		if len(blkCompleteMissingTxs.Txs) != len(missingTxs) {
-			return nil, errors.New("blkCompleteMissingTxs.Txs length is not matching")
-		}
-
-		for _, missingTx := range blkCompleteMissingTxs.Txs {
-			if missingTx == nil {
-				return nil, errors.New("blkCompleteMissingTxs.Tx is null")
-			}
-		}
-
-		for i, missingTx := range missingTxs {
-			tx := &transaction.Transaction{}
-			if err = tx.Deserialize(advanced_buffers.NewBufferReader(blkCompleteMissingTxs.Txs[i])); err != nil {
-				return nil, err
-			}
-			txs[missingTx] = tx
-		}
+	if err = downloadBlockMissingTxs(conn, blkWithTx.Block.Bloom.Hash, txs); err != nil {
+		return
 	}
 
+	blkComplete = block_complete.CreateEmptyBlockComplete()
+	blkComplete.Block = blkWithTx.Block
 	blkComplete.Txs = txs
 
-	if err = txs_validator.TxsValidator.ValidateTxs(txs); err != nil {
-		return nil, err
-	}
-
-	if err = blkComplete.BloomAll(); err != nil {
+	if err = validateBlockComplete(blkComplete); err != nil {
 		return nil, err
 	}
 
@@ -147,6 +129,11 @@ func (thread *ConsensusProcessForksThread) downloadFork(fork *Fork) bool {
 			return false
 		}
 
+		//the finalized blocks can't be replaced
+		if config.FINALITY_DEPTH > 0 && chainData.Height-start > config.FINALITY_DEPTH {
+			return false
+		}
+
 		if fork.errors > 2 {
 			return false
 		}
@@ -166,6 +153,10 @@ func (thread *ConsensusProcessForksThread) downloadFork(fork *Fork) bool {
 			continue
 		}
 
+		if checkpoint := config.GetCheckpoint(start - 1); checkpoint != nil && !bytes.Equal(checkpoint, hash) {
+			return false
+		}
+
 		chainHash, err := thread.chain.OpenLoadBlockHash(start - 1)
 		if err == nil && bytes.Equal(hash, chainHash) {
 			break
@@ -261,7 +252,7 @@ func (thread *ConsensusProcessForksThread) execute() {
 
 						if _, err := thread.chain.AddBlocks(blocks, false, advanced_connection_types.UUID_ALL); err != nil {
 							if config.DEBUG {
-								gui.GUI.Error("Invalid Fork", err)
+								gui.GUI.Error(gui_logger.SUBSYSTEM_NETWORK, "Invalid Fork", err)
 							}
 						} else {
 							fork.Lock()
@@ -278,7 +269,7 @@ func (thread *ConsensusProcessForksThread) execute() {
 
 			} else {
 				globals.MainEvents.BroadcastEvent("consensus/update", fork)
-				gui.GUI.Log("Status. AddBlocks fork - Simulating block")
+				gui.GUI.Log(gui_logger.SUBSYSTEM_NETWORK, "Status. AddBlocks fork - Simulating block")
 
 				newChainData := &blockchain.BlockchainData{
 					Height:             fork.End,
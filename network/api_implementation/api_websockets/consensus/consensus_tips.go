@@ -0,0 +1,171 @@
+package consensus
+
+import (
+	"bytes"
+	"pandora-pay/blockchain"
+	"pandora-pay/config"
+	"pandora-pay/helpers/metrics"
+	"pandora-pay/helpers/msgpack"
+	"pandora-pay/network/websocks/connection"
+	"pandora-pay/network/websocks/connection/advanced_connection_types"
+	"sync"
+	"time"
+)
+
+var (
+	metricTipMessages = metrics.NewCounterVec("pandora_chain_tip_messages_total", "Chain tip messages per kind: announce_sent, announce_skipped, announce_received, poll and poll_skipped", "kind")
+	metricTipBytes    = metrics.NewCounterVec("pandora_chain_tip_bytes_total", "Bytes of the chain tip messages per kind: announce_sent, announce_compact_sent, announce_received, announce_compact_received and poll", "kind")
+)
+
+type peerChainTip struct {
+	announced []byte    //last tip announced to the peer
+	received  time.Time //last time the peer sent its tip
+}
+
+//chainTips tracks the tips exchanged with every peer. The peers announce their new tips, so a peer is polled only when it was silent for longer than the block time
+type chainTips struct {
+	peers map[advanced_connection_types.UUID]*peerChainTip
+	lock  sync.Mutex
+}
+
+func (tips *chainTips) get(uuid advanced_connection_types.UUID) *peerChainTip {
+	tip := tips.peers[uuid]
+	if tip == nil {
+		tip = &peerChainTip{}
+		tips.peers[uuid] = tip
+	}
+	return tip
+}
+
+//tipReceived is called for every tip sent by the peer. The peer already has its tip, so it won't be announced back
+func (consensus *Consensus) tipReceived(conn *connection.AdvancedConnection, chainUpdateNotification *ChainUpdateNotification) {
+	consensus.tips.lock.Lock()
+	defer consensus.tips.lock.Unlock()
+
+	tip := consensus.tips.get(conn.UUID)
+	tip.received = time.Now()
+	tip.announced = chainUpdateNotification.Hash
+}
+
+//markAnnounced returns false when the hash was already announced to the peer. Force marks it anyway
+func (tips *chainTips) markAnnounced(conn *connection.AdvancedConnection, hash []byte, force bool) bool {
+	tips.lock.Lock()
+	defer tips.lock.Unlock()
+
+	tip := tips.get(conn.UUID)
+	if !force && bytes.Equal(tip.announced, hash) {
+		return false
+	}
+	tip.announced = hash
+	return true
+}
+
+//TipAnnouncement is the tip marshaled once for all the peers
+type TipAnnouncement struct {
+	Hash    []byte
+	Tip     []byte //chain-update
+	Compact []byte //chain-update-compact, nil when the block is not stored
+}
+
+//NewTipAnnouncement marshals the tip once for all the peers. Nodes without blocks stored announce only the tip
+func (consensus *Consensus) NewTipAnnouncement(newChainData *blockchain.BlockchainData) (*TipAnnouncement, error) {
+
+	notification := consensus.GetUpdateNotification(newChainData)
+
+	data, err := msgpack.Marshal(notification)
+	if err != nil {
+		return nil, err
+	}
+
+	announcement := &TipAnnouncement{notification.Hash, data, nil}
+	if announcement.Compact, err = consensus.marshalCompactBlockAnnouncement(notification); err != nil {
+		return nil, err
+	}
+
+	return announcement, nil
+}
+
+//Announce sends the tip to the peer in case it was not announced before. The full peers supporting it receive the compact block as well
+func (consensus *Consensus) Announce(conn *connection.AdvancedConnection, announcement *TipAnnouncement, ctxDuration time.Duration) error {
+
+	compact := announcement.Compact != nil && conn.Handshake.Consensus == config.NODE_CONSENSUS_TYPE_FULL && conn.HasFeature(connection.FEATURE_COMPACT_BLOCKS)
+	if !compact && conn.Handshake.Consensus != config.NODE_CONSENSUS_TYPE_FULL && conn.Handshake.Consensus != config.NODE_CONSENSUS_TYPE_APP {
+		return nil
+	}
+
+	if !consensus.tips.markAnnounced(conn, announcement.Hash, false) {
+		metricTipMessages.Inc("announce_skipped")
+		return nil
+	}
+
+	if compact {
+		return consensus.announceCompactBlock(conn, announcement, ctxDuration)
+	}
+
+	metricTipMessages.Inc("announce_sent")
+	metricTipBytes.Add("announce_sent", uint64(len(announcement.Tip)))
+	return conn.Send([]byte("chain-update"), announcement.Tip, ctxDuration)
+}
+
+//AnnounceTip sends the tip to the peer in case it was not announced before. Force sends it anyway
+func (consensus *Consensus) AnnounceTip(conn *connection.AdvancedConnection, chainUpdateNotification *ChainUpdateNotification, force bool, ctxDuration time.Duration) error {
+
+	if !consensus.tips.markAnnounced(conn, chainUpdateNotification.Hash, force) {
+		metricTipMessages.Inc("announce_skipped")
+		return nil
+	}
+
+	data, err := msgpack.Marshal(chainUpdateNotification)
+	if err != nil {
+		return err
+	}
+
+	metricTipMessages.Inc("announce_sent")
+	metricTipBytes.Add("announce_sent", uint64(len(data)))
+
+	return conn.Send([]byte("chain-update"), data, ctxDuration)
+}
+
+//ShouldPollTip returns true when the peer didn't send its tip for longer than the block time
+func (consensus *Consensus) ShouldPollTip(conn *connection.AdvancedConnection) bool {
+	consensus.tips.lock.Lock()
+	defer consensus.tips.lock.Unlock()
+
+	if tip := consensus.tips.peers[conn.UUID]; tip != nil && time.Since(tip.received) < time.Duration(config.BLOCK_TIME)*time.Second {
+		metricTipMessages.Inc("poll_skipped")
+		return false
+	}
+	return true
+}
+
+//PollTip asks the peer for its tip using get-chain
+func (consensus *Consensus) PollTip(conn *connection.AdvancedConnection) error {
+
+	out := conn.SendAwaitAnswer([]byte("get-chain"), nil, nil, 0)
+	if out.Err != nil {
+		return out.Err
+	}
+
+	metricTipMessages.Inc("poll")
+	metricTipBytes.Add("poll", uint64(len(out.Out)))
+
+	chainUpdateNotification := &ChainUpdateNotification{}
+	if err := msgpack.Unmarshal(out.Out, chainUpdateNotification); err != nil {
+		return err
+	}
+
+	_, err := consensus.ChainUpdateProcess(conn, chainUpdateNotification)
+	return err
+}
+
+//RemoveDisconnectedTips deletes the tips of the peers which are not connected anymore
+func (consensus *Consensus) RemoveDisconnectedTips(connected map[advanced_connection_types.UUID]bool) {
+	consensus.tips.lock.Lock()
+	defer consensus.tips.lock.Unlock()
+
+	for uuid := range consensus.tips.peers {
+		if !connected[uuid] {
+			delete(consensus.tips.peers, uuid)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package bandwidth
+
+import (
+	"pandora-pay/helpers/metrics"
+	"pandora-pay/network/network_config"
+	"sync/atomic"
+)
+
+type PriorityType uint8
+
+const (
+	PRIORITY_CONSENSUS  PriorityType = iota //handshake, chain tips and the tip block. Never delayed
+	PRIORITY_NORMAL                         //mempool and the other requests
+	PRIORITY_HISTORICAL                     //blocks served to the syncing peers
+	PRIORITIES
+)
+
+func (priority PriorityType) String() string {
+	switch priority {
+	case PRIORITY_CONSENSUS:
+		return "consensus"
+	case PRIORITY_HISTORICAL:
+		return "historical"
+	default:
+		return "normal"
+	}
+}
+
+var routesPriorities = map[string]PriorityType{
+	"handshake":            PRIORITY_CONSENSUS,
+	"get-chain":            PRIORITY_CONSENSUS,
+	"chain-update":         PRIORITY_CONSENSUS,
+	"chain-update-compact": PRIORITY_CONSENSUS,
+	"block-compact":        PRIORITY_HISTORICAL, //consensus only for the tip, see PriorityReply
+	"block-miss-txs":       PRIORITY_HISTORICAL, //consensus only for the tip, see PriorityReply
+	"block":                PRIORITY_HISTORICAL,
+	"block-complete":       PRIORITY_HISTORICAL,
+	"block-hash":           PRIORITY_HISTORICAL,
+	"block-info":           PRIORITY_HISTORICAL,
+}
+
+//PriorityReply is implemented by the replies whose priority depends on their content. The blocks served to the syncing peers use the same routes as the new blocks
+type PriorityReply interface {
+	GetPriority() PriorityType
+}
+
+func GetRoutePriority(route string) PriorityType {
+	if priority, ok := routesPriorities[route]; ok {
+		return priority
+	}
+	return PRIORITY_NORMAL
+}
+
+var (
+	metricSentBytes     = metrics.NewCounterVec("pandora_network_sent_bytes_total", "Bytes sent to the peers per priority: consensus, normal and historical", "priority")
+	metricReceivedBytes = metrics.NewCounterVec("pandora_network_received_bytes_total", "Bytes received from the peers per priority: consensus, normal and historical", "priority")
+)
+
+type bandwidthType struct {
+	Upload   *TokenBucket
+	Download *TokenBucket
+	sent     [PRIORITIES]atomic.Uint64
+	received [PRIORITIES]atomic.Uint64
+}
+
+var Bandwidth *bandwidthType
+
+func (this *bandwidthType) Sent(priority PriorityType, size int) {
+	this.sent[priority].Add(uint64(size))
+	metricSentBytes.Add(priority.String(), uint64(size))
+}
+
+func (this *bandwidthType) Received(priority PriorityType, size int) {
+	this.received[priority].Add(uint64(size))
+	metricReceivedBytes.Add(priority.String(), uint64(size))
+}
+
+//GetTraffic returns the bytes sent and received by priority
+func (this *bandwidthType) GetTraffic() (sent, received map[string]uint64) {
+	sent, received = make(map[string]uint64), make(map[string]uint64)
+	for priority := PriorityType(0); priority < PRIORITIES; priority++ {
+		sent[priority.String()] = this.sent[priority].Load()
+		received[priority.String()] = this.received[priority].Load()
+	}
+	return
+}
+
+func init() {
+	Bandwidth = &bandwidthType{
+		NewTokenBucket(&network_config.NETWORK_UPLOAD_LIMIT),
+		NewTokenBucket(&network_config.NETWORK_DOWNLOAD_LIMIT),
+		[PRIORITIES]atomic.Uint64{},
+		[PRIORITIES]atomic.Uint64{},
+	}
+}
@@ -0,0 +1,108 @@
+package bandwidth
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	TOKEN_BUCKET_MIN_DELAY = 5 * time.Millisecond
+	TOKEN_BUCKET_MAX_DELAY = 100 * time.Millisecond
+)
+
+//TokenBucket limits the bytes per second. The burst is one second of traffic
+//A message is allowed once the bucket is not in debt, so messages bigger than the burst are sent too and the next ones are delayed
+type TokenBucket struct {
+	rate    *int64 //bytes per second, use atomic. 0 is unlimited
+	tokens  float64
+	last    time.Time
+	waiting [PRIORITIES]int //use the mutex
+	lock    *sync.Mutex
+}
+
+func (bucket *TokenBucket) refill(rate float64) {
+	now := time.Now()
+	bucket.tokens += now.Sub(bucket.last).Seconds() * rate
+	if bucket.tokens > rate {
+		bucket.tokens = rate
+	}
+	bucket.last = now
+}
+
+//higherWaiting returns true when messages of a higher priority are waiting
+func (bucket *TokenBucket) higherWaiting(priority PriorityType) bool {
+	for i := PriorityType(0); i < priority; i++ {
+		if bucket.waiting[i] > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+//Wait takes the tokens of the message. The consensus messages are never delayed, but they are counted
+//The other messages wait while the bucket is in debt or while messages of a higher priority are waiting. Delayed is true when the message had to wait
+func (bucket *TokenBucket) Wait(size int, priority PriorityType, closed <-chan struct{}) (delayed bool, err error) {
+
+	rate := float64(atomic.LoadInt64(bucket.rate))
+	if rate <= 0 {
+		return
+	}
+
+	bucket.lock.Lock()
+	defer bucket.lock.Unlock()
+
+	bucket.refill(rate)
+
+	if priority == PRIORITY_CONSENSUS {
+		bucket.tokens -= float64(size)
+		return
+	}
+
+	bucket.waiting[priority]++
+	defer func() {
+		bucket.waiting[priority]--
+	}()
+
+	for bucket.tokens < 0 || bucket.higherWaiting(priority) {
+
+		delay := time.Duration(-bucket.tokens / rate * float64(time.Second))
+		if delay < TOKEN_BUCKET_MIN_DELAY {
+			delay = TOKEN_BUCKET_MIN_DELAY
+		}
+		if delay > TOKEN_BUCKET_MAX_DELAY {
+			delay = TOKEN_BUCKET_MAX_DELAY
+		}
+
+		delayed = true
+
+		bucket.lock.Unlock()
+		select {
+		case <-closed:
+			bucket.lock.Lock()
+			return delayed, errors.New("Closed")
+		case <-time.After(delay):
+		}
+		bucket.lock.Lock()
+
+		//the limit can be changed while the node is running
+		if rate = float64(atomic.LoadInt64(bucket.rate)); rate <= 0 {
+			return
+		}
+		bucket.refill(rate)
+	}
+
+	bucket.tokens -= float64(size)
+	return
+}
+
+func NewTokenBucket(rate *int64) *TokenBucket {
+	return &TokenBucket{
+		rate,
+		float64(atomic.LoadInt64(rate)),
+		time.Now(),
+		[PRIORITIES]int{},
+		&sync.Mutex{},
+	}
+}
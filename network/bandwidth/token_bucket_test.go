@@ -0,0 +1,37 @@
+package bandwidth
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestTokenBucket(t *testing.T) {
+
+	closed := make(chan struct{})
+
+	unlimited := int64(0)
+	delayed, err := NewTokenBucket(&unlimited).Wait(1000000, PRIORITY_HISTORICAL, closed)
+	assert.Nil(t, err)
+	assert.False(t, delayed, "Unlimited bucket should not delay")
+
+	rate := int64(1000)
+	bucket := NewTokenBucket(&rate)
+
+	delayed, err = bucket.Wait(1500, PRIORITY_NORMAL, closed)
+	assert.Nil(t, err)
+	assert.False(t, delayed, "Full bucket should not delay")
+
+	delayed, err = bucket.Wait(1000, PRIORITY_CONSENSUS, closed)
+	assert.Nil(t, err)
+	assert.False(t, delayed, "Consensus messages should not be delayed")
+
+	delayed, err = bucket.Wait(10, PRIORITY_HISTORICAL, closed)
+	assert.Nil(t, err)
+	assert.True(t, delayed, "Bucket in debt should delay")
+
+	close(closed)
+	bucket.tokens = -1000000
+	_, err = bucket.Wait(10, PRIORITY_NORMAL, closed)
+	assert.NotNil(t, err, "Closed connection should stop waiting")
+
+}
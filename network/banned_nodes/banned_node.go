@@ -7,6 +7,7 @@ import (
 
 type BannedNode struct {
 	URL        *url.URL
+	NodeId     string
 	Timestamp  time.Time
 	Expiration time.Time
 	Message    string
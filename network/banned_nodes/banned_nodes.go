@@ -10,6 +10,7 @@ type BannedNodesType struct {
 	bannedMap *generics.Map[string, *BannedNode]
 }
 
+//IsBanned accepts both urls and node ids as keys
 func (this *BannedNodesType) IsBanned(urlStr string) bool {
 	if _, found := this.bannedMap.Load(urlStr); found {
 		return true
@@ -17,6 +18,11 @@ func (this *BannedNodesType) IsBanned(urlStr string) bool {
 	return false
 }
 
+//IsBannedNode checks the url and the node id. A node with identity is banned by its id, no matter the url it uses
+func (this *BannedNodesType) IsBannedNode(urlStr, nodeId string) bool {
+	return (urlStr != "" && this.IsBanned(urlStr)) || (nodeId != "" && this.IsBanned(nodeId))
+}
+
 func (this *BannedNodesType) Ban(url *url.URL, urlStr, message string, duration time.Duration) {
 	if urlStr == "" {
 		urlStr = url.String()
@@ -30,6 +36,17 @@ func (this *BannedNodesType) Ban(url *url.URL, urlStr, message string, duration
 	})
 }
 
+//BanNodeId bans the identity of the node, no matter the url it uses
+func (this *BannedNodesType) BanNodeId(nodeId, message string, duration time.Duration) {
+	time := time.Now()
+	this.bannedMap.Store(nodeId, &BannedNode{
+		NodeId:     nodeId,
+		Message:    message,
+		Timestamp:  time,
+		Expiration: time.Add(duration),
+	})
+}
+
 var BannedNodes *BannedNodesType
 
 func init() {
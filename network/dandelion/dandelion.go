@@ -0,0 +1,410 @@
+package dandelion
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"pandora-pay/blockchain"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/blockchain/transactions/transaction/transaction_simple"
+	"pandora-pay/blockchain/transactions/transaction/transaction_type"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether"
+	"pandora-pay/config"
+	"pandora-pay/helpers/metrics"
+	"pandora-pay/helpers/msgpack"
+	"pandora-pay/helpers/recovery"
+	"pandora-pay/mempool"
+	"pandora-pay/network/network_config"
+	"pandora-pay/network/websocks"
+	"pandora-pay/network/websocks/connection"
+	"pandora-pay/network/websocks/connection/advanced_connection_types"
+	"sync"
+	"time"
+)
+
+var metricDandelionTxs = metrics.NewCounterVec("pandora_dandelion_txs_total", "Dandelion txs per kind: stem_sent, stem_received, fluffed and embargo_expired", "kind")
+
+type APIMempoolStemTxRequest struct {
+	Tx []byte `json:"tx" msgpack:"tx"`
+}
+
+type stemTx struct {
+	tx      *transaction.Transaction
+	embargo time.Time //the tx is diffused by this node in case it was not seen diffused until then
+	from    advanced_connection_types.UUID
+	senders []string //accounts and nonces spent by the tx
+	changed []string //accounts and nonces changed by the tx, the senders included
+	parents []string //own stem txs the tx was built on. The tx is held and diffused after them
+}
+
+//getStemAccounts returns the keys used to detect the conflicts between the stem txs. A zether tx changes the balances of all the accounts of the ring and requires the balances of the senders to stay the same
+func getStemAccounts(tx *transaction.Transaction) (senders, changed []string) {
+
+	switch tx.Version {
+	case transaction_type.TX_SIMPLE:
+		base := tx.TransactionBaseInterface.(*transaction_simple.TransactionSimple)
+		senders = []string{"simple:" + string(base.Vin.PublicKey)}
+		changed = senders
+	case transaction_type.TX_ZETHER:
+		base := tx.TransactionBaseInterface.(*transaction_zether.TransactionZether)
+		for payloadIndex, payload := range base.Payloads {
+			nonce := "nonce:" + string(base.Bloom.Nonces[payloadIndex])
+			senders = append(senders, nonce)
+			changed = append(changed, nonce)
+			for i, publicKey := range base.Bloom.PublicKeyLists[payloadIndex] {
+				key := "account:" + string(payload.Asset) + string(publicKey)
+				if (i%2 == 0) == payload.Parity {
+					senders = append(senders, key)
+				}
+				changed = append(changed, key)
+			}
+		}
+	}
+
+	return
+}
+
+//DandelionType relays the new txs along a stem of single peers before diffusing them, so the first node broadcasting a tx is not its origin
+type DandelionType struct {
+	chain    *blockchain.Blockchain
+	mempool  *mempool.Mempool
+	txs      map[string]*stemTx                     //stem txs are not added in the mempool to not reveal them
+	peers    map[advanced_connection_types.UUID]int //stem txs held per peer
+	senders  map[string]int
+	changed  map[string]int
+	epochEnd time.Time
+	fluff    bool //during the epoch the stem txs received are diffused
+	relays   []*connection.AdvancedConnection
+	routes   map[advanced_connection_types.UUID]*connection.AdvancedConnection //every inbound peer is routed to the same relay during the epoch
+	lock     sync.Mutex
+}
+
+var Dandelion *DandelionType
+
+//is locked before
+func (dandelion *DandelionType) newEpoch() {
+
+	dandelion.epochEnd = time.Now().Add(network_config.DANDELION_EPOCH)
+	dandelion.fluff = rand.Float64() < network_config.DANDELION_FLUFF_PROBABILITY
+	dandelion.routes = make(map[advanced_connection_types.UUID]*connection.AdvancedConnection)
+
+	list := make([]*connection.AdvancedConnection, 0)
+	for _, conn := range websocks.Websockets.GetAllSockets() {
+		if conn.Handshake.Consensus == config.NODE_CONSENSUS_TYPE_FULL && conn.HasFeature(connection.FEATURE_DANDELION) {
+			list = append(list, conn)
+		}
+	}
+
+	rand.Shuffle(len(list), func(i, j int) {
+		list[i], list[j] = list[j], list[i]
+	})
+	if len(list) > network_config.DANDELION_RELAYS {
+		list = list[:network_config.DANDELION_RELAYS]
+	}
+	dandelion.relays = list
+}
+
+//is locked before
+func (dandelion *DandelionType) getRoute(from advanced_connection_types.UUID) *connection.AdvancedConnection {
+
+	relaysClosed := false
+	for _, relay := range dandelion.relays {
+		if relay.IsClosed.IsSet() {
+			relaysClosed = true
+		}
+	}
+
+	if time.Now().After(dandelion.epochEnd) || relaysClosed || len(dandelion.relays) == 0 {
+		dandelion.newEpoch()
+	}
+
+	if len(dandelion.relays) == 0 {
+		return nil
+	}
+
+	route := dandelion.routes[from]
+	if route == nil {
+		route = dandelion.relays[rand.Intn(len(dandelion.relays))]
+		dandelion.routes[from] = route
+	}
+
+	//the tx is not sent back
+	if route.UUID == from {
+		return nil
+	}
+
+	return route
+}
+
+//is locked before
+func (dandelion *DandelionType) conflicts(senders, changed []string) bool {
+	for _, key := range senders {
+		if dandelion.changed[key] > 0 {
+			return true
+		}
+	}
+	for _, key := range changed {
+		if dandelion.senders[key] > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+//is locked before
+func (dandelion *DandelionType) getOwnParents(senders, changed []string) []string {
+
+	parents := make([]string, 0)
+	for hashStr, stem := range dandelion.txs {
+		if stem.from != advanced_connection_types.UUID_ALL {
+			continue
+		}
+
+		found := false
+		for _, key := range senders {
+			for _, key2 := range stem.changed {
+				if key == key2 {
+					found = true
+				}
+			}
+		}
+		for _, key := range changed {
+			for _, key2 := range stem.senders {
+				if key == key2 {
+					found = true
+				}
+			}
+		}
+		if found {
+			parents = append(parents, hashStr)
+		}
+	}
+	return parents
+}
+
+//is locked before
+func (dandelion *DandelionType) isHeld(hashes []string) bool {
+	for _, hashStr := range hashes {
+		if dandelion.txs[hashStr] != nil {
+			return true
+		}
+	}
+	return false
+}
+
+//is locked before
+func (dandelion *DandelionType) addTx(hashStr string, stem *stemTx) {
+	dandelion.txs[hashStr] = stem
+	dandelion.peers[stem.from] += 1
+	for _, key := range stem.senders {
+		dandelion.senders[key] += 1
+	}
+	for _, key := range stem.changed {
+		dandelion.changed[key] += 1
+	}
+}
+
+//is locked before
+func (dandelion *DandelionType) removeTx(hashStr string) *stemTx {
+
+	stem := dandelion.txs[hashStr]
+	if stem == nil {
+		return nil
+	}
+
+	delete(dandelion.txs, hashStr)
+	if dandelion.peers[stem.from] -= 1; dandelion.peers[stem.from] <= 0 {
+		delete(dandelion.peers, stem.from)
+	}
+	for _, key := range stem.senders {
+		if dandelion.senders[key] -= 1; dandelion.senders[key] <= 0 {
+			delete(dandelion.senders, key)
+		}
+	}
+	for _, key := range stem.changed {
+		if dandelion.changed[key] -= 1; dandelion.changed[key] <= 0 {
+			delete(dandelion.changed, key)
+		}
+	}
+	return stem
+}
+
+//Exists returns true when the tx is held in the stem phase
+func (dandelion *DandelionType) Exists(hashStr string) bool {
+	dandelion.lock.Lock()
+	defer dandelion.lock.Unlock()
+	return dandelion.txs[hashStr] != nil
+}
+
+//GetOwnTxs returns the txs created by this node which are held in the stem phase
+func (dandelion *DandelionType) GetOwnTxs() []*transaction.Transaction {
+	dandelion.lock.Lock()
+	defer dandelion.lock.Unlock()
+
+	txs := make([]*transaction.Transaction, 0)
+	for _, stem := range dandelion.txs {
+		if stem.from == advanced_connection_types.UUID_ALL {
+			txs = append(txs, stem.tx)
+		}
+	}
+	return txs
+}
+
+//Loop diffuses the tx in case it is held in the stem phase, as receiving it again means the stem made a loop. It is checked before validating the tx again
+func (dandelion *DandelionType) Loop(hashStr string) bool {
+
+	dandelion.lock.Lock()
+	stem := dandelion.txs[hashStr]
+	dandelion.lock.Unlock()
+
+	if stem == nil {
+		return false
+	}
+
+	dandelion.fluffTx(stem.tx)
+	return true
+}
+
+//Stem relays the tx to the next peer of the stem. It returns false when the tx must be diffused instead
+//The txs created by this node (from is UUID_ALL) are always relayed. The txs received which conflict with the stem txs held are rejected
+//An own tx built on top of own stem txs is held, without being relayed, and it is diffused after them. Relaying it would be rejected by the peers as a conflict
+func (dandelion *DandelionType) Stem(tx *transaction.Transaction, from advanced_connection_types.UUID) (bool, error) {
+
+	dandelion.lock.Lock()
+
+	if from != advanced_connection_types.UUID_ALL {
+		metricDandelionTxs.Inc("stem_received")
+	}
+
+	//the stem made a loop
+	if dandelion.removeTx(tx.Bloom.HashStr) != nil {
+		dandelion.lock.Unlock()
+		return false, nil
+	}
+
+	senders, changed := getStemAccounts(tx)
+	embargo := time.Now().Add(network_config.DANDELION_EMBARGO_MIN + time.Duration(rand.Int63n(int64(network_config.DANDELION_EMBARGO_RANDOM))))
+
+	if from == advanced_connection_types.UUID_ALL {
+		if parents := dandelion.getOwnParents(senders, changed); len(parents) > 0 {
+			dandelion.addTx(tx.Bloom.HashStr, &stemTx{tx, embargo, from, senders, changed, parents})
+			dandelion.lock.Unlock()
+			return true, nil
+		}
+	} else {
+
+		if dandelion.conflicts(senders, changed) {
+			dandelion.lock.Unlock()
+			return false, errors.New("Stem tx conflicts with another stem tx")
+		}
+
+		//during a fluff epoch the relayed txs are diffused. The own txs are always relayed
+		if time.Now().After(dandelion.epochEnd) {
+			dandelion.newEpoch()
+		}
+		if dandelion.fluff || dandelion.peers[from] >= network_config.DANDELION_MAX_PEER_TXS {
+			dandelion.lock.Unlock()
+			return false, nil
+		}
+	}
+
+	if len(dandelion.txs) >= network_config.DANDELION_MAX_TXS {
+		dandelion.lock.Unlock()
+		return false, nil
+	}
+
+	route := dandelion.getRoute(from)
+	if route == nil {
+		dandelion.lock.Unlock()
+		return false, nil
+	}
+
+	dandelion.addTx(tx.Bloom.HashStr, &stemTx{tx, embargo, from, senders, changed, nil})
+	dandelion.lock.Unlock()
+
+	data, err := msgpack.Marshal(&APIMempoolStemTxRequest{tx.Bloom.Serialized})
+	if err != nil {
+		return false, nil
+	}
+
+	recovery.SafeGo(func() {
+		if out := route.SendAwaitAnswer([]byte("mempool/stem-tx"), data, nil, 0); out.Err != nil {
+			//the relay failed and the tx is diffused right away
+			dandelion.fluffTx(tx)
+		}
+	})
+
+	metricDandelionTxs.Inc("stem_sent")
+	return true, nil
+}
+
+//fluffTx adds the tx to the mempool which diffuses it to all the peers
+func (dandelion *DandelionType) fluffTx(tx *transaction.Transaction) {
+
+	dandelion.lock.Lock()
+	dandelion.removeTx(tx.Bloom.HashStr)
+	dandelion.lock.Unlock()
+
+	if dandelion.mempool.Txs.Exists(tx.Bloom.HashStr) {
+		return
+	}
+
+	metricDandelionTxs.Inc("fluffed")
+	dandelion.mempool.AddTxToMempool(tx, dandelion.chain.GetChainData().Height, false, false, false, advanced_connection_types.UUID_ALL, context.Background())
+}
+
+//continuouslyCheckEmbargo diffuses the stem txs which were not seen diffused before the embargo expired and the own txs held once their parents were diffused
+func (dandelion *DandelionType) continuouslyCheckEmbargo() {
+	recovery.SafeGo(func() {
+
+		for {
+
+			now := time.Now()
+			expired := make([]*transaction.Transaction, 0)
+
+			dandelion.lock.Lock()
+			for hashStr, stem := range dandelion.txs {
+				if dandelion.mempool.Txs.Exists(hashStr) {
+					dandelion.removeTx(hashStr)
+				} else if len(stem.parents) > 0 {
+					//the parents diffused in this pass are still held, so the tx is added in the mempool after them
+					if !dandelion.isHeld(stem.parents) {
+						expired = append(expired, stem.tx)
+					}
+				} else if now.After(stem.embargo) {
+					expired = append(expired, stem.tx)
+				}
+			}
+			dandelion.lock.Unlock()
+
+			for _, tx := range expired {
+				metricDandelionTxs.Inc("embargo_expired")
+				dandelion.fluffTx(tx)
+			}
+
+			time.Sleep(network_config.DANDELION_EMBARGO_CHECK_TIME)
+		}
+
+	})
+}
+
+func InitDandelion(chain *blockchain.Blockchain, mempool *mempool.Mempool) {
+
+	Dandelion = &DandelionType{
+		chain:   chain,
+		mempool: mempool,
+		txs:     make(map[string]*stemTx),
+		peers:   make(map[advanced_connection_types.UUID]int),
+		senders: make(map[string]int),
+		changed: make(map[string]int),
+		routes:  make(map[advanced_connection_types.UUID]*connection.AdvancedConnection),
+	}
+
+	Dandelion.continuouslyCheckEmbargo()
+
+	mempool.OnStemNewTransaction = func(tx *transaction.Transaction) bool {
+		stemmed, _ := Dandelion.Stem(tx, advanced_connection_types.UUID_ALL)
+		return stemmed
+	}
+	mempool.OnGetStemTransactions = Dandelion.GetOwnTxs
+}
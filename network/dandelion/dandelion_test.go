@@ -0,0 +1,85 @@
+package dandelion
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/blockchain/transactions/transaction/transaction_type"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload"
+	"pandora-pay/gui"
+	"pandora-pay/gui/gui_non_interactive"
+	"pandora-pay/helpers"
+	"pandora-pay/mempool"
+	"pandora-pay/network/websocks/connection"
+	"pandora-pay/network/websocks/connection/advanced_connection_types"
+	"testing"
+	"time"
+)
+
+//createTestZetherTx creates a zether tx spending from the sender with the nonce given
+func createTestZetherTx(sender, nonce []byte) *transaction.Transaction {
+
+	base := &transaction_zether.TransactionZether{
+		Payloads: []*transaction_zether_payload.TransactionZetherPayload{{Asset: []byte{}, Parity: true}},
+		Bloom: &transaction_zether.TransactionZetherBloom{
+			Nonces:         [][]byte{nonce},
+			PublicKeyLists: [][][]byte{{sender, helpers.RandomBytes(33)}},
+		},
+	}
+
+	hash := helpers.RandomBytes(32)
+	return &transaction.Transaction{
+		TransactionBaseInterface: base,
+		Version:                  transaction_type.TX_ZETHER,
+		Bloom:                    &transaction.TransactionBloom{Hash: hash, HashStr: string(hash)},
+	}
+}
+
+func TestStemOwnDependentTxs(t *testing.T) {
+
+	gui.GUI, _ = gui_non_interactive.CreateGUINonInteractive(nil)
+
+	memPool, err := mempool.CreateMempool()
+	assert.Nil(t, err)
+
+	dandelion := &DandelionType{
+		mempool: memPool,
+		txs:     make(map[string]*stemTx),
+		peers:   make(map[advanced_connection_types.UUID]int),
+		senders: make(map[string]int),
+		changed: make(map[string]int),
+		routes:  make(map[advanced_connection_types.UUID]*connection.AdvancedConnection),
+	}
+	memPool.OnGetStemTransactions = dandelion.GetOwnTxs
+
+	sender := helpers.RandomBytes(33)
+
+	//the first own tx was relayed along the stem
+	tx1 := createTestZetherTx(sender, helpers.RandomBytes(32))
+	senders, changed := getStemAccounts(tx1)
+	dandelion.addTx(tx1.Bloom.HashStr, &stemTx{tx1, time.Now().Add(time.Minute), advanced_connection_types.UUID_ALL, senders, changed, nil})
+
+	//the builder sees the own stem tx, so the second tx of the account is built on top of it
+	pendingTxs := memPool.GetPendingTxs()
+	assert.Equal(t, 1, len(pendingTxs))
+	assert.Equal(t, tx1, pendingTxs[0])
+
+	//the second tx is held behind the first one even without a route
+	tx2 := createTestZetherTx(sender, helpers.RandomBytes(32))
+	stemmed, err := dandelion.Stem(tx2, advanced_connection_types.UUID_ALL)
+	assert.Nil(t, err)
+	assert.True(t, stemmed)
+	assert.Equal(t, []string{tx1.Bloom.HashStr}, dandelion.txs[tx2.Bloom.HashStr].parents)
+	assert.Equal(t, 2, len(memPool.GetPendingTxs()))
+
+	//the second tx is diffused only after the first one
+	assert.True(t, dandelion.isHeld(dandelion.txs[tx2.Bloom.HashStr].parents))
+	dandelion.removeTx(tx1.Bloom.HashStr)
+	assert.False(t, dandelion.isHeld(dandelion.txs[tx2.Bloom.HashStr].parents))
+
+	//a tx received from a peer which conflicts with the own stem txs is rejected
+	stemmed, err = dandelion.Stem(createTestZetherTx(sender, helpers.RandomBytes(32)), advanced_connection_types.UUID(1))
+	assert.NotNil(t, err)
+	assert.False(t, stemmed)
+
+}
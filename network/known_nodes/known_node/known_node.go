@@ -1,21 +1,52 @@
 package known_node
 
 import (
+	"pandora-pay/helpers/generics"
 	"sync/atomic"
 )
 
 type KnownNode struct {
 	URL    string
 	IsSeed bool
+	NodeId string //set once the node proved its identity in the handshake
 }
 
 type KnownNodeScored struct {
 	KnownNode
-	Score int32 //use atomic
+	Score        int32                  //use atomic
+	tested       int32                  //use atomic. Only the tested nodes are used for the outbound connections
+	networkGroup generics.Value[string] //resolved when connecting
+	failures     int32                  //use atomic. Consecutive failed tests
 }
 
 var KNOWN_KNODE_SCORE_MINIMUM = int32(-1000)
 
+//GetNetworkGroup returns the network group resolved by the last connect
+func (self *KnownNodeScored) GetNetworkGroup() string {
+	return self.networkGroup.Load()
+}
+
+//ResolveNetworkGroup resolves again the address, as the DNS records of the host can change
+func (self *KnownNodeScored) ResolveNetworkGroup() string {
+	networkGroup := GetNetworkGroup(self.URL)
+	self.networkGroup.Store(networkGroup)
+	return networkGroup
+}
+
+func (self *KnownNodeScored) IsTested() bool {
+	return atomic.LoadInt32(&self.tested) == 1
+}
+
+//MarkTested returns false when the node was already tested
+func (self *KnownNodeScored) MarkTested() bool {
+	return atomic.CompareAndSwapInt32(&self.tested, 0, 1)
+}
+
+//IncreaseFailures returns the consecutive failed tests
+func (self *KnownNodeScored) IncreaseFailures() int32 {
+	return atomic.AddInt32(&self.failures, 1)
+}
+
 func (self *KnownNodeScored) IncreaseScore(delta int32, isServer bool) (bool, int32) {
 
 	newScore := atomic.AddInt32(&self.Score, delta)
@@ -0,0 +1,48 @@
+package known_node
+
+import (
+	"context"
+	"encoding/hex"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func getIPNetworkGroup(ip net.IP) string {
+	if ip4 := ip.To4(); ip4 != nil {
+		return "ipv4:" + strconv.Itoa(int(ip4[0])) + "." + strconv.Itoa(int(ip4[1]))
+	}
+	return "ipv6:" + hex.EncodeToString(ip[:4])
+}
+
+//GetNetworkGroup returns the group of the node address: the /16 subnet for IPv4, the /32 subnet for IPv6 and a single group for onion
+//The host names are resolved, so many domains pointing to the same subnet are a single group. It should be called at connect time
+//The outbound connections are spread over different groups, so a single operator can't own all of them
+func GetNetworkGroup(urlStr string) string {
+
+	u, err := url.Parse(urlStr)
+	if err != nil {
+		return urlStr
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if strings.HasSuffix(host, ".onion") {
+		return "onion"
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		return getIPNetworkGroup(ip)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if ips, err := net.DefaultResolver.LookupIP(ctx, "ip", host); err == nil && len(ips) > 0 {
+		return getIPNetworkGroup(ips[0])
+	}
+
+	//the host can't be resolved, the connection will most likely fail
+	return "host:" + host
+}
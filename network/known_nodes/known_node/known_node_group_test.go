@@ -0,0 +1,18 @@
+package known_node
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGetNetworkGroup(t *testing.T) {
+
+	assert.Equal(t, "ipv4:10.20", GetNetworkGroup("ws://10.20.30.40:5230/ws"), "IPv4 group is invalid")
+	assert.Equal(t, GetNetworkGroup("ws://10.20.1.1:5230/ws"), GetNetworkGroup("wss://10.20.200.3/ws"), "IPv4 /16 should be the same group")
+	assert.NotEqual(t, GetNetworkGroup("ws://10.20.1.1:5230/ws"), GetNetworkGroup("ws://10.21.1.1:5230/ws"), "IPv4 /16 should be different groups")
+
+	assert.Equal(t, "ipv6:20010db8", GetNetworkGroup("ws://[2001:db8:85a3::8a2e:370:7334]:5230/ws"), "IPv6 group is invalid")
+	assert.Equal(t, "onion", GetNetworkGroup("ws://abcdefghijklmnop.onion/ws"), "Onion group is invalid")
+	assert.Equal(t, GetNetworkGroup("ws://127.0.0.1:5230/ws"), GetNetworkGroup("ws://localhost:5230/ws"), "Host should be grouped by the resolved IP")
+
+}
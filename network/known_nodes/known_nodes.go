@@ -15,7 +15,9 @@ import (
 
 type KnownNodesType struct {
 	knownMap                      *generics.Map[string, *known_node.KnownNodeScored]
-	knownList                     []*known_node.KnownNodeScored //contains all known peers
+	knownByNodeId                 *generics.Map[string, *known_node.KnownNodeScored]
+	knownList                     []*known_node.KnownNodeScored //contains the tested known peers
+	knownNewList                  []*known_node.KnownNodeScored //contains the known peers which were not tested yet
 	knownListMutex                sync.RWMutex
 	knownNotConnectedMaxHeap      *min_max_heap.HeapMemory //contains known peers that we are not connected
 	knownNotConnectedMaxHeapMutex sync.RWMutex
@@ -28,14 +30,28 @@ func (this *KnownNodesType) GetList() []*known_node.KnownNodeScored {
 	this.knownListMutex.RLock()
 	defer this.knownListMutex.RUnlock()
 
-	knownList := make([]*known_node.KnownNodeScored, len(this.knownList))
-	for i, knowNode := range this.knownList {
-		knownList[i] = knowNode
-	}
+	knownList := make([]*known_node.KnownNodeScored, 0, len(this.knownList)+len(this.knownNewList))
+	knownList = append(knownList, this.knownList...)
+	knownList = append(knownList, this.knownNewList...)
 
 	return knownList
 }
 
+func (this *KnownNodesType) GetKnownNode(url string) *known_node.KnownNodeScored {
+	knownNode, _ := this.knownMap.Load(url)
+	return knownNode
+}
+
+//GetRandomNewKnownNode returns a known node which was not tested yet
+func (this *KnownNodesType) GetRandomNewKnownNode() *known_node.KnownNodeScored {
+	this.knownListMutex.RLock()
+	defer this.knownListMutex.RUnlock()
+	if len(this.knownNewList) == 0 {
+		return nil
+	}
+	return this.knownNewList[rand.Intn(len(this.knownNewList))]
+}
+
 func (this *KnownNodesType) GetRandomKnownNode() *known_node.KnownNodeScored {
 	this.knownListMutex.RLock()
 	defer this.knownListMutex.RUnlock()
@@ -45,9 +61,16 @@ func (this *KnownNodesType) GetRandomKnownNode() *known_node.KnownNodeScored {
 	return this.knownList[rand.Intn(len(this.knownList))]
 }
 
-func (this *KnownNodesType) GetBestNotConnectedKnownNode() *known_node.KnownNodeScored {
+//GetBestNotConnectedKnownNode returns the best scored known node which is not skipped
+func (this *KnownNodesType) GetBestNotConnectedKnownNode(skip func(knownNode *known_node.KnownNodeScored) bool) *known_node.KnownNodeScored {
 	this.knownNotConnectedMaxHeapMutex.RLock()
-	top, _ := this.knownNotConnectedMaxHeap.GetTop()
+	top, _ := this.knownNotConnectedMaxHeap.Find(func(x *min_max_heap.HeapElement) bool {
+		if x.Score == float64(known_node.KNOWN_KNODE_SCORE_MINIMUM) {
+			return true
+		}
+		knownNode, _ := this.knownMap.Load(string(x.Key))
+		return knownNode != nil && !skip(knownNode)
+	})
 	this.knownNotConnectedMaxHeapMutex.RUnlock()
 	if top == nil {
 		return nil
@@ -59,9 +82,24 @@ func (this *KnownNodesType) GetBestNotConnectedKnownNode() *known_node.KnownNode
 	return knownNode
 }
 
-func (this *KnownNodesType) IncreaseKnownNodeScore(knownNode *known_node.KnownNodeScored, delta int32, isServer bool) bool {
+//getScoredKnownNode returns the known node linked to the node id, so the score follows the identity and not the url
+func (this *KnownNodesType) getScoredKnownNode(knownNode *known_node.KnownNodeScored, nodeId string) *known_node.KnownNodeScored {
+	if nodeId == "" {
+		nodeId = knownNode.NodeId
+	}
+	if nodeId != "" {
+		if byNodeId, ok := this.knownByNodeId.Load(nodeId); ok {
+			return byNodeId
+		}
+	}
+	return knownNode
+}
+
+//IncreaseKnownNodeScore scores the node id when it is present
+func (this *KnownNodesType) IncreaseKnownNodeScore(knownNode *known_node.KnownNodeScored, nodeId string, delta int32, isServer bool) bool {
+	knownNode = this.getScoredKnownNode(knownNode, nodeId)
 	update, score := knownNode.IncreaseScore(delta, isServer)
-	if update {
+	if update && knownNode.IsTested() {
 		this.knownNotConnectedMaxHeapMutex.Lock()
 		defer this.knownNotConnectedMaxHeapMutex.Unlock()
 		this.knownNotConnectedMaxHeap.Update(float64(score), []byte(knownNode.URL))
@@ -70,6 +108,7 @@ func (this *KnownNodesType) IncreaseKnownNodeScore(knownNode *known_node.KnownNo
 }
 
 func (this *KnownNodesType) DecreaseKnownNodeScore(knownNode *known_node.KnownNodeScored, delta int32, isServer bool) (bool, bool) {
+	knownNode = this.getScoredKnownNode(knownNode, "")
 	update, removed, score := knownNode.DecreaseScore(delta, isServer)
 	if removed {
 		this.RemoveKnownNode(knownNode)
@@ -78,7 +117,7 @@ func (this *KnownNodesType) DecreaseKnownNodeScore(knownNode *known_node.KnownNo
 		this.knownNotConnectedMaxHeapMutex.Lock()
 		defer this.knownNotConnectedMaxHeapMutex.Unlock()
 		this.knownNotConnectedMaxHeap.DeleteByKey([]byte(knownNode.URL))
-		if !removed {
+		if !removed && knownNode.IsTested() {
 			this.knownNotConnectedMaxHeap.Insert(float64(score), []byte(knownNode.URL))
 		}
 	}
@@ -92,6 +131,9 @@ func (this *KnownNodesType) MarkKnownNodeConnected(knownNode *known_node.KnownNo
 }
 
 func (this *KnownNodesType) MarkKnownNodeDisconnected(knownNode *known_node.KnownNodeScored) {
+	if !knownNode.IsTested() {
+		return
+	}
 	this.knownNotConnectedMaxHeapMutex.Lock()
 	defer this.knownNotConnectedMaxHeapMutex.Unlock()
 	this.knownNotConnectedMaxHeap.Update(float64(atomic.LoadInt32(&knownNode.Score)), []byte(knownNode.URL))
@@ -123,23 +165,58 @@ func (this *KnownNodesType) AddKnownNode(url string, isSeed bool) (*known_node.K
 		return nil, errors.New("Already exists")
 	}
 
+	atomic.AddInt32(&this.knownCount, +1)
+
+	//the seeds are trusted. The other nodes are promoted after they are tested
+	if !isSeed {
+		this.knownListMutex.Lock()
+		this.knownNewList = append(this.knownNewList, knownNode)
+		this.knownListMutex.Unlock()
+		return knownNode, nil
+	}
+
+	this.PromoteKnownNode(knownNode)
+
+	return knownNode, nil
+}
+
+//PromoteKnownNode marks the node as tested, so it can be used for the outbound connections
+func (this *KnownNodesType) PromoteKnownNode(knownNode *known_node.KnownNodeScored) {
+
+	if !knownNode.MarkTested() {
+		return
+	}
+
 	this.knownListMutex.Lock()
+	this.knownNewList = removeFromList(this.knownNewList, knownNode)
 	this.knownList = append(this.knownList, knownNode)
 	this.knownListMutex.Unlock()
 
-	atomic.AddInt32(&this.knownCount, +1)
-
-	if _, ok := connected_nodes.ConnectedNodes.AllAddresses.Load(url); !ok {
+	if _, ok := connected_nodes.ConnectedNodes.AllAddresses.Load(knownNode.URL); !ok {
 		this.knownNotConnectedMaxHeapMutex.Lock()
-		this.knownNotConnectedMaxHeap.Update(0, []byte(url))
+		this.knownNotConnectedMaxHeap.Update(float64(atomic.LoadInt32(&knownNode.Score)), []byte(knownNode.URL))
 		this.knownNotConnectedMaxHeapMutex.Unlock()
 	}
+}
 
-	return knownNode, nil
+func removeFromList(list []*known_node.KnownNodeScored, knownNode *known_node.KnownNodeScored) []*known_node.KnownNodeScored {
+	for i, knownNode2 := range list {
+		if knownNode2 == knownNode {
+			list[i] = list[len(list)-1]
+			return list[:len(list)-1]
+		}
+	}
+	return list
 }
 
 func (this *KnownNodesType) RemoveKnownNode(knownNode *known_node.KnownNodeScored) {
 
+	if knownNode.NodeId != "" {
+		if previous, ok := this.knownByNodeId.Load(knownNode.NodeId); ok && previous == knownNode {
+			this.knownByNodeId.Delete(knownNode.NodeId)
+		}
+	}
+
 	if _, exists := this.knownMap.LoadAndDelete(knownNode.URL); exists {
 
 		this.knownNotConnectedMaxHeapMutex.Lock()
@@ -147,19 +224,37 @@ func (this *KnownNodesType) RemoveKnownNode(knownNode *known_node.KnownNodeScore
 		this.knownNotConnectedMaxHeapMutex.Unlock()
 
 		this.knownListMutex.Lock()
-		defer this.knownListMutex.Unlock()
-		for i, knownNode2 := range this.knownList {
-			if knownNode2 == knownNode {
-				this.knownList[i] = this.knownList[len(this.knownList)-1]
-				this.knownList = this.knownList[:len(this.knownList)-1]
-				atomic.AddInt32(&this.knownCount, -1)
-				return
-			}
-		}
+		this.knownList = removeFromList(this.knownList, knownNode)
+		this.knownNewList = removeFromList(this.knownNewList, knownNode)
+		this.knownListMutex.Unlock()
+
+		atomic.AddInt32(&this.knownCount, -1)
 	}
 
 }
 
+//SetKnownNodeId links the known node to its proven node id. A node found before under a different url is replaced and its score is kept, so rotating urls doesn't reset the score
+func (this *KnownNodesType) SetKnownNodeId(knownNode *known_node.KnownNodeScored, nodeId string) {
+
+	if nodeId == "" || knownNode.NodeId == nodeId {
+		return
+	}
+
+	knownNode.NodeId = nodeId
+
+	previous, exists := this.knownByNodeId.LoadOrStore(nodeId, knownNode)
+	if !exists || previous == knownNode {
+		return
+	}
+
+	this.knownByNodeId.Store(nodeId, knownNode)
+	atomic.StoreInt32(&knownNode.Score, atomic.LoadInt32(&previous.Score))
+
+	if previous.URL != knownNode.URL && !previous.IsSeed {
+		this.RemoveKnownNode(previous)
+	}
+}
+
 func (this *KnownNodesType) Reset(urls []string, isSeed bool) (err error) {
 
 	this.knownNotConnectedMaxHeapMutex.Lock()
@@ -169,6 +264,7 @@ func (this *KnownNodesType) Reset(urls []string, isSeed bool) (err error) {
 	defer this.knownListMutex.Unlock()
 
 	this.knownList = []*known_node.KnownNodeScored{}
+	this.knownNewList = []*known_node.KnownNodeScored{}
 	this.knownNotConnectedMaxHeap.Reset()
 	atomic.StoreInt32(&this.knownCount, 0)
 
@@ -180,6 +276,11 @@ func (this *KnownNodesType) Reset(urls []string, isSeed bool) (err error) {
 			changes = true
 			return true
 		})
+		this.knownByNodeId.Range(func(key string, value *known_node.KnownNodeScored) bool {
+			this.knownByNodeId.Delete(key)
+			changes = true
+			return true
+		})
 	}
 
 	for _, url := range urls {
@@ -200,7 +301,10 @@ func (this *KnownNodesType) Reset(urls []string, isSeed bool) (err error) {
 			Score: 0,
 		}
 
-		this.knownMap.LoadOrStore(url, knownNode)
+		if _, exists := this.knownMap.LoadOrStore(url, knownNode); exists {
+			continue
+		}
+		knownNode.MarkTested()
 		this.knownList = append(this.knownList, knownNode)
 		if err = this.knownNotConnectedMaxHeap.Update(float64(knownNode.Score), []byte(url)); err != nil {
 			return
@@ -215,6 +319,8 @@ func (this *KnownNodesType) Reset(urls []string, isSeed bool) (err error) {
 func init() {
 	KnownNodes = &KnownNodesType{
 		&generics.Map[string, *known_node.KnownNodeScored]{},
+		&generics.Map[string, *known_node.KnownNodeScored]{},
+		make([]*known_node.KnownNodeScored, 0),
 		make([]*known_node.KnownNodeScored, 0),
 		sync.RWMutex{},
 		min_max_heap.NewMaxMemoryHeap(),
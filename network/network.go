@@ -6,8 +6,10 @@ import (
 	"pandora-pay/config"
 	"pandora-pay/helpers/msgpack"
 	"pandora-pay/mempool"
+	"pandora-pay/network/banned_nodes"
 	"pandora-pay/network/connected_nodes"
 	"pandora-pay/network/known_nodes"
+	"pandora-pay/network/node_identity"
 	"pandora-pay/network/server/node_tcp"
 	"pandora-pay/network/websocks"
 	"pandora-pay/network/websocks/connection/advanced_connection_types"
@@ -92,23 +94,39 @@ func SendJSONAwaitAnswer[T any](name []byte, data any, ctxParent context.Context
 	}
 }
 
-func NewNetwork(settings *settings.Settings, chain *blockchain.Blockchain, mempool *mempool.Mempool, wallet *wallet.Wallet) error {
+func NewNetwork(settings *settings.Settings, chain *blockchain.Blockchain, mempool *mempool.Mempool, wallets *wallet.Wallets) error {
 
-	list := make([]string, len(config.NETWORK_SELECTED_SEEDS))
-	for i, seed := range config.NETWORK_SELECTED_SEEDS {
-		list[i] = seed.Url
+	if err := node_identity.InitNodeIdentity(); err != nil {
+		return err
+	}
+
+	//connecting to a node with the same identity means connecting to myself
+	banned_nodes.BannedNodes.BanNodeId(node_identity.NodeIdentity.NodeId, "You can't connect to yourself", 10*365*24*time.Hour)
+
+	list := make([]string, 0, len(config.NETWORK_SELECTED_SEEDS)+len(node_identity.TrustedPeersURLs))
+	for _, seed := range config.NETWORK_SELECTED_SEEDS {
+		list = append(list, seed.Url)
+	}
+	for url := range node_identity.TrustedPeersURLs {
+		list = append(list, url)
 	}
 	if err := known_nodes.KnownNodes.Reset(list, true); err != nil {
 		return err
 	}
 
-	if err := node_tcp.NewTcpServer(settings, chain, mempool, wallet); err != nil {
+	if err := node_tcp.NewTcpServer(settings, chain, mempool, wallets); err != nil {
 		return err
 	}
 
 	Network = &networkType{}
 
+	if err := Network.connectToAnchors(); err != nil {
+		return err
+	}
+
 	Network.continuouslyConnectingNewPeers()
+	Network.continuouslyTestingNewPeers()
+	Network.continuouslySaveAnchors()
 	Network.continuouslyDownloadNetworkNodes()
 
 	return nil
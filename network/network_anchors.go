@@ -0,0 +1,105 @@
+package network
+
+import (
+	"golang.org/x/exp/slices"
+	"pandora-pay/gui"
+	"pandora-pay/gui/gui_logger"
+	"pandora-pay/helpers/msgpack"
+	"pandora-pay/helpers/recovery"
+	"pandora-pay/network/connected_nodes"
+	"pandora-pay/network/known_nodes"
+	"pandora-pay/network/network_config"
+	"pandora-pay/network/websocks"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"time"
+)
+
+//the anchors are outbound peers saved periodically and connected first after a restart, so an attacker can't replace all the peers while the node restarts
+
+func loadAnchors() (urls []string, err error) {
+	err = store.StoreSettings.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+		if data := reader.Get("networkAnchors"); data != nil {
+			return msgpack.Unmarshal(data, &urls)
+		}
+		return nil
+	})
+	return
+}
+
+func saveAnchors(urls []string) error {
+	return store.StoreSettings.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+		data, err := msgpack.Marshal(urls)
+		if err != nil {
+			return err
+		}
+		writer.Put("networkAnchors", data)
+		return nil
+	})
+}
+
+func (this *networkType) connectToAnchors() error {
+
+	urls, err := loadAnchors()
+	if err != nil {
+		return err
+	}
+
+	for _, url := range urls {
+
+		known_nodes.KnownNodes.AddKnownNode(url, false)
+
+		knownNode := known_nodes.KnownNodes.GetKnownNode(url)
+		if knownNode == nil {
+			continue
+		}
+
+		//the anchors were connected before
+		known_nodes.KnownNodes.PromoteKnownNode(knownNode)
+
+		recovery.SafeGo(func() {
+			knownNode.ResolveNetworkGroup()
+			if _, err := websocks.Websockets.NewWebsocketClient(knownNode); err == nil {
+				gui.GUI.Log(gui_logger.SUBSYSTEM_NETWORK, "connected to anchor: "+knownNode.URL)
+			}
+		})
+	}
+
+	return nil
+}
+
+func (this *networkType) continuouslySaveAnchors() {
+
+	recovery.SafeGo(func() {
+
+		var last []string
+
+		for {
+
+			time.Sleep(network_config.NETWORK_ANCHORS_SAVE_INTERVAL)
+
+			urls := make([]string, 0, network_config.NETWORK_ANCHORS)
+			for _, conn := range connected_nodes.ConnectedNodes.AllList.Get() {
+				if len(urls) == network_config.NETWORK_ANCHORS {
+					break
+				}
+				if !conn.ConnectionType && conn.KnownNode != nil {
+					urls = append(urls, conn.KnownNode.URL)
+				}
+			}
+
+			//nothing is saved while the node is disconnected to keep the previous anchors
+			if len(urls) == 0 || slices.Equal(urls, last) {
+				continue
+			}
+
+			if err := saveAnchors(urls); err != nil {
+				gui.GUI.Error(gui_logger.SUBSYSTEM_NETWORK, "Error saving the anchors", err)
+				continue
+			}
+			last = urls
+		}
+
+	})
+
+}
@@ -5,6 +5,7 @@ import (
 	"pandora-pay/config/arguments"
 	"pandora-pay/network/network_config/network_config_auth"
 	"strconv"
+	"sync/atomic"
 	"time"
 )
 
@@ -18,6 +19,11 @@ var (
 	NETWORK_ENABLE_SUBSCRIPTIONS               = false
 	NETWORK_CONNECTIONS_READY_THRESHOLD        = int64(1)
 	STATIC_FILES                               = map[string]string{}
+	NETWORK_DANDELION_ENABLED                  = true
+	NETWORK_P2P_ADDRESS_URL_STRING             = ""       //native tcp socket of the full node
+	NETWORK_UPLOAD_LIMIT                       = int64(0) //bytes per second, use atomic. 0 is unlimited
+	NETWORK_DOWNLOAD_LIMIT                     = int64(0) //bytes per second, use atomic. 0 is unlimited
+	NETWORK_PEER_UPLOAD_LIMIT                  = int64(0) //bytes per second for every peer, use atomic. 0 is unlimited
 )
 
 const (
@@ -29,20 +35,99 @@ const (
 	WEBSOCKETS_INCREASE_KNOWN_NODE_SCORE_INTERVAL = 1 * time.Minute
 	WEBSOCKETS_CONCURRENT_NEW_CONENCTIONS         = 5
 	WEBSOCKETS_TIMEOUT                            = 15 * time.Second //seconds
+	API_RPC_MAX_READ                              = int64(config.BLOCK_MAX_SIZE + 5*1024)
+	API_RPC_MAX_BATCH                             = 100
 )
 
-func InitConfig() (err error) {
+const (
+	NETWORK_OUTBOUND_MAX_PER_GROUP = 2 //outbound connections to the same network group
+	NETWORK_ANCHORS                = 2 //outbound peers connected again after a restart
+	NETWORK_ANCHORS_SAVE_INTERVAL  = 1 * time.Minute
+	NETWORK_FEELER_INTERVAL        = 1 * time.Minute //a new known node is tested every interval
+	NETWORK_FEELER_MAX_FAILURES    = int32(3)        //a new known node is removed after failing the test consecutively
+)
+
+const (
+	DANDELION_EPOCH              = 10 * time.Minute //the stem relays are chosen again every epoch
+	DANDELION_RELAYS             = 2
+	DANDELION_FLUFF_PROBABILITY  = 0.1 //probability of a node to diffuse the stem txs during an epoch
+	DANDELION_EMBARGO_MIN        = 30 * time.Second
+	DANDELION_EMBARGO_RANDOM     = 30 * time.Second
+	DANDELION_EMBARGO_CHECK_TIME = 1 * time.Second
+	DANDELION_MAX_TXS            = 10000 //stem txs held. Above the limit the stem txs are diffused
+	DANDELION_MAX_PEER_TXS       = 100   //stem txs held which were received from the same peer
+)
 
-	if arguments.Arguments["--tcp-max-clients"] != nil {
-		if WEBSOCKETS_NETWORK_CLIENTS_MAX, err = strconv.ParseInt(arguments.Arguments["--tcp-max-clients"].(string), 10, 64); err != nil {
+func initConnectionsLimits() (err error) {
+
+	var limit int64
+	args := arguments.GetArguments()
+
+	if args["--tcp-max-clients"] != nil {
+		if limit, err = strconv.ParseInt(args["--tcp-max-clients"].(string), 10, 64); err != nil {
 			return
 		}
+		atomic.StoreInt64(&WEBSOCKETS_NETWORK_CLIENTS_MAX, limit)
 	}
 
-	if arguments.Arguments["--tcp-max-server-sockets"] != nil {
-		if WEBSOCKETS_NETWORK_SERVER_MAX, err = strconv.ParseInt(arguments.Arguments["--tcp-max-server-sockets"].(string), 10, 64); err != nil {
+	if args["--tcp-max-server-sockets"] != nil {
+		if limit, err = strconv.ParseInt(args["--tcp-max-server-sockets"].(string), 10, 64); err != nil {
 			return
 		}
+		atomic.StoreInt64(&WEBSOCKETS_NETWORK_SERVER_MAX, limit)
+	}
+
+	return
+}
+
+//initBandwidthLimit reads the limit given in KB/s
+func initBandwidthLimit(argument string, value *int64) (err error) {
+
+	var limit int64
+	args := arguments.GetArguments()
+	if args[argument] != nil {
+		if limit, err = strconv.ParseInt(args[argument].(string), 10, 64); err != nil {
+			return
+		}
+	}
+
+	atomic.StoreInt64(value, limit*1024)
+	return
+}
+
+func initBandwidthLimits() (err error) {
+
+	if err = initBandwidthLimit("--tcp-upload-limit", &NETWORK_UPLOAD_LIMIT); err != nil {
+		return
+	}
+	if err = initBandwidthLimit("--tcp-download-limit", &NETWORK_DOWNLOAD_LIMIT); err != nil {
+		return
+	}
+	return initBandwidthLimit("--tcp-peer-upload-limit", &NETWORK_PEER_UPLOAD_LIMIT)
+}
+
+//ReloadConfig updates only the settings which can be changed while the node is running
+func ReloadConfig() (err error) {
+
+	if err = initConnectionsLimits(); err != nil {
+		return
+	}
+
+	if err = initBandwidthLimits(); err != nil {
+		return
+	}
+
+	return network_config_auth.InitConfig()
+}
+
+func InitConfig() (err error) {
+
+	if err = initConnectionsLimits(); err != nil {
+		return
+	}
+
+	if err = initBandwidthLimits(); err != nil {
+		return
 	}
 
 	if arguments.Arguments["--tcp-connections-ready=threshold"] != nil {
@@ -51,6 +136,10 @@ func InitConfig() (err error) {
 		}
 	}
 
+	if arguments.Arguments["--dandelion"] != nil {
+		NETWORK_DANDELION_ENABLED = arguments.Arguments["--dandelion"] == "true"
+	}
+
 	if config.NETWORK_SELECTED == config.TEST_NET_NETWORK_BYTE || config.NETWORK_SELECTED == config.DEV_NET_NETWORK_BYTE {
 
 		if arguments.Arguments["--hcaptcha-secret"] != nil {
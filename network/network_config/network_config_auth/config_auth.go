@@ -2,31 +2,60 @@ package network_config_auth
 
 import (
 	"encoding/json"
+	"errors"
 	"pandora-pay/config/arguments"
+	"pandora-pay/helpers/generics"
 )
 
 type ConfigAuth struct {
-	Username string `json:"user" msgpack:"user"`
-	Password string `json:"pass"  msgpack:"pass"`
+	Username string             `json:"user" msgpack:"user"`
+	Password string             `json:"pass"  msgpack:"pass"`
+	Webhook  *ConfigAuthWebhook `json:"webhook,omitempty"  msgpack:"webhook,omitempty"`
 }
 
-var (
-	CONFIG_AUTH_USERS_LIST []*ConfigAuth
-	CONFIG_AUTH_USERS_MAP  map[string]*ConfigAuth
-)
+//ConfigAuthWebhook receives the wallets events of the user. Wallets empty means all the opened wallets
+type ConfigAuthWebhook struct {
+	URL           string   `json:"url" msgpack:"url"`
+	Secret        string   `json:"secret" msgpack:"secret"`
+	Confirmations uint64   `json:"confirmations,omitempty" msgpack:"confirmations,omitempty"`
+	Wallets       []string `json:"wallets,omitempty" msgpack:"wallets,omitempty"`
+}
+
+//the users can be reloaded while the node is running
+var configAuthUsersMap = &generics.Value[map[string]*ConfigAuth]{}
+
+func GetUser(username string) *ConfigAuth {
+	return configAuthUsersMap.Load()[username]
+}
+
+func GetUsers() []*ConfigAuth {
+	usersMap := configAuthUsersMap.Load()
+	list := make([]*ConfigAuth, 0, len(usersMap))
+	for _, user := range usersMap {
+		list = append(list, user)
+	}
+	return list
+}
 
 func InitConfig() (err error) {
 
-	if str := arguments.Arguments["--auth-users"]; str != nil {
-		if err = json.Unmarshal([]byte(str.(string)), &CONFIG_AUTH_USERS_LIST); err != nil {
+	var list []*ConfigAuth
+	if str := arguments.GetArguments()["--auth-users"]; str != nil {
+		if err = json.Unmarshal([]byte(str.(string)), &list); err != nil {
 			return
 		}
 	}
 
-	CONFIG_AUTH_USERS_MAP = map[string]*ConfigAuth{}
-	for _, auth := range CONFIG_AUTH_USERS_LIST {
-		CONFIG_AUTH_USERS_MAP[auth.Username] = auth
+	usersMap := map[string]*ConfigAuth{}
+	for _, auth := range list {
+		//the deliveries can't be verified without a secret
+		if auth.Webhook != nil && auth.Webhook.URL != "" && auth.Webhook.Secret == "" {
+			return errors.New("Webhook secret of the user " + auth.Username + " can not be empty")
+		}
+		usersMap[auth.Username] = auth
 	}
 
+	configAuthUsersMap.Store(usersMap)
+
 	return
 }
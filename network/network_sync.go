@@ -3,6 +3,7 @@ package network
 import (
 	"pandora-pay/config"
 	"pandora-pay/gui"
+	"pandora-pay/gui/gui_logger"
 	"pandora-pay/helpers/recovery"
 	"pandora-pay/network/banned_nodes"
 	"pandora-pay/network/connected_nodes"
@@ -10,7 +11,9 @@ import (
 	"pandora-pay/network/known_nodes/known_node"
 	"pandora-pay/network/known_nodes_sync"
 	"pandora-pay/network/network_config"
+	"pandora-pay/network/node_identity"
 	"pandora-pay/network/websocks"
+	"sync/atomic"
 	"time"
 )
 
@@ -35,6 +38,61 @@ func (this *networkType) continuouslyDownloadNetworkNodes() {
 
 }
 
+//isNetworkGroupFull returns true when there are enough outbound connections to the network group of the node. The trusted peers are not limited
+func isNetworkGroupFull(knownNode *known_node.KnownNodeScored, networkGroup string) bool {
+
+	if node_identity.TrustedPeersURLs[knownNode.URL] != nil {
+		return false
+	}
+
+	count := 0
+	for _, conn := range connected_nodes.ConnectedNodes.AllList.Get() {
+		if !conn.ConnectionType && conn.KnownNode != nil && conn.KnownNode.GetNetworkGroup() == networkGroup {
+			count++
+		}
+	}
+
+	return count >= network_config.NETWORK_OUTBOUND_MAX_PER_GROUP
+}
+
+//continuouslyTestingNewPeers probes the handshake of a random known node which was not tested. It is promoted only if the handshake succeeds
+func (this *networkType) continuouslyTestingNewPeers() {
+
+	recovery.SafeGo(func() {
+
+		for {
+
+			time.Sleep(network_config.NETWORK_FEELER_INTERVAL)
+
+			knownNode := known_nodes.KnownNodes.GetRandomNewKnownNode()
+			if knownNode == nil {
+				continue
+			}
+
+			if _, loaded := connected_nodes.ConnectedNodes.AllAddresses.Load(knownNode.URL); loaded {
+				continue
+			}
+
+			if banned_nodes.BannedNodes.IsBannedNode(knownNode.URL, knownNode.NodeId) {
+				known_nodes.KnownNodes.RemoveKnownNode(knownNode)
+				continue
+			}
+
+			//a transient failure doesn't remove the node
+			if err := websocks.Websockets.ProbeKnownNode(knownNode); err != nil {
+				if knownNode.IncreaseFailures() >= network_config.NETWORK_FEELER_MAX_FAILURES {
+					known_nodes.KnownNodes.RemoveKnownNode(knownNode)
+				}
+				continue
+			}
+
+			known_nodes.KnownNodes.PromoteKnownNode(knownNode)
+		}
+
+	})
+
+}
+
 func (this *networkType) continuouslyConnectingNewPeers() {
 
 	for i := 0; i < network_config.WEBSOCKETS_CONCURRENT_NEW_CONENCTIONS; i++ {
@@ -43,40 +101,44 @@ func (this *networkType) continuouslyConnectingNewPeers() {
 
 			for {
 
-				if websocks.Websockets.GetClients() >= network_config.WEBSOCKETS_NETWORK_CLIENTS_MAX {
+				if websocks.Websockets.GetClients() >= atomic.LoadInt64(&network_config.WEBSOCKETS_NETWORK_CLIENTS_MAX) {
 					time.Sleep(500 * time.Millisecond)
 					continue
 				}
 
 				var knownNode *known_node.KnownNodeScored
 				if index == 0 {
-					knownNode = known_nodes.KnownNodes.GetBestNotConnectedKnownNode()
+					//the nodes of the full groups are skipped, so the next best node is tried
+					knownNode = known_nodes.KnownNodes.GetBestNotConnectedKnownNode(func(knownNode *known_node.KnownNodeScored) bool {
+						_, loaded := connected_nodes.ConnectedNodes.AllAddresses.Load(knownNode.URL)
+						return loaded || isNetworkGroupFull(knownNode, knownNode.GetNetworkGroup())
+					})
 				} else {
 					knownNode = known_nodes.KnownNodes.GetRandomKnownNode()
 				}
 				if knownNode != nil {
 
-					if _, loaded := connected_nodes.ConnectedNodes.AllAddresses.Load(knownNode.URL); loaded {
+					if _, loaded := connected_nodes.ConnectedNodes.AllAddresses.Load(knownNode.URL); loaded || isNetworkGroupFull(knownNode, knownNode.ResolveNetworkGroup()) {
 						time.Sleep(100 * time.Millisecond)
 						continue
 					}
 
-					//gui.GUI.Log("connecting to", knownNode.URL, atomic.LoadInt32(&knownNode.Score))
+					//gui.GUI.Log(gui_logger.SUBSYSTEM_NETWORK, "connecting to", knownNode.URL, atomic.LoadInt32(&knownNode.Score))
 
-					if banned_nodes.BannedNodes.IsBanned(knownNode.URL) {
+					if banned_nodes.BannedNodes.IsBannedNode(knownNode.URL, knownNode.NodeId) {
 						known_nodes.KnownNodes.DecreaseKnownNodeScore(knownNode, -10, false)
 					} else {
 						_, err := websocks.Websockets.NewWebsocketClient(knownNode)
 						if err != nil {
 
-							//gui.GUI.Error("error connecting", knownNode.URL, err)
+							//gui.GUI.Error(gui_logger.SUBSYSTEM_NETWORK, "error connecting", knownNode.URL, err)
 
 							if err.Error() != "Already connected" {
 								known_nodes.KnownNodes.DecreaseKnownNodeScore(knownNode, -20, false)
 							}
 
 						} else {
-							gui.GUI.Log("connected to: " + knownNode.URL)
+							gui.GUI.Log(gui_logger.SUBSYSTEM_NETWORK, "connected to: "+knownNode.URL)
 						}
 					}
 				}
@@ -0,0 +1,160 @@
+package node_identity
+
+import (
+	"encoding/hex"
+	"errors"
+	"pandora-pay/addresses"
+	"pandora-pay/config"
+	"pandora-pay/config/arguments"
+	"pandora-pay/cryptography"
+	"pandora-pay/cryptography/crypto"
+	"pandora-pay/gui"
+	"pandora-pay/gui/gui_logger"
+	"pandora-pay/helpers"
+	"pandora-pay/helpers/advanced_buffers"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"strconv"
+	"strings"
+)
+
+const NODE_IDENTITY_NONCE_SIZE = 32
+
+//NodeIdentityType is the persistent keypair of the node. The node id is the public key in hex
+type NodeIdentityType struct {
+	privateKey *addresses.PrivateKey
+	PublicKey  []byte
+	NodeId     string
+}
+
+type TrustedPeer struct {
+	PublicKey []byte
+	NodeId    string
+	URL       string //optional. The peer at this url must prove it has the public key
+}
+
+var (
+	REQUIRE_PEER_IDENTITY = false //the full node peers must prove a node identity in the handshake
+	NodeIdentity          *NodeIdentityType
+	TrustedPeers          = map[string]*TrustedPeer{} //by node id
+	TrustedPeersURLs      = map[string]*TrustedPeer{} //by url
+)
+
+func GetNodeId(publicKey []byte) string {
+	return hex.EncodeToString(publicKey)
+}
+
+//getTranscript hashes the handshake with the network: the nonces of both nodes, the identities of both nodes and the direction of the connection
+//The signature can't be replayed on another connection or reflected back to the node which requested it
+func getTranscript(requestNonce, requesterPublicKey, responseNonce, responderPublicKey []byte, requesterIsServer bool) []byte {
+	writer := advanced_buffers.NewBufferWriter()
+	writer.WriteString("node-handshake-" + strconv.FormatUint(config.NETWORK_SELECTED, 10))
+	writer.WriteVariableBytes(requestNonce)
+	writer.WriteVariableBytes(requesterPublicKey)
+	writer.WriteVariableBytes(responseNonce)
+	writer.WriteVariableBytes(responderPublicKey)
+	writer.WriteBool(requesterIsServer)
+	return cryptography.SHA3(writer.Bytes())
+}
+
+//SignHandshake signs the transcript of the handshake requested by the peer. requesterPublicKey is empty for the peers without identity
+func (identity *NodeIdentityType) SignHandshake(requestNonce, requesterPublicKey, responseNonce []byte, requesterIsServer bool) ([]byte, error) {
+	if len(requestNonce) != NODE_IDENTITY_NONCE_SIZE || len(responseNonce) != NODE_IDENTITY_NONCE_SIZE {
+		return nil, errors.New("Invalid nonce")
+	}
+	if len(requesterPublicKey) != 0 && len(requesterPublicKey) != cryptography.PublicKeySize {
+		return nil, errors.New("Invalid requester public key")
+	}
+	return identity.privateKey.Sign(getTranscript(requestNonce, requesterPublicKey, responseNonce, identity.PublicKey, requesterIsServer))
+}
+
+//VerifyHandshake verifies the transcript signed by the peer which answered the handshake requested by this node
+func VerifyHandshake(requestNonce, requesterPublicKey, responseNonce, publicKey, signature []byte, requesterIsServer bool) bool {
+	if len(responseNonce) != NODE_IDENTITY_NONCE_SIZE || len(publicKey) != cryptography.PublicKeySize || len(signature) != cryptography.SignatureSize {
+		return false
+	}
+	return crypto.VerifySignature(getTranscript(requestNonce, requesterPublicKey, responseNonce, publicKey, requesterIsServer), signature, publicKey)
+}
+
+func GenerateNonce() []byte {
+	return helpers.RandomBytes(NODE_IDENTITY_NONCE_SIZE)
+}
+
+//initTrustedPeers reads the pinned peers given as "publicKey" or "publicKey:url" with the public key in hex
+func initTrustedPeers() (err error) {
+
+	if arguments.Arguments["--trusted-peers"] == nil {
+		return
+	}
+
+	for _, value := range strings.Split(arguments.Arguments["--trusted-peers"].(string), ",") {
+
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		peer := &TrustedPeer{}
+
+		publicKeyStr := value
+		if index := strings.Index(value, ":"); index >= 0 {
+			publicKeyStr, peer.URL = value[:index], value[index+1:]
+		}
+
+		if peer.PublicKey, err = hex.DecodeString(publicKeyStr); err != nil {
+			return
+		}
+		if len(peer.PublicKey) != cryptography.PublicKeySize {
+			return errors.New("Trusted peer public key length is invalid")
+		}
+
+		peer.NodeId = GetNodeId(peer.PublicKey)
+		TrustedPeers[peer.NodeId] = peer
+		if peer.URL != "" {
+			TrustedPeersURLs[peer.URL] = peer
+		}
+	}
+
+	return
+}
+
+func loadNodeIdentity() (privateKey *addresses.PrivateKey, err error) {
+
+	err = store.StoreSettings.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		if data := writer.Get("nodeIdentity"); data != nil {
+			privateKey, err = addresses.NewPrivateKey(helpers.CloneBytes(data))
+			return
+		}
+
+		privateKey = addresses.GenerateNewPrivateKey()
+		writer.Put("nodeIdentity", privateKey.Key)
+		return
+	})
+
+	return
+}
+
+func InitNodeIdentity() (err error) {
+
+	var privateKey *addresses.PrivateKey
+	if privateKey, err = loadNodeIdentity(); err != nil {
+		return
+	}
+
+	publicKey := privateKey.GeneratePublicKey()
+
+	NodeIdentity = &NodeIdentityType{
+		privateKey,
+		publicKey,
+		GetNodeId(publicKey),
+	}
+
+	gui.GUI.Info(gui_logger.SUBSYSTEM_NETWORK, "Node id", NodeIdentity.NodeId)
+
+	if arguments.Arguments["--require-peer-identity"] != nil {
+		REQUIRE_PEER_IDENTITY = arguments.Arguments["--require-peer-identity"] == "true"
+	}
+
+	return initTrustedPeers()
+}
@@ -0,0 +1,40 @@
+package node_identity
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/addresses"
+	"testing"
+)
+
+func createTestNodeIdentity() *NodeIdentityType {
+	privateKey := addresses.GenerateNewPrivateKey()
+	publicKey := privateKey.GeneratePublicKey()
+	return &NodeIdentityType{privateKey, publicKey, GetNodeId(publicKey)}
+}
+
+func TestHandshakeTranscript(t *testing.T) {
+
+	requester, responder := createTestNodeIdentity(), createTestNodeIdentity()
+	requestNonce, responseNonce := GenerateNonce(), GenerateNonce()
+
+	signature, err := responder.SignHandshake(requestNonce, requester.PublicKey, responseNonce, false)
+	assert.Nil(t, err)
+	assert.True(t, VerifyHandshake(requestNonce, requester.PublicKey, responseNonce, responder.PublicKey, signature, false))
+
+	//the signature is bound to the connection it was given on
+	assert.False(t, VerifyHandshake(GenerateNonce(), requester.PublicKey, responseNonce, responder.PublicKey, signature, false), "Replayed for another request nonce")
+	assert.False(t, VerifyHandshake(requestNonce, requester.PublicKey, GenerateNonce(), responder.PublicKey, signature, false), "Replayed with another response nonce")
+	assert.False(t, VerifyHandshake(requestNonce, createTestNodeIdentity().PublicKey, responseNonce, responder.PublicKey, signature, false), "Replayed for another requester")
+	assert.False(t, VerifyHandshake(requestNonce, nil, responseNonce, responder.PublicKey, signature, false), "Replayed for a requester without identity")
+	assert.False(t, VerifyHandshake(requestNonce, requester.PublicKey, responseNonce, responder.PublicKey, signature, true), "Reflected in the other direction")
+	assert.False(t, VerifyHandshake(requestNonce, requester.PublicKey, responseNonce, requester.PublicKey, signature, false), "Verified for another identity")
+
+	//the requesters without identity are supported
+	signature, err = responder.SignHandshake(requestNonce, nil, responseNonce, true)
+	assert.Nil(t, err)
+	assert.True(t, VerifyHandshake(requestNonce, nil, responseNonce, responder.PublicKey, signature, true))
+
+	_, err = responder.SignHandshake(requestNonce[1:], nil, responseNonce, true)
+	assert.NotNil(t, err)
+
+}
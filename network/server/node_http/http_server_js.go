@@ -19,10 +19,10 @@ type httpServerType struct {
 
 var HttpServer *httpServerType
 
-func NewHttpServer(chain *blockchain.Blockchain, settings *settings.Settings, mempool *mempool.Mempool, wallet *wallet.Wallet) error {
+func NewHttpServer(chain *blockchain.Blockchain, settings *settings.Settings, mempool *mempool.Mempool, wallets *wallet.Wallets) error {
 
 	apiStore := api_common.NewAPIStore(chain)
-	apiCommon, err := api_common.NewAPICommon(mempool, chain, wallet, apiStore)
+	apiCommon, err := api_common.NewAPICommon(mempool, chain, wallets, apiStore)
 	if err != nil {
 		return err
 	}
@@ -7,11 +7,13 @@ import (
 	"encoding/json"
 	"errors"
 	"github.com/rs/cors"
-	"io"
 	"net/http"
 	"net/url"
 	"pandora-pay/blockchain"
+	"pandora-pay/config/arguments"
+	"pandora-pay/helpers/metrics"
 	"pandora-pay/mempool"
+	"pandora-pay/network/api_code/api_code_http"
 	"pandora-pay/network/api_implementation/api_common"
 	"pandora-pay/network/api_implementation/api_http"
 	"pandora-pay/network/api_implementation/api_websockets"
@@ -20,18 +22,30 @@ import (
 	"pandora-pay/network/websocks"
 	"pandora-pay/settings"
 	"pandora-pay/wallet"
+	"pandora-pay/webhooks"
 )
 
 type httpServerType struct {
 	Api           *api_http.API
 	ApiWebsockets *api_websockets.APIWebsockets
 	ApiStore      *api_common.APIStore
-	GetMap        map[string]func(values url.Values) (any, error)
-	PostMap       map[string]func(values io.ReadCloser) (any, error)
+	Rpc           *node_http_rpc.RPCServer
+	GetMap        map[string]*api_code_http.Route
+	PostMap       map[string]*api_code_http.Route
 }
 
 var HttpServer *httpServerType
 
+//replies implementing it can answer with a different HTTP status code
+type httpStatusReply interface {
+	GetHTTPStatus() int
+}
+
+var (
+	metricRequests      = metrics.NewCounterVec("pandora_http_requests_total", "HTTP requests received per route", "route")
+	metricRequestErrors = metrics.NewCounterVec("pandora_http_requests_errors_total", "HTTP requests failed per route", "route")
+)
+
 func (this *httpServerType) get(w http.ResponseWriter, req *http.Request) {
 
 	defer func() {
@@ -43,20 +57,27 @@ func (this *httpServerType) get(w http.ResponseWriter, req *http.Request) {
 	var err error
 	var output interface{}
 
-	callback := this.GetMap[req.URL.Path]
-	if callback != nil {
+	route := this.GetMap[req.URL.Path]
+	if route != nil {
+
+		metricRequests.Inc(req.URL.Path)
 
 		var args url.Values
 		if args, err = url.ParseQuery(req.URL.RawQuery); err != nil {
+			metricRequestErrors.Inc(req.URL.Path)
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		output, err = callback(args)
+		output, err = route.Get(args)
 	} else {
+		metricRequests.Inc("unknown")
 		err = errors.New("Unknown request")
 	}
 
 	if err != nil {
+		if route != nil {
+			metricRequestErrors.Inc(req.URL.Path)
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -75,6 +96,9 @@ func (this *httpServerType) get(w http.ResponseWriter, req *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	if reply, ok := output.(httpStatusReply); ok {
+		w.WriteHeader(reply.GetHTTPStatus())
+	}
 	w.Write(final)
 }
 
@@ -89,14 +113,19 @@ func (this *httpServerType) post(w http.ResponseWriter, req *http.Request) {
 	var err error
 	var output interface{}
 
-	callback := this.PostMap[req.URL.Path]
-	if callback != nil {
-		output, err = callback(req.Body)
+	route := this.PostMap[req.URL.Path]
+	if route != nil {
+		metricRequests.Inc(req.URL.Path)
+		output, err = route.Post(req.Body)
 	} else {
+		metricRequests.Inc("unknown")
 		err = errors.New("Unknown request")
 	}
 
 	if err != nil {
+		if route != nil {
+			metricRequestErrors.Inc(req.URL.Path)
+		}
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -118,35 +147,48 @@ func (this *httpServerType) post(w http.ResponseWriter, req *http.Request) {
 	w.Write(final)
 }
 
+//getMetrics exports the metrics in Prometheus text format
+func (this *httpServerType) getMetrics(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	w.Write(metrics.Export())
+}
+
 func (this *httpServerType) GetHttpHandler() *http.Handler {
 
 	mux := http.NewServeMux()
 
 	mux.HandleFunc("/ws", websocks.Websockets.HandleUpgradeConnection)
+	mux.HandleFunc("/metrics", this.getMetrics)
+	mux.Handle("/rpc", this.Rpc)
+	mux.HandleFunc("/rpc/ws", this.Rpc.HandleUpgradeConnection)
+
+	if arguments.Arguments["--webhooks-test-stub"] == true {
+		mux.HandleFunc("/webhooks/test-stub", webhooks.TestStubHandler)
+	}
 
 	for key, filepath := range network_config.STATIC_FILES {
 		fs := http.FileServer(http.Dir(filepath))
 		mux.Handle(key, http.StripPrefix(key, fs))
 	}
 
-	for key, callback := range this.Api.GetMap {
+	for key, route := range this.Api.GetMap {
 		mux.HandleFunc("/"+key, this.get)
-		this.GetMap["/"+key] = callback
+		this.GetMap["/"+key] = route
 	}
 
-	for key, callback := range this.Api.PostMap {
+	for key, route := range this.Api.PostMap {
 		mux.HandleFunc("/"+key, this.post)
-		this.PostMap["/"+key] = callback
+		this.PostMap["/"+key] = route
 	}
 
 	handler := cors.AllowAll().Handler(mux)
 	return &handler
 }
 
-func NewHttpServer(chain *blockchain.Blockchain, settings *settings.Settings, mempool *mempool.Mempool, wallet *wallet.Wallet) error {
+func NewHttpServer(chain *blockchain.Blockchain, settings *settings.Settings, mempool *mempool.Mempool, wallets *wallet.Wallets) error {
 
 	apiStore := api_common.NewAPIStore(chain)
-	apiCommon, err := api_common.NewAPICommon(mempool, chain, wallet, apiStore)
+	apiCommon, err := api_common.NewAPICommon(mempool, chain, wallets, apiStore)
 	if err != nil {
 		return err
 	}
@@ -156,16 +198,18 @@ func NewHttpServer(chain *blockchain.Blockchain, settings *settings.Settings, me
 
 	websocks.NewWebsockets(chain, mempool, settings, apiWebsockets.GetMap)
 
+	rpc, err := node_http_rpc.NewRPCServer(api)
+	if err != nil {
+		return err
+	}
+
 	HttpServer = &httpServerType{
 		api,
 		apiWebsockets,
 		apiStore,
-		make(map[string]func(values url.Values) (any, error)),
-		make(map[string]func(values io.ReadCloser) (any, error)),
-	}
-
-	if err = node_http_rpc.InitializeRPC(apiCommon); err != nil {
-		return err
+		rpc,
+		make(map[string]*api_code_http.Route),
+		make(map[string]*api_code_http.Route),
 	}
 
 	return nil
@@ -1,21 +1,70 @@
+//go:build !js
+// +build !js
+
 package node_http_rpc
 
 import (
-	"github.com/gorilla/rpc"
+	"github.com/gorilla/websocket"
+	"io"
 	"net/http"
-	"pandora-pay/network/api_implementation/api_common"
+	"pandora-pay/network/network_config"
+	"pandora-pay/network/websocks/websock"
 )
 
-func InitializeRPC(apiCommon *api_common.APICommon) (err error) {
+//ServeHTTP answers JSON-RPC calls sent via POST. A GET returns the OpenRPC document
+func (this *RPCServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+
+	w.Header().Set("Content-Type", "application/json")
+
+	switch req.Method {
+	case http.MethodGet:
+		w.Write(this.openRPC)
+		return
+	case http.MethodPost:
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, req.Body, network_config.API_RPC_MAX_READ))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	out := this.Process(data)
+	if out == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	w.Write(out)
+}
 
-	s := rpc.NewServer()
+//HandleUpgradeConnection serves JSON-RPC over a websocket. Every text message is a request or a batch
+func (this *RPCServer) HandleUpgradeConnection(w http.ResponseWriter, req *http.Request) {
 
-	s.RegisterCodec(NewUpCodec(), "application/json")
-	if err = s.RegisterService(apiCommon, "api"); err != nil {
+	c, err := websock.Upgrade(w, req)
+	if err != nil {
 		return
 	}
+	defer c.Close()
 
-	http.Handle("/rpc/api/v1", s)
+	c.SetReadLimit(network_config.API_RPC_MAX_READ)
 
-	return
+	for {
+		messageType, data, err := c.ReadMessage()
+		if err != nil {
+			return
+		}
+		if messageType != websocket.TextMessage && messageType != websocket.BinaryMessage {
+			continue
+		}
+
+		if out := this.Process(data); out != nil {
+			if err = c.WriteMessage(websocket.TextMessage, out); err != nil {
+				return
+			}
+		}
+	}
 }
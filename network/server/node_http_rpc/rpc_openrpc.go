@@ -0,0 +1,236 @@
+package node_http_rpc
+
+import (
+	"encoding"
+	"encoding/json"
+	"pandora-pay/config"
+	"pandora-pay/network/api_code/api_code_http"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const OPENRPC_VERSION = "1.2.6"
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+type openRPCContentDescriptor struct {
+	Name     string         `json:"name"`
+	Required bool           `json:"required,omitempty"`
+	Schema   map[string]any `json:"schema"`
+}
+
+type openRPCMethod struct {
+	Name           string                      `json:"name"`
+	ParamStructure string                      `json:"paramStructure"`
+	Params         []*openRPCContentDescriptor `json:"params"`
+	Result         *openRPCContentDescriptor   `json:"result"`
+}
+
+type openRPCDocument struct {
+	OpenRPC string `json:"openrpc"`
+	Info    struct {
+		Title   string `json:"title"`
+		Version string `json:"version"`
+	} `json:"info"`
+	Methods    []*openRPCMethod `json:"methods"`
+	Components struct {
+		Schemas map[string]map[string]any `json:"schemas"`
+	} `json:"components"`
+}
+
+type openRPCField struct {
+	name     string
+	required bool
+	t        reflect.Type
+}
+
+type openRPCSchemas struct {
+	schemas map[string]map[string]any
+	names   map[reflect.Type]string
+}
+
+//getFields returns the json fields of a struct. Embedded structs without a json name are flattened like encoding/json does
+func getFields(t reflect.Type) []*openRPCField {
+
+	fields := make([]*openRPCField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+
+		field := t.Field(i)
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+
+		fieldType := field.Type
+		if field.Anonymous && name == "" {
+			if fieldType.Kind() == reflect.Pointer {
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() == reflect.Struct {
+				fields = append(fields, getFields(fieldType)...)
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		fields = append(fields, &openRPCField{name, !strings.Contains(options, "omitempty"), fieldType})
+	}
+
+	return fields
+}
+
+func (this *openRPCSchemas) getName(t reflect.Type) string {
+
+	if name, ok := this.names[t]; ok {
+		return name
+	}
+
+	name := t.Name()
+	if index := strings.IndexByte(name, '['); index >= 0 {
+		name = name[:index]
+	}
+	if _, ok := this.schemas[name]; ok {
+		name = path.Base(t.PkgPath()) + "." + name
+	}
+
+	this.names[t] = name
+	return name
+}
+
+func (this *openRPCSchemas) getSchema(t reflect.Type) map[string]any {
+
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType) {
+		return map[string]any{}
+	}
+	if t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType) {
+		return map[string]any{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "contentEncoding": "base64"}
+		}
+		return map[string]any{"type": "array", "items": this.getSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": this.getSchema(t.Elem())}
+	case reflect.Struct:
+
+		if t.Name() == "" {
+			return this.getStructSchema(t)
+		}
+
+		name := this.getName(t)
+		if _, ok := this.schemas[name]; !ok {
+			this.schemas[name] = map[string]any{} //avoids infinite recursion
+			this.schemas[name] = this.getStructSchema(t)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + name}
+	}
+
+	return map[string]any{}
+}
+
+func (this *openRPCSchemas) getStructSchema(t reflect.Type) map[string]any {
+
+	properties := make(map[string]any)
+	required := make([]string, 0)
+
+	for _, field := range getFields(t) {
+		properties[field.name] = this.getSchema(field.t)
+		if field.required {
+			required = append(required, field.name)
+		}
+	}
+
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func (this *openRPCSchemas) getParams(route *api_code_http.Route) []*openRPCContentDescriptor {
+
+	params := make([]*openRPCContentDescriptor, 0)
+
+	t := route.Args
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() == reflect.Struct {
+		for _, field := range getFields(t) {
+			params = append(params, &openRPCContentDescriptor{field.name, field.required, this.getSchema(field.t)})
+		}
+	}
+
+	if route.Authenticated {
+		params = append(params,
+			&openRPCContentDescriptor{"user", false, map[string]any{"type": "string"}},
+			&openRPCContentDescriptor{"pass", false, map[string]any{"type": "string"}},
+		)
+	}
+
+	return params
+}
+
+func generateOpenRPC(methods map[string]*api_code_http.Route) *openRPCDocument {
+
+	doc := &openRPCDocument{
+		OpenRPC: OPENRPC_VERSION,
+		Methods: make([]*openRPCMethod, 0, len(methods)),
+	}
+	doc.Info.Title = config.NAME + " JSON-RPC API"
+	doc.Info.Version = config.VERSION_STRING
+
+	schemas := &openRPCSchemas{
+		make(map[string]map[string]any),
+		make(map[reflect.Type]string),
+	}
+
+	names := make([]string, 0, len(methods))
+	for name := range methods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		route := methods[name]
+		doc.Methods = append(doc.Methods, &openRPCMethod{
+			Name:           name,
+			ParamStructure: "by-name",
+			Params:         schemas.getParams(route),
+			Result:         &openRPCContentDescriptor{"result", false, schemas.getSchema(route.Reply)},
+		})
+	}
+
+	doc.Components.Schemas = schemas.schemas
+	return doc
+}
@@ -0,0 +1,173 @@
+package node_http_rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"pandora-pay/helpers/metrics"
+	"pandora-pay/network/api_code/api_code_http"
+	"pandora-pay/network/api_implementation/api_http"
+	"pandora-pay/network/network_config"
+)
+
+type RPCServer struct {
+	methods map[string]*api_code_http.Route
+	openRPC []byte
+}
+
+var (
+	metricRPCRequests      = metrics.NewCounterVec("pandora_rpc_requests_total", "JSON-RPC calls received per method", "method")
+	metricRPCRequestErrors = metrics.NewCounterVec("pandora_rpc_requests_errors_total", "JSON-RPC calls failed per method", "method")
+)
+
+//getMethodName converts a route into a JSON-RPC method name. The root route is exposed as "info"
+func getMethodName(route string) string {
+	if route == "" {
+		return "info"
+	}
+	return route
+}
+
+func (this *RPCServer) callMethod(method string, params json.RawMessage) (result json.RawMessage, rpcErr *RPCError) {
+
+	defer func() {
+		if err := recover(); err != nil {
+			result, rpcErr = nil, &RPCError{RPC_ERROR_INTERNAL, fmt.Sprint(err)}
+		}
+	}()
+
+	if method == "rpc.discover" {
+		return this.openRPC, nil
+	}
+
+	route := this.methods[method]
+	if route == nil {
+		metricRPCRequests.Inc("unknown")
+		return nil, &RPCError{RPC_ERROR_METHOD_NOT_FOUND, "Method not found"}
+	}
+
+	metricRPCRequests.Inc(method)
+
+	//only named params are supported. A positional array is accepted when it wraps a single object
+	params = bytes.TrimSpace(params)
+	if len(params) == 0 || bytes.Equal(params, []byte("null")) {
+		params = []byte("{}")
+	} else if params[0] == '[' {
+		list := make([]json.RawMessage, 0, 1)
+		if err := json.Unmarshal(params, &list); err != nil || len(list) > 1 {
+			metricRPCRequestErrors.Inc(method)
+			return nil, &RPCError{RPC_ERROR_INVALID_PARAMS, "Params must be an object"}
+		}
+		if params = []byte("{}"); len(list) == 1 {
+			params = list[0]
+		}
+	}
+
+	if params[0] != '{' {
+		metricRPCRequestErrors.Inc(method)
+		return nil, &RPCError{RPC_ERROR_INVALID_PARAMS, "Params must be an object"}
+	}
+
+	output, err := route.Call(params)
+	if err != nil {
+		metricRPCRequestErrors.Inc(method)
+		invalidParams := new(api_code_http.InvalidParamsError)
+		if errors.As(err, &invalidParams) {
+			return nil, &RPCError{RPC_ERROR_INVALID_PARAMS, err.Error()}
+		}
+		return nil, &RPCError{RPC_ERROR_SERVER, err.Error()}
+	}
+
+	if result, err = json.Marshal(output); err != nil {
+		return nil, &RPCError{RPC_ERROR_INTERNAL, err.Error()}
+	}
+	return
+}
+
+//processRequest returns nil for notifications
+func (this *RPCServer) processRequest(data json.RawMessage) *RPCResponse {
+
+	request := new(RPCRequest)
+	if err := json.Unmarshal(data, request); err != nil {
+		return newRPCError(nil, RPC_ERROR_INVALID_REQUEST, "Invalid request")
+	}
+
+	if request.JSONRPC != RPC_VERSION || request.Method == "" {
+		return newRPCError(request.ID, RPC_ERROR_INVALID_REQUEST, "Invalid request")
+	}
+
+	result, rpcErr := this.callMethod(request.Method, request.Params)
+	if request.ID == nil {
+		return nil
+	}
+
+	if rpcErr != nil {
+		return &RPCResponse{RPC_VERSION, nil, rpcErr, request.ID}
+	}
+	return &RPCResponse{RPC_VERSION, result, nil, request.ID}
+}
+
+//Process answers a single JSON-RPC request or a batch. It returns nil when no answer must be sent back
+func (this *RPCServer) Process(data []byte) []byte {
+
+	var out any
+
+	data = bytes.TrimSpace(data)
+	if !json.Valid(data) {
+		out = newRPCError(nil, RPC_ERROR_PARSE, "Parse error")
+	} else if len(data) > 0 && data[0] == '[' {
+
+		batch := make([]json.RawMessage, 0)
+		if err := json.Unmarshal(data, &batch); err != nil {
+			out = newRPCError(nil, RPC_ERROR_PARSE, "Parse error")
+		} else if len(batch) == 0 {
+			out = newRPCError(nil, RPC_ERROR_INVALID_REQUEST, "Empty batch")
+		} else if len(batch) > network_config.API_RPC_MAX_BATCH {
+			out = newRPCError(nil, RPC_ERROR_INVALID_REQUEST, "Batch is too big")
+		} else {
+			responses := make([]*RPCResponse, 0, len(batch))
+			for _, request := range batch {
+				if response := this.processRequest(request); response != nil {
+					responses = append(responses, response)
+				}
+			}
+			if len(responses) == 0 {
+				return nil
+			}
+			out = responses
+		}
+
+	} else {
+		response := this.processRequest(data)
+		if response == nil {
+			return nil
+		}
+		out = response
+	}
+
+	final, _ := json.Marshal(out)
+	return final
+}
+
+func NewRPCServer(api *api_http.API) (*RPCServer, error) {
+
+	server := &RPCServer{
+		methods: make(map[string]*api_code_http.Route),
+	}
+
+	for _, routes := range []map[string]*api_code_http.Route{api.GetMap, api.PostMap} {
+		for key, route := range routes {
+			if route.Call != nil {
+				server.methods[getMethodName(key)] = route
+			}
+		}
+	}
+
+	var err error
+	if server.openRPC, err = json.Marshal(generateOpenRPC(server.methods)); err != nil {
+		return nil, err
+	}
+
+	return server, nil
+}
@@ -0,0 +1,116 @@
+package node_http_rpc
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"pandora-pay/network/api_code/api_code_http"
+	"pandora-pay/network/api_implementation/api_http"
+	"testing"
+)
+
+type testEchoRequest struct {
+	Text   string `json:"text"`
+	Height uint64 `json:"height,omitempty"`
+}
+
+type testEchoReply struct {
+	Text          string `json:"text"`
+	Authenticated bool   `json:"authenticated"`
+}
+
+func newTestRPCServer(t *testing.T) *RPCServer {
+
+	api := &api_http.API{
+		GetMap: map[string]*api_code_http.Route{
+			"echo": api_code_http.Handle[testEchoRequest, testEchoReply](func(r *http.Request, args *testEchoRequest, reply *testEchoReply) error {
+				if args.Text == "" {
+					return errors.New("Text is empty")
+				}
+				reply.Text = args.Text
+				return nil
+			}),
+		},
+		PostMap: map[string]*api_code_http.Route{
+			"wallet/echo": api_code_http.HandlePOSTAuthenticated[testEchoRequest, testEchoReply](func(r *http.Request, args *testEchoRequest, reply *testEchoReply, authenticated bool) error {
+				reply.Text = args.Text
+				reply.Authenticated = authenticated
+				return nil
+			}),
+		},
+	}
+
+	server, err := NewRPCServer(api)
+	assert.NoError(t, err)
+	return server
+}
+
+func TestRPCServerProcess(t *testing.T) {
+
+	server := newTestRPCServer(t)
+
+	response := new(RPCResponse)
+	assert.NoError(t, json.Unmarshal(server.Process([]byte(`{"jsonrpc":"2.0","id":1,"method":"echo","params":{"text":"hello"}}`)), response))
+	assert.Nil(t, response.Error)
+	assert.Equal(t, `1`, string(response.ID))
+	assert.JSONEq(t, `{"text":"hello","authenticated":false}`, string(response.Result))
+
+	assert.NoError(t, json.Unmarshal(server.Process([]byte(`{"jsonrpc":"2.0","id":"a","method":"wallet/echo","params":[{"text":"hi"}]}`)), response))
+	assert.JSONEq(t, `{"text":"hi","authenticated":false}`, string(response.Result))
+
+	assert.Nil(t, server.Process([]byte(`{"jsonrpc":"2.0","method":"echo","params":{"text":"notification"}}`)))
+
+	assert.NoError(t, json.Unmarshal(server.Process([]byte(`{"jsonrpc":"2.0","id":1`)), response))
+	assert.Equal(t, RPC_ERROR_PARSE, response.Error.Code)
+	assert.Equal(t, `null`, string(response.ID))
+
+	assert.NoError(t, json.Unmarshal(server.Process([]byte(`[]`)), response))
+	assert.Equal(t, RPC_ERROR_INVALID_REQUEST, response.Error.Code)
+}
+
+func TestRPCServerBatch(t *testing.T) {
+
+	server := newTestRPCServer(t)
+
+	responses := make([]*RPCResponse, 0)
+	assert.NoError(t, json.Unmarshal(server.Process([]byte(`[
+		{"jsonrpc":"2.0","id":1,"method":"echo","params":{"text":"a"}},
+		{"jsonrpc":"2.0","method":"echo","params":{"text":"notification"}},
+		{"jsonrpc":"2.0","id":2,"method":"missing"},
+		{"jsonrpc":"2.0","id":3,"method":"echo","params":{"height":"text"}},
+		{"jsonrpc":"2.0","id":4,"method":"echo"},
+		{"jsonrpc":"1.0","id":5,"method":"echo"},
+		7
+	]`)), &responses))
+
+	assert.Len(t, responses, 6)
+	assert.JSONEq(t, `{"text":"a","authenticated":false}`, string(responses[0].Result))
+	assert.Equal(t, RPC_ERROR_METHOD_NOT_FOUND, responses[1].Error.Code)
+	assert.Equal(t, RPC_ERROR_INVALID_PARAMS, responses[2].Error.Code)
+	assert.Equal(t, RPC_ERROR_SERVER, responses[3].Error.Code)
+	assert.Equal(t, "Text is empty", responses[3].Error.Message)
+	assert.Equal(t, RPC_ERROR_INVALID_REQUEST, responses[4].Error.Code)
+	assert.Equal(t, `5`, string(responses[4].ID))
+	assert.Equal(t, RPC_ERROR_INVALID_REQUEST, responses[5].Error.Code)
+}
+
+func TestRPCServerDiscover(t *testing.T) {
+
+	server := newTestRPCServer(t)
+
+	response := new(RPCResponse)
+	assert.NoError(t, json.Unmarshal(server.Process([]byte(`{"jsonrpc":"2.0","id":1,"method":"rpc.discover"}`)), response))
+
+	doc := new(openRPCDocument)
+	assert.NoError(t, json.Unmarshal(response.Result, doc))
+	assert.Equal(t, OPENRPC_VERSION, doc.OpenRPC)
+	assert.Len(t, doc.Methods, 2)
+	assert.Equal(t, "echo", doc.Methods[0].Name)
+	assert.Equal(t, "text", doc.Methods[0].Params[0].Name)
+	assert.True(t, doc.Methods[0].Params[0].Required)
+	assert.False(t, doc.Methods[0].Params[1].Required)
+	assert.Equal(t, "#/components/schemas/testEchoReply", doc.Methods[0].Result.Schema["$ref"])
+	assert.Equal(t, "pass", doc.Methods[1].Params[len(doc.Methods[1].Params)-1].Name)
+	assert.Contains(t, doc.Components.Schemas, "testEchoReply")
+}
@@ -0,0 +1,42 @@
+package node_http_rpc
+
+import (
+	"encoding/json"
+)
+
+const (
+	RPC_VERSION = "2.0"
+
+	RPC_ERROR_PARSE            = -32700
+	RPC_ERROR_INVALID_REQUEST  = -32600
+	RPC_ERROR_METHOD_NOT_FOUND = -32601
+	RPC_ERROR_INVALID_PARAMS   = -32602
+	RPC_ERROR_INTERNAL         = -32603
+	RPC_ERROR_SERVER           = -32000
+)
+
+type RPCRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type RPCResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+func newRPCError(id json.RawMessage, code int, message string) *RPCResponse {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	return &RPCResponse{RPC_VERSION, nil, &RPCError{code, message}, id}
+}
@@ -16,7 +16,7 @@ type tcpServerType struct {
 
 var TcpServer *tcpServerType
 
-func NewTcpServer(settings *settings.Settings, chain *blockchain.Blockchain, mempool *mempool.Mempool, wallet *wallet.Wallet) error {
+func NewTcpServer(settings *settings.Settings, chain *blockchain.Blockchain, mempool *mempool.Mempool, wallets *wallet.Wallets) error {
 	TcpServer = &tcpServerType{}
-	return node_http.NewHttpServer(chain, settings, mempool, wallet)
+	return node_http.NewHttpServer(chain, settings, mempool, wallets)
 }
@@ -15,11 +15,13 @@ import (
 	"pandora-pay/config"
 	"pandora-pay/config/arguments"
 	"pandora-pay/gui"
+	"pandora-pay/gui/gui_logger"
 	"pandora-pay/helpers/recovery"
 	"pandora-pay/mempool"
 	"pandora-pay/network/banned_nodes"
 	"pandora-pay/network/network_config"
 	"pandora-pay/network/server/node_http"
+	"pandora-pay/network/websocks"
 	"pandora-pay/settings"
 	"pandora-pay/wallet"
 	"path"
@@ -32,11 +34,12 @@ type tcpServerType struct {
 	Port        string
 	URL         *url.URL
 	tcpListener net.Listener
+	p2pListener net.Listener //native tcp sockets of the full nodes
 }
 
 var TcpServer *tcpServerType
 
-func NewTcpServer(settings *settings.Settings, chain *blockchain.Blockchain, mempool *mempool.Mempool, wallet *wallet.Wallet) error {
+func NewTcpServer(settings *settings.Settings, chain *blockchain.Blockchain, mempool *mempool.Mempool, wallets *wallet.Wallets) error {
 
 	TcpServer = &tcpServerType{}
 
@@ -161,26 +164,94 @@ func NewTcpServer(settings *settings.Settings, chain *blockchain.Blockchain, mem
 		if TcpServer.tcpListener, err = tls.Listen("tcp", ":"+port, tlsConfig); err != nil {
 			return err
 		}
-		gui.GUI.Info("TLS Certificate loaded for ", address, port)
+		gui.GUI.Info(gui_logger.SUBSYSTEM_NETWORK, "TLS Certificate loaded for ", address, port)
 	} else {
 		// no ssl at all
 		if TcpServer.tcpListener, err = net.Listen("tcp", ":"+port); err != nil {
 			return errors.New("Error creating TcpServer" + err.Error())
 		}
-		gui.GUI.Warning("No TLS Certificate")
+		gui.GUI.Warning(gui_logger.SUBSYSTEM_NETWORK, "No TLS Certificate")
 	}
 
 	gui.GUI.InfoUpdate("TCP", address+":"+port)
 
-	if err = node_http.NewHttpServer(chain, settings, mempool, wallet); err != nil {
+	if err = node_http.NewHttpServer(chain, settings, mempool, wallets); err != nil {
 		return err
 	}
 
+	if arguments.Arguments["--tcp-server-p2p-port"] != nil {
+		if err = TcpServer.listenP2P(address, shareAddress, tlsConfig); err != nil {
+			return err
+		}
+	}
+
 	recovery.SafeGo(func() {
 		if err := http.Serve(TcpServer.tcpListener, *node_http.HttpServer.GetHttpHandler()); err != nil {
-			gui.GUI.Error("Error opening HTTP server", err)
+			gui.GUI.Error(gui_logger.SUBSYSTEM_NETWORK, "Error opening HTTP server", err)
+		}
+		gui.GUI.Info(gui_logger.SUBSYSTEM_NETWORK, "HTTP server")
+	})
+
+	return nil
+}
+
+//listenP2P accepts the native tcp sockets of the full nodes on a separate port using the same TLS certificate
+func (server *tcpServerType) listenP2P(address string, shareAddress bool, tlsConfig *tls.Config) (err error) {
+
+	portNumber, err := strconv.Atoi(arguments.Arguments["--tcp-server-p2p-port"].(string))
+	if err != nil {
+		return errors.New("P2P Port is not a valid port number")
+	}
+
+	port := strconv.Itoa(portNumber + config.INSTANCE_ID)
+
+	scheme := "tcp"
+	if tlsConfig != nil {
+		scheme = "tls"
+		server.p2pListener, err = tls.Listen("tcp", ":"+port, tlsConfig)
+	} else {
+		server.p2pListener, err = net.Listen("tcp", ":"+port)
+	}
+	if err != nil {
+		return errors.New("Error creating P2P TcpServer" + err.Error())
+	}
+
+	if shareAddress {
+		if server.URL != nil {
+			address = server.URL.Hostname()
+		}
+
+		p2pUrl := &url.URL{Scheme: scheme, Host: address + ":" + port}
+		network_config.NETWORK_P2P_ADDRESS_URL_STRING = p2pUrl.String()
+
+		banned_nodes.BannedNodes.Ban(p2pUrl, "", "You can't connect to yourself", 10*365*24*time.Hour)
+	}
+
+	gui.GUI.InfoUpdate("P2P", address+":"+port)
+
+	recovery.SafeGo(func() {
+		//like http.Serve, the errors are retried with a backoff until the listener is closed
+		var delay time.Duration
+		for {
+			c, err := server.p2pListener.Accept()
+			if err != nil {
+				if errors.Is(err, net.ErrClosed) {
+					return
+				}
+				if delay == 0 {
+					delay = 5 * time.Millisecond
+				} else if delay *= 2; delay > time.Second {
+					delay = time.Second
+				}
+				gui.GUI.Error(gui_logger.SUBSYSTEM_NETWORK, "Error accepting P2P connection", err)
+				time.Sleep(delay)
+				continue
+			}
+			delay = 0
+			recovery.SafeGo(func() {
+				websocks.Websockets.HandleTcpConnection(c)
+			})
 		}
-		gui.GUI.Info("HTTP server")
 	})
 
 	return nil
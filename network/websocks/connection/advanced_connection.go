@@ -7,8 +7,10 @@ import (
 	"github.com/tevino/abool"
 	"pandora-pay/helpers"
 	"pandora-pay/helpers/generics"
+	"pandora-pay/helpers/metrics"
 	"pandora-pay/helpers/msgpack"
 	"pandora-pay/helpers/recovery"
+	"pandora-pay/network/bandwidth"
 	"pandora-pay/network/known_nodes/known_node"
 	"pandora-pay/network/network_config"
 	"pandora-pay/network/websocks/connection/advanced_connection_types"
@@ -28,33 +30,71 @@ const (
 
 var uuidGenerator uint32 //use atomic
 
+var (
+	metricRequests      = metrics.NewCounterVec("pandora_websockets_requests_total", "Websockets requests received per route", "route")
+	metricRequestErrors = metrics.NewCounterVec("pandora_websockets_requests_errors_total", "Websockets requests failed per route", "route")
+)
+
 type AdvancedConnection struct {
 	Authenticated            *abool.AtomicBool
 	UUID                     advanced_connection_types.UUID
-	Conn                     *websock.Conn
+	Conn                     Transport
 	Handshake                *ConnectionHandshake
 	Version                  *semver.Version
+	NodeId                   string //empty for nodes without identity
+	protocolVersion          uint64 //negotiated in the handshake, use atomic
+	features                 uint64 //negotiated in the handshake, use atomic
 	KnownNode                *known_node.KnownNodeScored
 	RemoteAddr               string
+	Traffic                  *ConnectionTraffic
 	answerCounter            uint32
 	Closed                   chan struct{}
+	Initialized              chan struct{}         //closed once the handshake was validated
 	InitializedStatus        InitializedStatusType //use the mutex
 	InitializedStatusMutex   *sync.Mutex
 	IsClosed                 *abool.AtomicBool
 	getMap                   map[string]func(conn *AdvancedConnection, values []byte) (any, error)
 	answerMap                map[uint32]chan *advanced_connection_types.AdvancedConnectionReply
+	answerRoutes             map[uint32]string //route of the awaited answers, used by the traffic
 	answerMapLock            *sync.Mutex
 	Subscriptions            *Subscriptions
 	writeLock                *sync.Mutex
 	ConnectionType           bool
 	onClosedConnection       func(c *AdvancedConnection)
-	onIncreaseKnownNodeScore func(knownNode *known_node.KnownNodeScored, delta int32, isServer bool) bool
+	onIncreaseKnownNodeScore func(c *AdvancedConnection, delta int32) bool
 }
 
 func (c *AdvancedConnection) GetTimeout() time.Duration {
 	return network_config.WEBSOCKETS_TIMEOUT
 }
 
+//SetProtocol stores the protocol negotiated in the handshake. The requests of the peer are processed while the handshake is done
+func (c *AdvancedConnection) SetProtocol(version uint64, features FeaturesType) {
+	atomic.StoreUint64(&c.protocolVersion, version)
+	atomic.StoreUint64(&c.features, uint64(features))
+}
+
+func (c *AdvancedConnection) GetProtocolVersion() uint64 {
+	return atomic.LoadUint64(&c.protocolVersion)
+}
+
+//HasFeature returns true when both peers support the feature
+func (c *AdvancedConnection) HasFeature(feature FeaturesType) bool {
+	return FeaturesType(atomic.LoadUint64(&c.features)).Has(feature)
+}
+
+//WaitInitialized waits until the handshake of the connection was validated
+func (c *AdvancedConnection) WaitInitialized() error {
+	select {
+	case <-c.Initialized:
+		return nil
+	case <-c.Closed:
+		return errors.New("Closed")
+	case <-time.After(network_config.WEBSOCKETS_TIMEOUT):
+		return errors.New("Connection was not initialized")
+	}
+}
+
 func (c *AdvancedConnection) Close() error {
 	if c.IsClosed.SetToIf(false, true) {
 		close(c.Closed)
@@ -64,7 +104,7 @@ func (c *AdvancedConnection) Close() error {
 	return nil
 }
 
-func (c *AdvancedConnection) connSendMessage(message any, ctxDuration time.Duration) error {
+func (c *AdvancedConnection) connSendMessage(message any, route string, priority bandwidth.PriorityType, ctxDuration time.Duration) error {
 
 	data, err := msgpack.Marshal(message)
 	if err != nil {
@@ -75,14 +115,28 @@ func (c *AdvancedConnection) connSendMessage(message any, ctxDuration time.Durat
 		return errors.New("Closed")
 	}
 
+	if _, err = c.Traffic.upload.Wait(len(data), priority, c.Closed); err != nil {
+		return err
+	}
+	if _, err = bandwidth.Bandwidth.Upload.Wait(len(data), priority, c.Closed); err != nil {
+		return err
+	}
+
 	c.writeLock.Lock()
 	defer c.writeLock.Unlock()
 
 	c.Conn.SetWriteDeadline(time.Now().Add(generics.Max(ctxDuration, network_config.WEBSOCKETS_TIMEOUT)))
-	return c.Conn.WriteMessage(websock.BinaryMessage, data)
+	if err = c.Conn.WriteMessage(websock.BinaryMessage, data); err != nil {
+		return err
+	}
+
+	c.Traffic.sent(route, len(data))
+	bandwidth.Bandwidth.Sent(priority, len(data))
+	return nil
 }
 
-func (c *AdvancedConnection) sendNow(replyBackId uint32, name []byte, data []byte, reply bool, ctxDuration time.Duration) error {
+//sendNow sends the message. The replies are counted for the route of the request
+func (c *AdvancedConnection) sendNow(replyBackId uint32, name []byte, data []byte, reply bool, route string, priority bandwidth.PriorityType, ctxDuration time.Duration) error {
 	message := &advanced_connection_types.AdvancedConnectionMessage{
 		replyBackId,
 		reply,
@@ -90,7 +144,7 @@ func (c *AdvancedConnection) sendNow(replyBackId uint32, name []byte, data []byt
 		name,
 		data,
 	}
-	return c.connSendMessage(message, ctxDuration)
+	return c.connSendMessage(message, route, priority, ctxDuration)
 }
 
 func (c *AdvancedConnection) sendNowAwait(name []byte, data []byte, reply bool, ctxParent context.Context, ctxDuration time.Duration) *advanced_connection_types.AdvancedConnectionReply {
@@ -110,6 +164,7 @@ func (c *AdvancedConnection) sendNowAwait(name []byte, data []byte, reply bool,
 			delete(c.answerMap, replyBackId)
 			closeCn = true
 		}
+		delete(c.answerRoutes, replyBackId)
 		c.answerMapLock.Unlock()
 		if closeCn {
 			close(eventCn)
@@ -126,9 +181,10 @@ func (c *AdvancedConnection) sendNowAwait(name []byte, data []byte, reply bool,
 
 	c.answerMapLock.Lock()
 	c.answerMap[replyBackId] = eventCn
+	c.answerRoutes[replyBackId] = string(name)
 	c.answerMapLock.Unlock()
 
-	if err := c.connSendMessage(message, ctxDuration); err != nil {
+	if err := c.connSendMessage(message, string(name), bandwidth.GetRoutePriority(string(name)), ctxDuration); err != nil {
 		return &advanced_connection_types.AdvancedConnectionReply{nil, err, false}
 	}
 
@@ -143,7 +199,7 @@ func (c *AdvancedConnection) sendNowAwait(name []byte, data []byte, reply bool,
 }
 
 func (c *AdvancedConnection) Send(name []byte, data []byte, ctxDuration time.Duration) error {
-	return c.sendNow(0, name, data, false, ctxDuration)
+	return c.sendNow(0, name, data, false, string(name), bandwidth.GetRoutePriority(string(name)), ctxDuration)
 }
 
 func (c *AdvancedConnection) SendJSON(name []byte, data any, ctxDuration time.Duration) error {
@@ -151,7 +207,7 @@ func (c *AdvancedConnection) SendJSON(name []byte, data any, ctxDuration time.Du
 	if err != nil {
 		return err
 	}
-	return c.sendNow(0, name, out, false, ctxDuration)
+	return c.sendNow(0, name, out, false, string(name), bandwidth.GetRoutePriority(string(name)), ctxDuration)
 }
 
 func (c *AdvancedConnection) SendAwaitAnswer(name []byte, data []byte, ctxParent context.Context, ctxDuration time.Duration) *advanced_connection_types.AdvancedConnectionReply {
@@ -178,7 +234,8 @@ func SendJSONAwaitAnswer[T any](c *AdvancedConnection, name []byte, data any, ct
 	return final, nil
 }
 
-func (c *AdvancedConnection) get(message *advanced_connection_types.AdvancedConnectionMessage) (final []byte, err error) {
+//get returns the answer of the request. The replies implementing bandwidth.PriorityReply are sent with their own priority instead of the one of the route
+func (c *AdvancedConnection) get(message *advanced_connection_types.AdvancedConnectionMessage) (final []byte, priority bandwidth.PriorityType, err error) {
 
 	defer func() {
 		if err2 := recover(); err2 != nil {
@@ -189,9 +246,14 @@ func (c *AdvancedConnection) get(message *advanced_connection_types.AdvancedConn
 	var output any
 
 	route := string(message.Name)
+	priority = bandwidth.GetRoutePriority(route)
 	if callback := c.getMap[route]; callback != nil {
-		output, err = callback(c, message.Data)
+		metricRequests.Inc(route)
+		if output, err = callback(c, message.Data); err != nil {
+			metricRequestErrors.Inc(route)
+		}
 	} else {
+		metricRequests.Inc("unknown")
 		err = errors.New("Unknown request")
 	}
 
@@ -199,6 +261,10 @@ func (c *AdvancedConnection) get(message *advanced_connection_types.AdvancedConn
 		return
 	}
 
+	if v, ok := output.(bandwidth.PriorityReply); ok {
+		priority = v.GetPriority()
+	}
+
 	switch v := output.(type) {
 	case string:
 		final = []byte(v)
@@ -216,13 +282,14 @@ func (c *AdvancedConnection) processRead(message *advanced_connection_types.Adva
 
 	if !message.ReplyStatus {
 
-		out, err := c.get(message)
+		out, priority, err := c.get(message)
+		route := c.getMessageRoute(message)
 
 		if message.ReplyAwait {
 			if err != nil {
-				_ = c.sendNow(message.ReplyId, []byte{0}, []byte(err.Error()), true, 0)
+				_ = c.sendNow(message.ReplyId, []byte{0}, []byte(err.Error()), true, route, priority, 0)
 			} else {
-				_ = c.sendNow(message.ReplyId, []byte{1}, out, true, 0)
+				_ = c.sendNow(message.ReplyId, []byte{1}, out, true, route, priority, 0)
 			}
 		}
 
@@ -240,6 +307,7 @@ func (c *AdvancedConnection) processRead(message *advanced_connection_types.Adva
 		if cn != nil {
 			delete(c.answerMap, message.ReplyId)
 		}
+		delete(c.answerRoutes, message.ReplyId)
 		c.answerMapLock.Unlock()
 
 		if cn != nil {
@@ -251,6 +319,25 @@ func (c *AdvancedConnection) processRead(message *advanced_connection_types.Adva
 	}
 }
 
+//getMessageRoute returns the route of a request or the route of the request answered by a reply
+func (c *AdvancedConnection) getMessageRoute(message *advanced_connection_types.AdvancedConnectionMessage) string {
+
+	if !message.ReplyStatus {
+		if route := string(message.Name); c.getMap[route] != nil {
+			return route
+		}
+		return "unknown"
+	}
+
+	c.answerMapLock.Lock()
+	defer c.answerMapLock.Unlock()
+
+	if route, ok := c.answerRoutes[message.ReplyId]; ok {
+		return route
+	}
+	return "unknown"
+}
+
 func (c *AdvancedConnection) ReadPump() {
 
 	c.Conn.SetReadLimit(int64(network_config.WEBSOCKETS_MAX_READ))
@@ -267,11 +354,29 @@ func (c *AdvancedConnection) ReadPump() {
 			return
 		}
 
+		message := &advanced_connection_types.AdvancedConnectionMessage{}
+		if err = msgpack.Unmarshal(read, message); err != nil {
+			continue
+		}
+
+		route := c.getMessageRoute(message)
+		priority := bandwidth.GetRoutePriority(route)
+
+		c.Traffic.received(route, len(read))
+		bandwidth.Bandwidth.Received(priority, len(read))
+
+		//not reading applies back pressure to the peer
+		delayed, err := bandwidth.Bandwidth.Download.Wait(len(read), priority, c.Closed)
+		if err != nil {
+			c.Close()
+			return
+		}
+		if delayed {
+			c.Conn.SetReadDeadline(time.Now().Add(network_config.WEBSOCKETS_PONG_WAIT))
+		}
+
 		recovery.SafeGo(func() {
-			message := &advanced_connection_types.AdvancedConnectionMessage{}
-			if err = msgpack.Unmarshal(read, message); err == nil && message != nil {
-				c.processRead(message)
-			}
+			c.processRead(message)
 		})
 
 	}
@@ -318,7 +423,7 @@ func (c *AdvancedConnection) IncreaseKnownNodeScore() {
 
 		select {
 		case <-ticker.C:
-			if !c.onIncreaseKnownNodeScore(c.KnownNode, 1, c.ConnectionType) {
+			if !c.onIncreaseKnownNodeScore(c, 1) {
 				break
 			}
 		case <-c.Closed:
@@ -329,7 +434,7 @@ func (c *AdvancedConnection) IncreaseKnownNodeScore() {
 
 }
 
-func NewAdvancedConnection(conn *websock.Conn, remoteAddr string, knownNode *known_node.KnownNodeScored, getMap map[string]func(conn *AdvancedConnection, values []byte) (any, error), connectionType bool, newSubscriptionCn, removeSubscriptionCn chan<- *SubscriptionNotification, onClosedConnection func(*AdvancedConnection), onIncreaseKnownNodeScore func(*known_node.KnownNodeScored, int32, bool) bool) (*AdvancedConnection, error) {
+func NewAdvancedConnection(conn Transport, remoteAddr string, knownNode *known_node.KnownNodeScored, getMap map[string]func(conn *AdvancedConnection, values []byte) (any, error), connectionType bool, newSubscriptionCn, removeSubscriptionCn chan<- *SubscriptionNotification, onClosedConnection func(*AdvancedConnection), onIncreaseKnownNodeScore func(*AdvancedConnection, int32) bool) (*AdvancedConnection, error) {
 
 	//making sure u is not collided with UUID_ALL and UUID_SKIP_ALL
 	uuid := advanced_connection_types.UUID(atomic.AddUint32(&uuidGenerator, 1))
@@ -343,15 +448,21 @@ func NewAdvancedConnection(conn *websock.Conn, remoteAddr string, knownNode *kno
 		conn,
 		nil,
 		nil,
+		"",
+		0,
+		0,
 		knownNode,
 		remoteAddr,
+		NewConnectionTraffic(),
 		0,
 		make(chan struct{}),
+		make(chan struct{}),
 		INITIALIZED_STATUS_CREATED,
 		&sync.Mutex{},
 		abool.New(),
 		getMap,
 		make(map[uint32]chan *advanced_connection_types.AdvancedConnectionReply),
+		make(map[uint32]string),
 		&sync.Mutex{},
 		nil,
 		&sync.Mutex{},
@@ -0,0 +1,50 @@
+package connection
+
+import (
+	"errors"
+	"pandora-pay/helpers/generics"
+)
+
+//FeaturesType is a bit set of the optional messages supported by a node. Only the features supported by both peers are used
+type FeaturesType uint64
+
+const (
+	FEATURE_MEMPOOL_RECONCILIATION FeaturesType = 1 << iota
+	FEATURE_COMPACT_BLOCKS
+	FEATURE_DANDELION
+)
+
+const (
+	PROTOCOL_VERSION_LEGACY = uint64(1) //nodes which don't send the protocol versions in the handshake
+	PROTOCOL_VERSION_MIN    = uint64(1)
+	PROTOCOL_VERSION        = uint64(2)
+)
+
+var PROTOCOL_FEATURES = FEATURE_MEMPOOL_RECONCILIATION | FEATURE_COMPACT_BLOCKS | FEATURE_DANDELION
+
+func (features FeaturesType) Has(feature FeaturesType) bool {
+	return features&feature == feature
+}
+
+//NegotiateProtocol returns the highest protocol version supported by both peers and the common features
+func (handshake *ConnectionHandshake) NegotiateProtocol() (uint64, FeaturesType, error) {
+
+	min, max := handshake.ProtocolMinVersion, handshake.ProtocolMaxVersion
+	if max == 0 {
+		min, max = PROTOCOL_VERSION_LEGACY, PROTOCOL_VERSION_LEGACY
+	}
+	if min > max {
+		return 0, 0, errors.New("Invalid protocol versions")
+	}
+
+	version := generics.Min(max, PROTOCOL_VERSION)
+	if version < generics.Max(min, PROTOCOL_VERSION_MIN) {
+		return 0, 0, errors.New("Protocol version is not supported")
+	}
+
+	if version == PROTOCOL_VERSION_LEGACY {
+		return version, 0, nil
+	}
+
+	return version, handshake.Features & PROTOCOL_FEATURES, nil
+}
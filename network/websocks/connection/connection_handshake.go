@@ -7,11 +7,23 @@ import (
 )
 
 type ConnectionHandshake struct {
-	Name      string                   `json:"name" msgpack:"name"`
-	Version   string                   `json:"version" msgpack:"version"`
-	Network   uint64                   `json:"network" msgpack:"network"`
-	Consensus config.NodeConsensusType `json:"consensus" msgpack:"consensus"`
-	URL       string                   `json:"url" msgpack:"url"`
+	Name               string                   `json:"name" msgpack:"name"`
+	Version            string                   `json:"version" msgpack:"version"`
+	Network            uint64                   `json:"network" msgpack:"network"`
+	Consensus          config.NodeConsensusType `json:"consensus" msgpack:"consensus"`
+	URL                string                   `json:"url" msgpack:"url"`
+	PublicKey          []byte                   `json:"publicKey,omitempty" msgpack:"publicKey,omitempty"` //node identity
+	Nonce              []byte                   `json:"nonce,omitempty" msgpack:"nonce,omitempty"`         //nonce of the node answering, signed together with the nonce of the request
+	Signature          []byte                   `json:"signature,omitempty" msgpack:"signature,omitempty"` //signature of the handshake transcript
+	ProtocolMinVersion uint64                   `json:"protocolMinVersion,omitempty" msgpack:"protocolMinVersion,omitempty"`
+	ProtocolMaxVersion uint64                   `json:"protocolMaxVersion,omitempty" msgpack:"protocolMaxVersion,omitempty"`
+	Features           FeaturesType             `json:"features,omitempty" msgpack:"features,omitempty"`
+	P2PURL             string                   `json:"p2pUrl,omitempty" msgpack:"p2pUrl,omitempty"` //native tcp socket of the full node
+}
+
+type ConnectionHandshakeRequest struct {
+	Nonce     []byte `json:"nonce" msgpack:"nonce"`
+	PublicKey []byte `json:"publicKey,omitempty" msgpack:"publicKey,omitempty"` //node identity of the requester, signed in the transcript
 }
 
 func (handshake *ConnectionHandshake) ValidateHandshake() (*semver.Version, error) {
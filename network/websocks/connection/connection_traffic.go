@@ -0,0 +1,69 @@
+package connection
+
+import (
+	"pandora-pay/network/bandwidth"
+	"pandora-pay/network/network_config"
+	"sync"
+	"sync/atomic"
+)
+
+type ConnectionRouteTraffic struct {
+	Sent     uint64 `json:"sent" msgpack:"sent"`
+	Received uint64 `json:"received" msgpack:"received"`
+}
+
+//ConnectionTraffic counts the bytes exchanged with the peer. The routes unknown to the node are counted as "unknown"
+type ConnectionTraffic struct {
+	Sent     atomic.Uint64
+	Received atomic.Uint64
+	upload   *bandwidth.TokenBucket //limit of the peer
+	routes   map[string]*ConnectionRouteTraffic
+	lock     *sync.Mutex
+}
+
+func (traffic *ConnectionTraffic) sent(route string, size int) {
+	traffic.Sent.Add(uint64(size))
+
+	traffic.lock.Lock()
+	defer traffic.lock.Unlock()
+	traffic.getRoute(route).Sent += uint64(size)
+}
+
+func (traffic *ConnectionTraffic) received(route string, size int) {
+	traffic.Received.Add(uint64(size))
+
+	traffic.lock.Lock()
+	defer traffic.lock.Unlock()
+	traffic.getRoute(route).Received += uint64(size)
+}
+
+func (traffic *ConnectionTraffic) getRoute(route string) *ConnectionRouteTraffic {
+	routeTraffic := traffic.routes[route]
+	if routeTraffic == nil {
+		routeTraffic = &ConnectionRouteTraffic{}
+		traffic.routes[route] = routeTraffic
+	}
+	return routeTraffic
+}
+
+//GetRoutes returns a copy of the bytes by route
+func (traffic *ConnectionTraffic) GetRoutes() map[string]*ConnectionRouteTraffic {
+	traffic.lock.Lock()
+	defer traffic.lock.Unlock()
+
+	out := make(map[string]*ConnectionRouteTraffic, len(traffic.routes))
+	for route, routeTraffic := range traffic.routes {
+		out[route] = &ConnectionRouteTraffic{routeTraffic.Sent, routeTraffic.Received}
+	}
+	return out
+}
+
+func NewConnectionTraffic() *ConnectionTraffic {
+	return &ConnectionTraffic{
+		atomic.Uint64{},
+		atomic.Uint64{},
+		bandwidth.NewTokenBucket(&network_config.NETWORK_PEER_UPLOAD_LIMIT),
+		make(map[string]*ConnectionRouteTraffic),
+		&sync.Mutex{},
+	}
+}
@@ -0,0 +1,14 @@
+package connection
+
+import "time"
+
+//Transport carries the msgpack messages of the AdvancedConnection. It is implemented by the websockets and by the native tcp sockets used between full nodes
+type Transport interface {
+	ReadMessage() (int, []byte, error)
+	WriteMessage(messageType int, data []byte) error
+	SetReadLimit(limit int64)
+	SetReadDeadline(t time.Time) error
+	SetWriteDeadline(t time.Time) error
+	SetPongHandler(handler func(appData string) error)
+	Close() error
+}
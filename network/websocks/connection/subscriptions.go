@@ -27,8 +27,10 @@ func checkSubscriptionLength(key []byte, subscriptionType api_code_types.Subscri
 		length = cryptography.PublicKeySize
 	case api_code_types.SUBSCRIPTION_ASSET:
 		length = config_coins.ASSET_LENGTH
-	case api_code_types.SUBSCRIPTION_TRANSACTION:
+	case api_code_types.SUBSCRIPTION_TRANSACTION, api_code_types.SUBSCRIPTION_TX_STATUS:
 		length = cryptography.HashSize
+	case api_code_types.SUBSCRIPTION_BLOCKS, api_code_types.SUBSCRIPTION_REORGS, api_code_types.SUBSCRIPTION_MEMPOOL:
+		length = 0 //global streams don't have a key
 	}
 	if len(key) != length {
 		return errors.New("Key is invalid")
@@ -0,0 +1,213 @@
+//go:build !js
+// +build !js
+
+package tcp_sock
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"golang.org/x/net/proxy"
+	"io"
+	"net"
+	"net/url"
+	"pandora-pay/config/arguments"
+	"pandora-pay/network/network_config"
+	"pandora-pay/network/websocks/websock"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+//the frames are 1 byte the message type, 4 bytes the length big endian and the payload. The message types are the same as the websockets
+const TCP_SOCK_FRAME_HEADER_SIZE = 5
+
+//TCP_SOCK_MAGIC is sent by the client right after connecting, so that other protocols are rejected early
+var TCP_SOCK_MAGIC = []byte("PandoraPay-P2P/1")
+
+//Conn is a native tcp (or tls) socket between full nodes carrying the same messages as websock.Conn
+type Conn struct {
+	conn        net.Conn
+	reader      *bufio.Reader
+	writeLock   *sync.Mutex
+	limit       int64 //use atomic
+	pongHandler func(string) error
+}
+
+func (c *Conn) writeFrame(messageType int, data []byte) error {
+
+	frame := make([]byte, TCP_SOCK_FRAME_HEADER_SIZE+len(data))
+	frame[0] = byte(messageType)
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(data)))
+	copy(frame[TCP_SOCK_FRAME_HEADER_SIZE:], data)
+
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+
+	_, err := c.conn.Write(frame)
+	return err
+}
+
+func (c *Conn) readFrame() (int, []byte, error) {
+
+	header := make([]byte, TCP_SOCK_FRAME_HEADER_SIZE)
+	if _, err := io.ReadFull(c.reader, header); err != nil {
+		return 0, nil, err
+	}
+
+	length := int64(binary.BigEndian.Uint32(header[1:]))
+	if limit := atomic.LoadInt64(&c.limit); limit > 0 && length > limit {
+		return 0, nil, errors.New("Frame is too big")
+	}
+
+	//the buffer grows with the bytes received, not with the length claimed by the header
+	data := &bytes.Buffer{}
+	if _, err := io.CopyN(data, c.reader, length); err != nil {
+		return 0, nil, err
+	}
+
+	return int(header[0]), data.Bytes(), nil
+}
+
+//ReadMessage returns the next data message. The pings are answered and the pongs are passed to the pong handler
+func (c *Conn) ReadMessage() (int, []byte, error) {
+	for {
+
+		messageType, data, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch messageType {
+		case websock.TextMessage, websock.BinaryMessage:
+			return messageType, data, nil
+		case websock.PingMessage:
+			if err = c.conn.SetWriteDeadline(time.Now().Add(network_config.WEBSOCKETS_TIMEOUT)); err != nil {
+				return 0, nil, err
+			}
+			if err = c.writeFrame(websock.PongMessage, data); err != nil {
+				return 0, nil, err
+			}
+		case websock.PongMessage:
+			if c.pongHandler != nil {
+				if err = c.pongHandler(string(data)); err != nil {
+					return 0, nil, err
+				}
+			}
+		case websock.CloseMessage:
+			return 0, nil, errors.New("Closed")
+		default:
+			return 0, nil, errors.New("Invalid message type")
+		}
+	}
+}
+
+func (c *Conn) WriteMessage(messageType int, data []byte) error {
+	return c.writeFrame(messageType, data)
+}
+
+func (c *Conn) SetReadLimit(limit int64) {
+	atomic.StoreInt64(&c.limit, limit)
+}
+
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	return c.conn.SetReadDeadline(t)
+}
+
+func (c *Conn) SetWriteDeadline(t time.Time) error {
+	return c.conn.SetWriteDeadline(t)
+}
+
+//SetPongHandler must be called before reading
+func (c *Conn) SetPongHandler(handler func(string) error) {
+	c.pongHandler = handler
+}
+
+func (c *Conn) RemoteAddr() string {
+	return c.conn.RemoteAddr().String()
+}
+
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
+
+func newConn(conn net.Conn) *Conn {
+	return &Conn{
+		conn,
+		bufio.NewReader(conn),
+		&sync.Mutex{},
+		0,
+		nil,
+	}
+}
+
+//IsURL returns true for the urls of the native transport: tcp://host:port and tls://host:port
+func IsURL(URL string) bool {
+	u, err := url.Parse(URL)
+	return err == nil && (u.Scheme == "tcp" || u.Scheme == "tls")
+}
+
+func Dial(URL string) (*Conn, error) {
+
+	u, err := url.Parse(URL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "tcp" && u.Scheme != "tls" {
+		return nil, errors.New("Invalid tcp socket scheme")
+	}
+
+	//tcp proxy
+	var dialer proxy.Dialer = &net.Dialer{Timeout: network_config.WEBSOCKETS_TIMEOUT}
+	if arguments.Arguments["--tcp-proxy"] != nil {
+
+		proxyUrl, err := url.Parse(arguments.Arguments["--tcp-proxy"].(string))
+		if err != nil {
+			return nil, err
+		}
+
+		if dialer, err = proxy.FromURL(proxyUrl, dialer); err != nil {
+			return nil, err
+		}
+	}
+
+	conn, err := dialer.Dial("tcp", u.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme == "tls" {
+		conn = tls.Client(conn, &tls.Config{ServerName: u.Hostname()})
+	}
+
+	if err = conn.SetWriteDeadline(time.Now().Add(network_config.WEBSOCKETS_TIMEOUT)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if _, err = conn.Write(TCP_SOCK_MAGIC); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return newConn(conn), nil
+}
+
+//Accept checks the magic sent by the client
+func Accept(conn net.Conn) (*Conn, error) {
+
+	if err := conn.SetReadDeadline(time.Now().Add(network_config.WEBSOCKETS_TIMEOUT)); err != nil {
+		return nil, err
+	}
+
+	magic := make([]byte, len(TCP_SOCK_MAGIC))
+	if _, err := io.ReadFull(conn, magic); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(magic, TCP_SOCK_MAGIC) {
+		return nil, errors.New("Invalid tcp socket magic")
+	}
+
+	return newConn(conn), nil
+}
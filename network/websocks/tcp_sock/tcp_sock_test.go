@@ -0,0 +1,85 @@
+//go:build !js
+// +build !js
+
+package tcp_sock
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net"
+	"pandora-pay/network/websocks/websock"
+	"runtime"
+	"testing"
+)
+
+func TestConnFrames(t *testing.T) {
+
+	clientPipe, serverPipe := net.Pipe()
+	defer clientPipe.Close()
+	defer serverPipe.Close()
+
+	go func() {
+		clientPipe.Write(TCP_SOCK_MAGIC)
+	}()
+
+	server, err := Accept(serverPipe)
+	assert.Nil(t, err, "Accept failed")
+	client := newConn(clientPipe)
+
+	pong := make(chan string, 1)
+	client.SetPongHandler(func(data string) error {
+		pong <- data
+		return nil
+	})
+
+	go func() {
+		client.WriteMessage(websock.PingMessage, []byte("ping"))
+		client.WriteMessage(websock.BinaryMessage, []byte{1, 2, 3})
+	}()
+
+	//the ping is answered while reading
+	go func() {
+		client.ReadMessage()
+	}()
+
+	messageType, data, err := server.ReadMessage()
+	assert.Nil(t, err, "Read failed")
+	assert.Equal(t, websock.BinaryMessage, messageType, "Message type is invalid")
+	assert.Equal(t, []byte{1, 2, 3}, data, "Message is invalid")
+	assert.Equal(t, "ping", <-pong, "Pong is invalid")
+
+	server.SetReadLimit(2)
+	go func() {
+		client.WriteMessage(websock.BinaryMessage, []byte{1, 2, 3})
+	}()
+	_, _, err = server.ReadMessage()
+	assert.NotNil(t, err, "Read limit was not applied")
+
+}
+
+func TestConnFrameClaimedLength(t *testing.T) {
+
+	clientPipe, serverPipe := net.Pipe()
+	defer serverPipe.Close()
+
+	server := newConn(serverPipe)
+
+	//the header claims 4GB, but the peer sends only 3 bytes
+	go func() {
+		clientPipe.Write([]byte{byte(websock.BinaryMessage), 0xff, 0xff, 0xff, 0xff, 1, 2, 3})
+		clientPipe.Close()
+	}()
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	_, _, err := server.ReadMessage()
+	runtime.ReadMemStats(&after)
+
+	assert.NotNil(t, err, "Incomplete frame was accepted")
+	assert.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(1<<20), "Claimed length was allocated")
+}
+
+func TestIsURL(t *testing.T) {
+	assert.True(t, IsURL("tcp://127.0.0.1:8090"))
+	assert.True(t, IsURL("tls://domain.net:8090"))
+	assert.False(t, IsURL("ws://127.0.0.1:8080/ws"))
+}
@@ -124,7 +124,7 @@ func Dial(url string) (c *Conn, err error) {
 	return
 }
 
-func (c *Conn) SetPongHandler(cb func(string) error) error {
+func (c *Conn) SetPongHandler(cb func(string) error) {
 	go func() {
 		for {
 			select {
@@ -136,12 +136,10 @@ func (c *Conn) SetPongHandler(cb func(string) error) error {
 			}
 		}
 	}()
-	return nil
 }
 
-func (c *Conn) SetReadLimit(limit int64) error {
+func (c *Conn) SetReadLimit(limit int64) {
 	c.limit.Store(limit)
-	return nil
 }
 
 func (c *Conn) SetWriteDeadline(limit time.Time) error {
@@ -1,9 +1,9 @@
 package websocks
 
 import (
+	"pandora-pay/helpers/recovery"
 	"pandora-pay/network/known_nodes/known_node"
 	"pandora-pay/network/websocks/connection"
-	"pandora-pay/network/websocks/websock"
 )
 
 type WebsocketClient struct {
@@ -17,7 +17,7 @@ func (this *websocketsType) NewWebsocketClient(knownNode *known_node.KnownNodeSc
 		knownNode, nil,
 	}
 
-	c, err := websock.Dial(knownNode.URL)
+	c, err := dial(knownNode.URL)
 	if err != nil {
 		return nil, err
 	}
@@ -28,3 +28,32 @@ func (this *websocketsType) NewWebsocketClient(knownNode *known_node.KnownNodeSc
 
 	return wsClient, nil
 }
+
+//ProbeKnownNode dials the known node only to validate its handshake. The probe is not registered as a connected peer, so nothing is synced
+func (this *websocketsType) ProbeKnownNode(knownNode *known_node.KnownNodeScored) error {
+
+	c, err := dial(knownNode.URL)
+	if err != nil {
+		return err
+	}
+
+	//the peer can request our handshake as well
+	getMap := map[string]func(conn *connection.AdvancedConnection, values []byte) (any, error){
+		"handshake": this.apiGetMap["handshake"],
+	}
+
+	conn, err := connection.NewAdvancedConnection(c, knownNode.URL, knownNode, getMap, false, nil, nil, func(*connection.AdvancedConnection) {}, nil)
+	if err != nil {
+		c.Close()
+		return err
+	}
+	defer conn.Close()
+
+	recovery.SafeGo(conn.ReadPump)
+
+	return this.requestHandshake(conn)
+}
+
+func (this *WebsocketClient) Close() error {
+	return this.conn.Close()
+}
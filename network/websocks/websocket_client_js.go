@@ -0,0 +1,23 @@
+//go:build js
+// +build js
+
+package websocks
+
+import (
+	"errors"
+	"pandora-pay/network/websocks/connection"
+	"pandora-pay/network/websocks/websock"
+	"strings"
+)
+
+//dial rejects the native tcp sockets as the browsers can open only websockets
+func dial(URL string) (connection.Transport, error) {
+	if strings.HasPrefix(URL, "tcp://") || strings.HasPrefix(URL, "tls://") {
+		return nil, errors.New("Native tcp sockets are not supported")
+	}
+	c, err := websock.Dial(URL)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
@@ -0,0 +1,26 @@
+//go:build !js
+// +build !js
+
+package websocks
+
+import (
+	"pandora-pay/network/websocks/connection"
+	"pandora-pay/network/websocks/tcp_sock"
+	"pandora-pay/network/websocks/websock"
+)
+
+//dial uses the native tcp sockets for the tcp:// and tls:// urls and the websockets otherwise
+func dial(URL string) (connection.Transport, error) {
+	if tcp_sock.IsURL(URL) {
+		c, err := tcp_sock.Dial(URL)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+	c, err := websock.Dial(URL)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
@@ -4,18 +4,21 @@
 package websocks
 
 import (
+	"net"
 	"net/http"
 	"pandora-pay/helpers/recovery"
 	"pandora-pay/network/connected_nodes"
 	"pandora-pay/network/known_nodes"
 	"pandora-pay/network/network_config"
+	"pandora-pay/network/websocks/connection"
+	"pandora-pay/network/websocks/tcp_sock"
 	"pandora-pay/network/websocks/websock"
 	"sync/atomic"
 )
 
 func (this *websocketsType) HandleUpgradeConnection(w http.ResponseWriter, r *http.Request) {
 
-	if atomic.LoadInt64(&connected_nodes.ConnectedNodes.ServerSockets) >= network_config.WEBSOCKETS_NETWORK_SERVER_MAX {
+	if atomic.LoadInt64(&connected_nodes.ConnectedNodes.ServerSockets) >= atomic.LoadInt64(&network_config.WEBSOCKETS_NETWORK_SERVER_MAX) {
 		http.Error(w, "Too many websockets", 400)
 		return
 	}
@@ -25,14 +28,43 @@ func (this *websocketsType) HandleUpgradeConnection(w http.ResponseWriter, r *ht
 		return
 	}
 
-	conn, err := Websockets.NewConnection(c, r.RemoteAddr, nil, true)
+	this.newServerConnection(c, r.RemoteAddr)
+}
+
+//HandleTcpConnection accepts a native tcp socket of a full node
+func (this *websocketsType) HandleTcpConnection(c net.Conn) {
+
+	if atomic.LoadInt64(&connected_nodes.ConnectedNodes.ServerSockets) >= atomic.LoadInt64(&network_config.WEBSOCKETS_NETWORK_SERVER_MAX) {
+		c.Close()
+		return
+	}
+
+	conn, err := tcp_sock.Accept(c)
 	if err != nil {
+		c.Close()
 		return
 	}
 
-	if conn.Handshake.URL != "" {
-		conn.KnownNode, err = known_nodes.KnownNodes.AddKnownNode(conn.Handshake.URL, false)
+	this.newServerConnection(conn, conn.RemoteAddr())
+}
+
+func (this *websocketsType) newServerConnection(c connection.Transport, remoteAddr string) {
+
+	conn, err := Websockets.NewConnection(c, remoteAddr, nil, true)
+	if err != nil {
+		return
+	}
+
+	//the full nodes listening for native tcp sockets are known by their tcp url
+	url := conn.Handshake.URL
+	if conn.Handshake.P2PURL != "" {
+		url = conn.Handshake.P2PURL
+	}
+
+	if url != "" {
+		conn.KnownNode, err = known_nodes.KnownNodes.AddKnownNode(url, false)
 		if conn.KnownNode != nil {
+			known_nodes.KnownNodes.SetKnownNodeId(conn.KnownNode, conn.NodeId)
 			recovery.SafeGo(conn.IncreaseKnownNodeScore)
 		}
 	}
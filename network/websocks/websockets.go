@@ -9,7 +9,9 @@ import (
 	"pandora-pay/config"
 	"pandora-pay/config/globals"
 	"pandora-pay/gui"
+	"pandora-pay/gui/gui_logger"
 	"pandora-pay/helpers/generics"
+	"pandora-pay/helpers/metrics"
 	"pandora-pay/helpers/msgpack"
 	"pandora-pay/helpers/multicast"
 	"pandora-pay/helpers/recovery"
@@ -19,9 +21,9 @@ import (
 	"pandora-pay/network/known_nodes"
 	"pandora-pay/network/known_nodes/known_node"
 	"pandora-pay/network/network_config"
+	"pandora-pay/network/node_identity"
 	"pandora-pay/network/websocks/connection"
 	"pandora-pay/network/websocks/connection/advanced_connection_types"
-	"pandora-pay/network/websocks/websock"
 	"pandora-pay/settings"
 	"strconv"
 	"sync/atomic"
@@ -46,6 +48,11 @@ type websocketsType struct {
 
 var Websockets *websocketsType
 
+var (
+	metricClients       = metrics.NewGauge("pandora_websockets_clients", "Websockets connected by the node to other nodes")
+	metricServerSockets = metrics.NewGauge("pandora_websockets_server_sockets", "Websockets accepted by the node server")
+)
+
 func (this *websocketsType) GetClients() int64 {
 	return atomic.LoadInt64(&connected_nodes.ConnectedNodes.Clients)
 }
@@ -103,7 +110,7 @@ func (this *websocketsType) BroadcastAwaitAnswer(name, data []byte, consensusTyp
 
 	t := time.Now().Unix()
 	index := rand.Int()
-	gui.GUI.Log("Propagating", index, len(all), string(name), t)
+	gui.GUI.Log(gui_logger.SUBSYSTEM_NETWORK, "Propagating", index, len(all), string(name), t)
 
 	chans := make(chan *advanced_connection_types.AdvancedConnectionReply, len(all)+1)
 	for i, conn := range all {
@@ -121,7 +128,7 @@ func (this *websocketsType) BroadcastAwaitAnswer(name, data []byte, consensusTyp
 	for i := range all {
 		out[i] = <-chans
 		if out[i] != nil && out[i].Err != nil {
-			gui.GUI.Error("Error propagating", index, out[i].Err, len(all), string(name), all[i].RemoteAddr, all[i].UUID, time.Now().Unix()-t)
+			gui.GUI.Error(gui_logger.SUBSYSTEM_NETWORK, "Error propagating", index, out[i].Err, len(all), string(name), all[i].RemoteAddr, all[i].UUID, time.Now().Unix()-t)
 		}
 	}
 
@@ -170,11 +177,11 @@ func (this *websocketsType) closedConnection(conn *connection.AdvancedConnection
 	}
 }
 
-func (this *websocketsType) increaseScoreKnownNode(knownNode *known_node.KnownNodeScored, delta int32, isServer bool) bool {
-	return known_nodes.KnownNodes.IncreaseKnownNodeScore(knownNode, delta, isServer)
+func (this *websocketsType) increaseScoreKnownNode(conn *connection.AdvancedConnection, delta int32) bool {
+	return known_nodes.KnownNodes.IncreaseKnownNodeScore(conn.KnownNode, conn.NodeId, delta, conn.ConnectionType)
 }
 
-func (this *websocketsType) NewConnection(c *websock.Conn, remoteAddr string, knownNode *known_node.KnownNodeScored, connectionType bool) (*connection.AdvancedConnection, error) {
+func (this *websocketsType) NewConnection(c connection.Transport, remoteAddr string, knownNode *known_node.KnownNodeScored, connectionType bool) (*connection.AdvancedConnection, error) {
 
 	conn, err := connection.NewAdvancedConnection(c, remoteAddr, knownNode, this.apiGetMap, connectionType, this.subscriptions.newSubscriptionCn, this.subscriptions.removeSubscriptionCn, this.closedConnection, this.increaseScoreKnownNode)
 	if err != nil {
@@ -200,15 +207,21 @@ func (this *websocketsType) NewConnection(c *websock.Conn, remoteAddr string, kn
 	return conn, nil
 }
 
-func (this *websocketsType) InitializeConnection(conn *connection.AdvancedConnection) (err error) {
+//requestHandshake requests and validates the handshake of the peer, storing it in the connection
+func (this *websocketsType) requestHandshake(conn *connection.AdvancedConnection) (err error) {
 
-	defer func() {
-		if err != nil {
-			conn.Close()
-		}
-	}()
+	var publicKey []byte
+	if node_identity.NodeIdentity != nil {
+		publicKey = node_identity.NodeIdentity.PublicKey
+	}
+
+	nonce := node_identity.GenerateNonce()
+	request, err := msgpack.Marshal(&connection.ConnectionHandshakeRequest{nonce, publicKey})
+	if err != nil {
+		return
+	}
 
-	out := conn.SendAwaitAnswer([]byte("handshake"), nil, nil, 0)
+	out := conn.SendAwaitAnswer([]byte("handshake"), request, nil, 0)
 
 	if out.Err != nil {
 		return errors.New("Error sending handshake")
@@ -227,12 +240,57 @@ func (this *websocketsType) InitializeConnection(conn *connection.AdvancedConnec
 		return errors.New("Handshake is invalid")
 	}
 
-	if handshakeReceived.URL != "" && banned_nodes.BannedNodes.IsBanned(handshakeReceived.URL) {
-		return errors.New("Socket is banned")
+	protocolVersion, features, err := handshakeReceived.NegotiateProtocol()
+	if err != nil {
+		return
+	}
+
+	nodeId := ""
+	if handshakeReceived.PublicKey != nil {
+		if !node_identity.VerifyHandshake(nonce, publicKey, handshakeReceived.Nonce, handshakeReceived.PublicKey, handshakeReceived.Signature, conn.ConnectionType) {
+			return errors.New("Handshake signature is invalid")
+		}
+		nodeId = node_identity.GetNodeId(handshakeReceived.PublicKey)
+	}
+
+	if conn.KnownNode != nil {
+		if trustedPeer := node_identity.TrustedPeersURLs[conn.KnownNode.URL]; trustedPeer != nil && trustedPeer.NodeId != nodeId {
+			return errors.New("Trusted peer identity is different")
+		}
+	}
+
+	//nodes without identity can't be banned by id, so the full nodes can be required to prove one
+	if nodeId == "" && node_identity.REQUIRE_PEER_IDENTITY && handshakeReceived.Consensus == config.NODE_CONSENSUS_TYPE_FULL {
+		return errors.New("Node identity is required")
+	}
+
+	if banned_nodes.BannedNodes.IsBannedNode(handshakeReceived.URL, nodeId) {
+		return errors.New("Node is banned")
 	}
 
 	conn.Handshake = handshakeReceived
 	conn.Version = version
+	conn.NodeId = nodeId
+	conn.SetProtocol(protocolVersion, features)
+
+	return nil
+}
+
+func (this *websocketsType) InitializeConnection(conn *connection.AdvancedConnection) (err error) {
+
+	defer func() {
+		if err != nil {
+			conn.Close()
+		}
+	}()
+
+	if err = this.requestHandshake(conn); err != nil {
+		return
+	}
+
+	if conn.KnownNode != nil {
+		known_nodes.KnownNodes.SetKnownNodeId(conn.KnownNode, conn.NodeId)
+	}
 
 	if conn.IsClosed.IsSet() {
 		return
@@ -240,6 +298,7 @@ func (this *websocketsType) InitializeConnection(conn *connection.AdvancedConnec
 
 	conn.InitializedStatusMutex.Lock()
 	conn.InitializedStatus = connection.INITIALIZED_STATUS_INITIALIZED
+	close(conn.Initialized)
 	conn.InitializedStatusMutex.Unlock()
 
 	totalSockets := connected_nodes.ConnectedNodes.ConnectedHandshakeValidated(conn)
@@ -274,7 +333,10 @@ func NewWebsockets(chain *blockchain.Blockchain, mempool *mempool.Mempool, setti
 
 	recovery.SafeGo(func() {
 		for {
-			gui.GUI.InfoUpdate("sockets", strconv.FormatInt(atomic.LoadInt64(&connected_nodes.ConnectedNodes.Clients), 32)+" "+strconv.FormatInt(atomic.LoadInt64(&connected_nodes.ConnectedNodes.ServerSockets), 32))
+			clients, serverSockets := atomic.LoadInt64(&connected_nodes.ConnectedNodes.Clients), atomic.LoadInt64(&connected_nodes.ConnectedNodes.ServerSockets)
+			gui.GUI.InfoUpdate("sockets", strconv.FormatInt(clients, 32)+" "+strconv.FormatInt(serverSockets, 32))
+			metricClients.Set(float64(clients))
+			metricServerSockets.Set(float64(serverSockets))
 			time.Sleep(1 * time.Second)
 		}
 	})
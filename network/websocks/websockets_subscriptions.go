@@ -2,6 +2,7 @@ package websocks
 
 import (
 	"pandora-pay/blockchain"
+	"pandora-pay/blockchain/blockchain_types"
 	"pandora-pay/helpers"
 	"pandora-pay/helpers/msgpack"
 	"pandora-pay/helpers/recovery"
@@ -23,6 +24,11 @@ type WebsocketSubscriptions struct {
 	accountsTransactionsSubscriptions map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification
 	assetsSubscriptions               map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification
 	transactionsSubscriptions         map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification
+	blocksSubscriptions               map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification
+	reorgsSubscriptions               map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification
+	mempoolSubscriptions              map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification
+	txStatusSubscriptions             map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification
+	txStatusLast                      map[string]blockchain_types.TxStatus
 }
 
 func newWebsocketSubscriptions(chain *blockchain.Blockchain, mempool *mempool.Mempool) (subs *WebsocketSubscriptions) {
@@ -35,6 +41,11 @@ func newWebsocketSubscriptions(chain *blockchain.Blockchain, mempool *mempool.Me
 		make(map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification),
 		make(map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification),
 		make(map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification),
+		make(map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification),
+		make(map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification),
+		make(map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification),
+		make(map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification),
+		make(map[string]blockchain_types.TxStatus),
 	}
 
 	if network_config.NETWORK_ENABLE_SUBSCRIPTIONS {
@@ -103,10 +114,34 @@ func (this *WebsocketSubscriptions) getSubsMap(subscriptionType api_code_types.S
 		subsMap = this.assetsSubscriptions
 	case api_code_types.SUBSCRIPTION_TRANSACTION:
 		subsMap = this.transactionsSubscriptions
+	case api_code_types.SUBSCRIPTION_BLOCKS:
+		subsMap = this.blocksSubscriptions
+	case api_code_types.SUBSCRIPTION_REORGS:
+		subsMap = this.reorgsSubscriptions
+	case api_code_types.SUBSCRIPTION_MEMPOOL:
+		subsMap = this.mempoolSubscriptions
+	case api_code_types.SUBSCRIPTION_TX_STATUS:
+		subsMap = this.txStatusSubscriptions
 	}
 	return
 }
 
+//sendTxStatus notifies only when the status of the tx changed, unless it is forced for a new subscriber
+func (this *WebsocketSubscriptions) sendTxStatus(key string, list map[advanced_connection_types.UUID]*connection.SubscriptionNotification, force bool) {
+
+	status, err := this.chain.OpenLoadTxStatus([]byte(key))
+	if err != nil {
+		return
+	}
+
+	if last, ok := this.txStatusLast[key]; ok && last == *status && !force {
+		return
+	}
+	this.txStatusLast[key] = *status
+
+	this.send(api_code_types.SUBSCRIPTION_TX_STATUS, []byte("sub/notify"), []byte(key), list, nil, nil, status)
+}
+
 func (this *WebsocketSubscriptions) removeConnection(conn *connection.AdvancedConnection, subscriptionType api_code_types.SubscriptionType) {
 
 	subsMap := this.getSubsMap(subscriptionType)
@@ -136,6 +171,9 @@ func (this *WebsocketSubscriptions) processSubscriptions() {
 	updateMempoolTransactionsCn := this.mempool.Txs.UpdateMempoolTransactions.AddListener()
 	defer this.mempool.Txs.UpdateMempoolTransactions.RemoveChannel(updateMempoolTransactionsCn)
 
+	updateNewChainCn := this.chain.UpdateNewChainUpdate.AddListener()
+	defer this.chain.UpdateNewChainUpdate.RemoveChannel(updateNewChainCn)
+
 	var subsMap map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification
 
 	for {
@@ -153,6 +191,10 @@ func (this *WebsocketSubscriptions) processSubscriptions() {
 			}
 			subsMap[keyStr][subscription.Conn.UUID] = subscription
 
+			if subscription.Subscription.Type == api_code_types.SUBSCRIPTION_TX_STATUS {
+				this.sendTxStatus(keyStr, map[advanced_connection_types.UUID]*connection.SubscriptionNotification{subscription.Conn.UUID: subscription}, true)
+			}
+
 		case subscription := <-this.removeSubscriptionCn:
 
 			if subsMap = this.getSubsMap(subscription.Subscription.Type); subsMap == nil {
@@ -230,6 +272,45 @@ func (this *WebsocketSubscriptions) processSubscriptions() {
 				}
 			}
 
+		case chainUpdate, ok := <-updateNewChainCn:
+			if !ok {
+				return
+			}
+
+			if list := this.reorgsSubscriptions[""]; list != nil && len(chainUpdate.RemovedHeights) > 0 {
+
+				addedHeights := make([]uint64, len(chainUpdate.InsertedBlocks))
+				for i, blkComplete := range chainUpdate.InsertedBlocks {
+					addedHeights[i] = blkComplete.Block.Height
+				}
+
+				this.send(api_code_types.SUBSCRIPTION_REORGS, []byte("sub/notify"), nil, list, nil, nil, &api_types.APISubscriptionNotificationReorgExtra{
+					chainUpdate.RemovedHeights,
+					addedHeights,
+					chainUpdate.BlockHeight,
+					chainUpdate.BlockHash,
+				})
+			}
+
+			for key := range this.txStatusLast {
+				if this.txStatusSubscriptions[key] == nil {
+					delete(this.txStatusLast, key)
+				}
+			}
+			for key, list := range this.txStatusSubscriptions {
+				this.sendTxStatus(key, list, false)
+			}
+
+			if list := this.blocksSubscriptions[""]; list != nil {
+				for _, blkComplete := range chainUpdate.InsertedBlocks {
+					this.send(api_code_types.SUBSCRIPTION_BLOCKS, []byte("sub/notify"), nil, list, blkComplete.Block, nil, &api_types.APISubscriptionNotificationBlockExtra{
+						blkComplete.Block.Height,
+						blkComplete.Block.Bloom.Hash,
+						uint64(len(blkComplete.Txs)),
+					})
+				}
+			}
+
 		case txsUpdates, ok := <-updateTransactionsCn:
 			if !ok {
 				return
@@ -253,6 +334,10 @@ func (this *WebsocketSubscriptions) processSubscriptions() {
 						},
 					})
 				}
+
+				if list := this.txStatusSubscriptions[v.TxHashStr]; list != nil {
+					this.sendTxStatus(v.TxHashStr, list, false)
+				}
 			}
 
 		case txUpdate, ok := <-updateMempoolTransactionsCn:
@@ -274,6 +359,16 @@ func (this *WebsocketSubscriptions) processSubscriptions() {
 				})
 			}
 
+			if list := this.txStatusSubscriptions[txUpdate.Tx.Bloom.HashStr]; list != nil {
+				this.sendTxStatus(txUpdate.Tx.Bloom.HashStr, list, false)
+			}
+
+			if list := this.mempoolSubscriptions[""]; list != nil {
+				this.send(api_code_types.SUBSCRIPTION_MEMPOOL, []byte("sub/notify"), nil, list, txUpdate.Tx, nil, &api_types.APISubscriptionNotificationMempoolExtra{
+					txUpdate.Tx.Bloom.Hash, txUpdate.Inserted, txUpdate.IncludedInBlockchainNotification,
+				})
+			}
+
 		case conn, ok := <-this.websocketClosedCn:
 			if !ok {
 				return
@@ -283,6 +378,10 @@ func (this *WebsocketSubscriptions) processSubscriptions() {
 			this.removeConnection(conn, api_code_types.SUBSCRIPTION_ACCOUNT_TRANSACTIONS)
 			this.removeConnection(conn, api_code_types.SUBSCRIPTION_ASSET)
 			this.removeConnection(conn, api_code_types.SUBSCRIPTION_TRANSACTION)
+			this.removeConnection(conn, api_code_types.SUBSCRIPTION_BLOCKS)
+			this.removeConnection(conn, api_code_types.SUBSCRIPTION_REORGS)
+			this.removeConnection(conn, api_code_types.SUBSCRIPTION_MEMPOOL)
+			this.removeConnection(conn, api_code_types.SUBSCRIPTION_TX_STATUS)
 
 		}
 
@@ -19,6 +19,7 @@ import (
 	"pandora-pay/cryptography/crypto/balance_decryptor"
 	"pandora-pay/gui"
 	"pandora-pay/helpers/debugging_pprof"
+	"pandora-pay/helpers/recovery"
 	"pandora-pay/mempool"
 	"pandora-pay/network"
 	"pandora-pay/network/network_config"
@@ -28,6 +29,7 @@ import (
 	"pandora-pay/txs_builder"
 	"pandora-pay/txs_validator"
 	"pandora-pay/wallet"
+	"pandora-pay/webhooks"
 	"runtime"
 	"strconv"
 	"syscall"
@@ -119,12 +121,26 @@ func StartMainNow() (err error) {
 		return
 	}
 
+	if app.Wallets, err = wallet.CreateWallets(app.Wallet, app.Chain.UpdateNewChainUpdate); err != nil {
+		return
+	}
+	if err = app.Wallets.ProcessWalletsArguments(); err != nil {
+		return
+	}
+	globals.MainEvents.BroadcastEvent("main", "wallets initialized")
+
 	if app.Settings, err = settings.SettingsInit(); err != nil {
 		return
 	}
 	globals.MainEvents.BroadcastEvent("main", "settings initialized")
 
-	if err = txs_builder.TxsBuilderInit(app.Wallet, app.Mempool); err != nil {
+	if runtime.GOARCH != "wasm" {
+		if err = webhooks.InitWebhooks(app.Wallets, app.Chain.UpdateNewChainUpdate); err != nil {
+			return
+		}
+	}
+
+	if err = txs_builder.TxsBuilderInit(app.Wallets, app.Mempool); err != nil {
 		return
 	}
 	globals.MainEvents.BroadcastEvent("main", "transactions builder initialized")
@@ -148,7 +164,7 @@ func StartMainNow() (err error) {
 		}
 	}
 
-	if err = network.NewNetwork(app.Settings, app.Chain, app.Mempool, app.Wallet); err != nil {
+	if err = network.NewNetwork(app.Settings, app.Chain, app.Mempool, app.Wallets); err != nil {
 		return
 	}
 	globals.MainEvents.BroadcastEvent("main", "network initialized")
@@ -161,6 +177,14 @@ func StartMainNow() (err error) {
 	return
 }
 
+//reloadConfig reloads the non consensus settings like the auth users and the connections limits
+func reloadConfig() error {
+	if err := arguments.ReloadArguments(); err != nil {
+		return err
+	}
+	return network_config.ReloadConfig()
+}
+
 func InitMain(ready func()) {
 	var err error
 
@@ -184,6 +208,18 @@ func InitMain(ready func()) {
 		ready()
 	}
 
+	reloadSignal := make(chan os.Signal, 1)
+	notifyReloadSignal(reloadSignal)
+	recovery.SafeGo(func() {
+		for range reloadSignal {
+			if err := reloadConfig(); err != nil {
+				gui.GUI.Error("Error reloading config", err)
+			} else {
+				gui.GUI.Info("Config reloaded")
+			}
+		}
+	})
+
 	exitSignal := make(chan os.Signal, 10)
 	signal.Notify(exitSignal, syscall.SIGINT, syscall.SIGTERM)
 	<-exitSignal
@@ -0,0 +1,14 @@
+//go:build !windows && !wasm
+// +build !windows,!wasm
+
+package start
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func notifyReloadSignal(c chan os.Signal) {
+	signal.Notify(c, syscall.SIGHUP)
+}
@@ -0,0 +1,12 @@
+//go:build windows || wasm
+// +build windows wasm
+
+package start
+
+import (
+	"os"
+)
+
+//windows and wasm don't have SIGHUP
+func notifyReloadSignal(c chan os.Signal) {
+}
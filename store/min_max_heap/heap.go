@@ -181,6 +181,67 @@ func (m *Heap) GetTop() (*HeapElement, error) {
 	return m.getElement(0)
 }
 
+//Walk visits the elements in the order of the scores, without removing them, until the callback returns false
+func (m *Heap) Walk(callback func(x *HeapElement) bool) error {
+
+	type candidate struct {
+		index   uint64
+		element *HeapElement
+	}
+
+	if m.GetSize() == 0 {
+		return nil
+	}
+
+	top, err := m.getElement(0)
+	if err != nil || top == nil {
+		return err
+	}
+
+	candidates := []*candidate{{0, top}}
+	for len(candidates) > 0 {
+
+		best := 0
+		for i := 1; i < len(candidates); i++ {
+			if m.compare(candidates[i].element.Score, candidates[best].element.Score) {
+				best = i
+			}
+		}
+
+		current := candidates[best]
+		candidates = append(candidates[:best], candidates[best+1:]...)
+
+		if !callback(current.element) {
+			return nil
+		}
+
+		for _, child := range []uint64{m.leftchild(current.index), m.rightchild(current.index)} {
+			if child >= m.GetSize() {
+				continue
+			}
+			x, err := m.getElement(child)
+			if err != nil {
+				return err
+			}
+			candidates = append(candidates, &candidate{child, x})
+		}
+	}
+
+	return nil
+}
+
+//Find returns the best element accepted by the callback, without removing the rejected elements. The elements are visited in the order of the scores
+func (m *Heap) Find(accept func(x *HeapElement) bool) (found *HeapElement, err error) {
+	err = m.Walk(func(x *HeapElement) bool {
+		if accept(x) {
+			found = x
+			return false
+		}
+		return true
+	})
+	return
+}
+
 /*
 Minheap
 
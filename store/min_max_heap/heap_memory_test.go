@@ -6,6 +6,7 @@ import (
 	"math/rand"
 	"pandora-pay/cryptography"
 	"pandora-pay/helpers"
+	"sort"
 	"testing"
 	"time"
 )
@@ -159,3 +160,56 @@ func TestCreateMinHeapMemory(t *testing.T) {
 	assert.Nil(t, top)
 	assert.Nil(t, err)
 }
+
+func TestFindMaxHeapMemory(t *testing.T) {
+
+	v := []float64{6, 5, 3, 7, 2, 8}
+
+	maxHeap := NewMaxMemoryHeap()
+	for i := range v {
+		assert.Nil(t, maxHeap.Insert(v[i], []byte{byte(i)}))
+	}
+
+	visited := []float64{}
+	el, err := maxHeap.Find(func(x *HeapElement) bool {
+		visited = append(visited, x.Score)
+		return x.Score < 6
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, float64(5), el.Score)
+	assert.Equal(t, []float64{8, 7, 6, 5}, visited, "Elements are not visited in the order of the scores")
+
+	el, err = maxHeap.Find(func(x *HeapElement) bool {
+		return false
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, el)
+	assert.Equal(t, uint64(len(v)), maxHeap.GetSize())
+}
+
+func TestWalkMaxHeapMemory(t *testing.T) {
+
+	maxHeap := NewMaxMemoryHeap()
+	assert.Nil(t, maxHeap.Walk(func(x *HeapElement) bool {
+		t.Fatal("Empty heap should not be walked")
+		return false
+	}))
+
+	v := make([]float64, 100)
+	for i := range v {
+		v[i] = float64(rand.Intn(1000))
+		assert.Nil(t, maxHeap.Insert(v[i], []byte{byte(i)}))
+	}
+
+	sorted := append([]float64{}, v...)
+	sort.Sort(sort.Reverse(sort.Float64Slice(sorted)))
+
+	//the top elements are visited best first, like popping them
+	visited := []float64{}
+	assert.Nil(t, maxHeap.Walk(func(x *HeapElement) bool {
+		visited = append(visited, x.Score)
+		return len(visited) < 10
+	}))
+	assert.Equal(t, sorted[:10], visited)
+	assert.Equal(t, uint64(len(v)), maxHeap.GetSize())
+}
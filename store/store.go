@@ -16,6 +16,11 @@ func (store *Store) close() error {
 	return store.DB.Close()
 }
 
+func (store *Store) Close() error {
+	store.Opened = false
+	return store.close()
+}
+
 func createStore(name string, db store_db_interface.StoreDBInterface) (*Store, error) {
 
 	store := &Store{
@@ -32,12 +32,16 @@ func createStoreNow(name string, storeType string) (*Store, error) {
 	return createStore(name, db)
 }
 
+var allowedStores = map[string]bool{"bunt-memory": true, "memory": true, "js": true}
+
+func CreateStoreWallet(name string) (*Store, error) {
+	return createStoreNow("/wallet4_"+name, getStoreType(arguments.Arguments["--store-wallet-type"].(string), allowedStores))
+}
+
 func create_db() (err error) {
 
 	var prefix = ""
 
-	allowedStores := map[string]bool{"bunt-memory": true, "memory": true, "js": true}
-
 	if StoreBlockchain, err = createStoreNow(prefix+"/blockchain", getStoreType(arguments.Arguments["--store-chain-type"].(string), allowedStores)); err != nil {
 		return
 	}
@@ -42,12 +42,16 @@ func createStoreNow(name, storeType string) (*Store, error) {
 	return store, nil
 }
 
+var allowedStores = map[string]bool{"bolt": true, "bunt": true, "bunt-memory": true, "memory": true}
+
+func CreateStoreWallet(name string) (*Store, error) {
+	return createStoreNow("/wallet_"+name, getStoreType(arguments.Arguments["--store-wallet-type"].(string), allowedStores))
+}
+
 func create_db() (err error) {
 
 	var prefix = ""
 
-	allowedStores := map[string]bool{"bolt": true, "bunt": true, "bunt-memory": true, "memory": true}
-
 	if StoreBlockchain, err = createStoreNow(prefix+"/blockchain", getStoreType(arguments.Arguments["--store-chain-type"].(string), allowedStores)); err != nil {
 		return
 	}
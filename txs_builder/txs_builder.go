@@ -19,7 +19,7 @@ import (
 )
 
 type TxsBuilderType struct {
-	wallet  *wallet.Wallet
+	wallets *wallet.Wallets
 	mempool *mempool.Mempool
 	lock    *sync.Mutex
 }
@@ -50,13 +50,13 @@ func (builder *TxsBuilderType) convertFloatAmounts(amounts []float64, ast *asset
 	return amountsFinal, nil
 }
 
-func (builder *TxsBuilderType) getWalletAddresses(senders []string) ([]*wallet_address.WalletAddress, error) {
+func (builder *TxsBuilderType) getWalletAddresses(walletName string, senders []string) ([]*wallet_address.WalletAddress, error) {
 
 	sendersWalletAddress := make([]*wallet_address.WalletAddress, len(senders))
 	var err error
 
 	for i, senderAddress := range senders {
-		if sendersWalletAddress[i], err = builder.wallet.GetWalletAddressByEncodedAddress(senderAddress, true); err != nil {
+		if _, sendersWalletAddress[i], err = builder.wallets.GetWalletAddressByEncodedAddress(walletName, senderAddress); err != nil {
 			return nil, err
 		}
 		if sendersWalletAddress[i].PrivateKey == nil {
@@ -79,7 +79,7 @@ func (builder *TxsBuilderType) CreateSimpleTx(txData *TxBuilderCreateSimpleTx, p
 	var sendersWalletAddresses []*wallet_address.WalletAddress
 	var err error
 	if txData.Sender != "" {
-		if sendersWalletAddresses, err = builder.getWalletAddresses([]string{txData.Sender}); err != nil {
+		if sendersWalletAddresses, err = builder.getWalletAddresses(txData.Wallet, []string{txData.Sender}); err != nil {
 			return nil, err
 		}
 	}
@@ -138,10 +138,10 @@ func (builder *TxsBuilderType) CreateSimpleTx(txData *TxBuilderCreateSimpleTx, p
 	return tx, nil
 }
 
-func TxsBuilderInit(wallet *wallet.Wallet, mempool *mempool.Mempool) error {
+func TxsBuilderInit(wallets *wallet.Wallets, mempool *mempool.Mempool) error {
 
 	TxsBuilder = &TxsBuilderType{
-		wallet,
+		wallets,
 		mempool,
 		&sync.Mutex{},
 	}
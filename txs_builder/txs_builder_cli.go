@@ -19,11 +19,13 @@ import (
 	"pandora-pay/config/config_coins"
 	"pandora-pay/cryptography"
 	"pandora-pay/gui"
+	"pandora-pay/gui/gui_logger"
 	"pandora-pay/helpers"
 	"pandora-pay/helpers/files"
 	"pandora-pay/store"
 	"pandora-pay/store/store_db/store_db_interface"
 	"pandora-pay/txs_builder/wizard"
+	"pandora-pay/wallet/wallet_contact"
 )
 
 func (builder *TxsBuilderType) showWarningIfNotSyncCLI() {
@@ -195,18 +197,42 @@ func (builder *TxsBuilderType) initCLI() {
 			Payloads: []*TxBuilderCreateZetherTxPayload{{}},
 		}
 
-		if _, txData.Payloads[0].Sender, _, err = builder.wallet.CliSelectAddress("Select Address to Transfer", ctx); err != nil {
+		wallet := builder.wallets.GetSelectedWallet()
+		txData.Wallet = wallet.Name
+
+		if _, txData.Payloads[0].Sender, _, err = wallet.CliSelectAddress("Select Address to Transfer", ctx); err != nil {
 			return
 		}
 
-		txData.Payloads[0].Asset = builder.readAsset("Asset. Leave empty for Native Asset", true)
-
-		if _, txData.Payloads[0].Recipient, txData.Payloads[0].Amount, err = builder.readAddressOptional("Recipient Address", txData.Payloads[0].Asset, false); err != nil {
+		var contact *wallet_contact.WalletContact
+		if contact, err = wallet.CliSelectContact("Select Contact as Recipient", ctx); err != nil {
 			return
 		}
 
+		if contact != nil {
+			if len(contact.Asset) > 0 && gui.GUI.OutputReadBool("Use the Contact's default Asset? y/n. Leave empty for yes", true, true) {
+				txData.Payloads[0].Asset = contact.GetAsset()
+			} else {
+				txData.Payloads[0].Asset = builder.readAsset("Asset. Leave empty for Native Asset", true)
+			}
+			txData.Payloads[0].Recipient = contact.AddressEncoded
+			if txData.Payloads[0].Amount, err = builder.readAmount(txData.Payloads[0].Asset, "Recipient Address Amount"); err != nil {
+				return
+			}
+		} else {
+			txData.Payloads[0].Asset = builder.readAsset("Asset. Leave empty for Native Asset", true)
+			if _, txData.Payloads[0].Recipient, txData.Payloads[0].Amount, err = builder.readAddressOptional("Recipient Address", txData.Payloads[0].Asset, false); err != nil {
+				return
+			}
+		}
+
 		builder.readZetherRingConfiguration(txData.Payloads[0])
-		txData.Payloads[0].Data = builder.readData()
+		if contact != nil && len(contact.PaymentID) > 0 {
+			gui.GUI.OutputWrite("The Contact's Payment ID will be sent as encrypted message")
+			txData.Payloads[0].Data = &wizard.WizardTransactionData{Data: contact.PaymentID, Encrypt: true}
+		} else {
+			txData.Payloads[0].Data = builder.readData()
+		}
 		txData.Payloads[0].Fee = builder.readZetherFee(txData.Payloads[0].Asset)
 		propagate := gui.GUI.OutputReadBool("Propagate? y/n. Leave empty for yes", true, true)
 
@@ -221,6 +247,80 @@ func (builder *TxsBuilderType) initCLI() {
 		return
 	}
 
+	cliPrivateSweep := func(cmd string, ctx context.Context) (err error) {
+		builder.showWarningIfNotSyncCLI()
+
+		wallet := builder.wallets.GetSelectedWallet()
+		data := &TxBuilderSweepData{Wallet: wallet.Name}
+
+		var contact *wallet_contact.WalletContact
+		if contact, err = wallet.CliSelectContact("Select Contact as Destination", ctx); err != nil {
+			return
+		}
+
+		if contact != nil {
+			data.Destination = contact.AddressEncoded
+		} else {
+			var destination *addresses.Address
+			if destination, err = builder.readAddress("Destination Address", false); err != nil {
+				return
+			}
+			data.Destination = destination.EncodeAddr()
+		}
+
+		for {
+			assetId := gui.GUI.OutputReadBytes("Asset to sweep. Leave empty to finish the list. An empty list sweeps all assets", func(input []byte) bool {
+				return len(input) == 0 || len(input) == config_coins.ASSET_LENGTH
+			})
+			if len(assetId) == 0 {
+				break
+			}
+			data.Assets = append(data.Assets, assetId)
+		}
+
+		payload := &TxBuilderCreateZetherTxPayload{}
+		builder.readZetherRingConfiguration(payload)
+		data.RingSize = payload.RingSize
+
+		statusCallback := func(status string) {
+			gui.GUI.OutputWrite(status)
+		}
+
+		sweepTxs, err := builder.SweepZether(data, true, false, false, false, ctx, statusCallback)
+		if err != nil {
+			return
+		}
+
+		var totalPayloads int
+		for i, sweepTx := range sweepTxs {
+			gui.GUI.OutputWrite(fmt.Sprintf("Transaction %d", i))
+			for _, payload := range sweepTx.Payloads {
+				gui.GUI.OutputWrite(fmt.Sprintf("   %s Asset %s Fee %d Amount %d", payload.Sender, base64.StdEncoding.EncodeToString(payload.Asset), payload.Fee, payload.Amount))
+			}
+			totalPayloads += len(sweepTx.Payloads)
+		}
+		if totalPayloads == 0 {
+			return errors.New("There are no funds to be swept")
+		}
+
+		if !gui.GUI.OutputReadBool(fmt.Sprintf("Sweep %d balances in %d transactions to %s? y/n", totalPayloads, len(sweepTxs), data.Destination), false, false) {
+			return
+		}
+
+		propagate := gui.GUI.OutputReadBool("Propagate? y/n. Leave empty for yes", true, true)
+
+		if sweepTxs, err = builder.SweepZether(data, false, propagate, true, true, ctx, statusCallback); err != nil {
+			return
+		}
+
+		for _, sweepTx := range sweepTxs {
+			if sweepTx.Tx != nil {
+				gui.GUI.OutputWrite(fmt.Sprintf("Tx created: %s %s", base64.StdEncoding.EncodeToString(sweepTx.Tx.Bloom.Hash), cmd))
+			}
+		}
+		return
+	}
+
 	cliPrivateAssetCreate := func(cmd string, ctx context.Context) (err error) {
 		builder.showWarningIfNotSyncCLI()
 
@@ -232,7 +332,10 @@ func (builder *TxsBuilderType) initCLI() {
 			}},
 		}
 
-		if _, txData.Payloads[0].Sender, _, err = builder.wallet.CliSelectAddress("Select Address which will create the asset", ctx); err != nil {
+		wallet := builder.wallets.GetSelectedWallet()
+		txData.Wallet = wallet.Name
+
+		if _, txData.Payloads[0].Sender, _, err = wallet.CliSelectAddress("Select Address which will create the asset", ctx); err != nil {
 			return
 		}
 
@@ -310,7 +413,10 @@ func (builder *TxsBuilderType) initCLI() {
 			}},
 		}
 
-		if _, txData.Payloads[0].Sender, _, err = builder.wallet.CliSelectAddress("Select Address which will increase the supply of asset", ctx); err != nil {
+		wallet := builder.wallets.GetSelectedWallet()
+		txData.Wallet = wallet.Name
+
+		if _, txData.Payloads[0].Sender, _, err = wallet.CliSelectAddress("Select Address which will increase the supply of asset", ctx); err != nil {
 			return
 		}
 
@@ -358,7 +464,10 @@ func (builder *TxsBuilderType) initCLI() {
 			}},
 		}
 
-		if _, txData.Payloads[0].Sender, _, err = builder.wallet.CliSelectAddress("Select Address which will fund a plain account", ctx); err != nil {
+		wallet := builder.wallets.GetSelectedWallet()
+		txData.Wallet = wallet.Name
+
+		if _, txData.Payloads[0].Sender, _, err = wallet.CliSelectAddress("Select Address which will fund a plain account", ctx); err != nil {
 			return
 		}
 
@@ -401,7 +510,10 @@ func (builder *TxsBuilderType) initCLI() {
 			}, {}},
 		}
 
-		if _, txData.Payloads[0].Sender, _, err = builder.wallet.CliSelectAddress("Select Address to Transfer", ctx); err != nil {
+		wallet := builder.wallets.GetSelectedWallet()
+		txData.Wallet = wallet.Name
+
+		if _, txData.Payloads[0].Sender, _, err = wallet.CliSelectAddress("Select Address to Transfer", ctx); err != nil {
 			return
 		}
 		txData.Payloads[1].Sender = txData.Payloads[0].Sender
@@ -484,7 +596,10 @@ func (builder *TxsBuilderType) initCLI() {
 			FeeVersion: true,
 		}
 
-		if _, txData.Sender, _, err = builder.wallet.CliSelectAddress("Select Address to Publicly Update Asset Fee Liquidity", ctx); err != nil {
+		wallet := builder.wallets.GetSelectedWallet()
+		txData.Wallet = wallet.Name
+
+		if _, txData.Sender, _, err = wallet.CliSelectAddress("Select Address to Publicly Update Asset Fee Liquidity", ctx); err != nil {
 			return
 		}
 
@@ -574,7 +689,7 @@ func (builder *TxsBuilderType) initCLI() {
 			}
 
 			if !extra.VerifySignature() {
-				gui.GUI.Error("provided resolution signature is not valid")
+				gui.GUI.Error(gui_logger.SUBSYSTEM_WALLET, "provided resolution signature is not valid")
 				break
 			}
 
@@ -601,6 +716,7 @@ func (builder *TxsBuilderType) initCLI() {
 	}
 
 	gui.GUI.CommandDefineCallback("Private Transfer", cliPrivateTransfer, true)
+	gui.GUI.CommandDefineCallback("Private Sweep", cliPrivateSweep, true)
 	gui.GUI.CommandDefineCallback("Private Asset Create", cliPrivateAssetCreate, true)
 	gui.GUI.CommandDefineCallback("Private Asset Supply Increase", cliPrivateAssetSupplyIncrease, true)
 	gui.GUI.CommandDefineCallback("Private Plain Account Fund", cliPrivatePlainAccountFund, true)
@@ -0,0 +1,371 @@
+package txs_builder
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/blockchain/data_storage/accounts"
+	"pandora-pay/blockchain/data_storage/accounts/account"
+	"pandora-pay/blockchain/data_storage/plain_accounts/plain_account/asset_fee_liquidity"
+	"pandora-pay/blockchain/data_storage/registrations/registration"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/cryptography/crypto"
+	"pandora-pay/helpers"
+	"pandora-pay/network/websocks/connection/advanced_connection_types"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/txs_builder/txs_builder_zether_helper"
+	"pandora-pay/txs_builder/wizard"
+	"pandora-pay/txs_validator"
+	"pandora-pay/wallet"
+	"pandora-pay/wallet/wallet_address"
+)
+
+const SWEEP_MAX_PAYLOADS_PER_TX = 8
+
+//fees are estimated by building the transaction once. The ring members of the final transaction are different, hence a small margin
+const SWEEP_FEE_MARGIN_PERCENT = 10
+
+type TxBuilderSweepData struct {
+	Wallet           string           `json:"wallet,omitempty" msgpack:"wallet,omitempty"`
+	Destination      string           `json:"destination" msgpack:"destination"`
+	Assets           []helpers.Base64 `json:"assets,omitempty" msgpack:"assets,omitempty"` //empty means all assets
+	RingSize         int              `json:"ringSize" msgpack:"ringSize"`                 //-1 means random
+	MaxPayloadsPerTx int              `json:"maxPayloadsPerTx" msgpack:"maxPayloadsPerTx"` //0 means SWEEP_MAX_PAYLOADS_PER_TX
+}
+
+type TxBuilderSweepPayload struct {
+	Sender  string `json:"sender" msgpack:"sender"`
+	Asset   []byte `json:"asset" msgpack:"asset"`
+	Balance uint64 `json:"balance" msgpack:"balance"`
+	Fee     uint64 `json:"fee" msgpack:"fee"`
+	Amount  uint64 `json:"amount" msgpack:"amount"`
+}
+
+type TxBuilderSweepTx struct {
+	Payloads []*TxBuilderSweepPayload `json:"payloads" msgpack:"payloads"`
+	Tx       *transaction.Transaction `json:"tx,omitempty" msgpack:"tx,omitempty"`
+}
+
+type sweepSource struct {
+	addr    *wallet_address.WalletAddress
+	asset   []byte
+	balance uint64
+}
+
+func (builder *TxsBuilderType) getSweepSources(walletUsed *wallet.Wallet, destination *addresses.Address, assetsFilter []helpers.Base64, pendingTxs []*transaction.Transaction, ctx context.Context, statusCallback func(string)) ([]*sweepSource, error) {
+
+	walletAddresses := make([]*wallet_address.WalletAddress, 0)
+	for i := 0; i < walletUsed.GetAddressesCount(); i++ {
+		addr, err := walletUsed.GetWalletAddress(i, true)
+		if err != nil {
+			return nil, err
+		}
+		if addr.PrivateKey == nil || bytes.Equal(addr.PublicKey, destination.PublicKey) {
+			continue
+		}
+		walletAddresses = append(walletAddresses, addr)
+	}
+
+	assetAllowed := func(assetId []byte) bool {
+		if len(assetsFilter) == 0 {
+			return true
+		}
+		for _, it := range assetsFilter {
+			if bytes.Equal(it, assetId) {
+				return true
+			}
+		}
+		return false
+	}
+
+	type encryptedSource struct {
+		addr    *wallet_address.WalletAddress
+		asset   []byte
+		balance []byte
+	}
+	encryptedSources := make([]*encryptedSource, 0)
+
+	if err := store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		dataStorage := data_storage.NewDataStorage(reader)
+
+		for _, addr := range walletAddresses {
+
+			var assetsList [][]byte
+			if assetsList, err = dataStorage.AccsCollection.GetAccountAssets(addr.PublicKey); err != nil {
+				return
+			}
+
+			var reg *registration.Registration
+			if reg, err = dataStorage.Regs.Get(string(addr.PublicKey)); err != nil {
+				return
+			}
+
+			for _, assetId := range assetsList {
+
+				if !assetAllowed(assetId) {
+					continue
+				}
+
+				if !bytes.Equal(assetId, config_coins.NATIVE_ASSET_FULL) {
+					var assetFeeLiquidity *asset_fee_liquidity.AssetFeeLiquidity
+					if assetFeeLiquidity, err = dataStorage.GetAssetFeeLiquidityTop(assetId); err != nil {
+						return
+					}
+					if assetFeeLiquidity == nil {
+						statusCallback(fmt.Sprintf("Asset %s skipped for %s as there is no Asset Fee Liquidity", base64.StdEncoding.EncodeToString(assetId), addr.AddressEncoded))
+						continue
+					}
+				}
+
+				var accs *accounts.Accounts
+				var acc *account.Account
+				if accs, err = dataStorage.AccsCollection.GetMap(assetId); err != nil {
+					return
+				}
+				if acc, err = accs.Get(string(addr.PublicKey)); err != nil {
+					return
+				}
+				if acc == nil {
+					continue
+				}
+
+				hasRollover := reg != nil && reg.Staked && bytes.Equal(assetId, config_coins.NATIVE_ASSET_FULL)
+
+				var balance *crypto.ElGamal
+				if balance, err = wizard.GetZetherBalance(addr.PublicKey, acc.Balance.Amount, assetId, hasRollover, pendingTxs); err != nil {
+					return
+				}
+
+				encryptedSources = append(encryptedSources, &encryptedSource{addr, assetId, balance.Serialize()})
+			}
+		}
+
+		return
+	}); err != nil {
+		return nil, err
+	}
+
+	sources := make([]*sweepSource, 0, len(encryptedSources))
+	for _, it := range encryptedSources {
+		balance, err := walletUsed.DecryptBalance(it.addr, it.balance, it.asset, false, 0, true, ctx, statusCallback)
+		if err != nil {
+			return nil, err
+		}
+		if balance == 0 {
+			continue
+		}
+		sources = append(sources, &sweepSource{it.addr, it.asset, balance})
+	}
+
+	statusCallback("Balances decoded")
+	return sources, nil
+}
+
+func (builder *TxsBuilderType) createSweepTxData(walletName, destination string, ringSize int, sources []*sweepSource, fees []uint64) *TxBuilderCreateZetherTxData {
+
+	txData := &TxBuilderCreateZetherTxData{
+		Wallet:   walletName,
+		Payloads: make([]*TxBuilderCreateZetherTxPayload, len(sources)),
+	}
+
+	for t, source := range sources {
+
+		fee := &wizard.WizardZetherTransactionFee{
+			WizardTransactionFee: &wizard.WizardTransactionFee{PerByteAuto: true},
+			Auto:                 true,
+		}
+
+		var amount uint64
+		if fees != nil {
+			fee.WizardTransactionFee = &wizard.WizardTransactionFee{Fixed: fees[t]}
+			amount = source.balance - fees[t]
+		}
+
+		txData.Payloads[t] = &TxBuilderCreateZetherTxPayload{
+			TxsBuilderZetherTxPayloadBase: txs_builder_zether_helper.TxsBuilderZetherTxPayloadBase{
+				Sender:    source.addr.AddressEncoded,
+				Recipient: destination,
+				RingSize:  ringSize,
+			},
+			Asset:            source.asset,
+			Amount:           amount,
+			DecryptedBalance: source.balance,
+			RingConfiguration: &ZetherRingConfiguration{
+				SenderRingType:    &ZetherSenderRingType{},
+				RecipientRingType: &ZetherRecipientRingType{NewAccounts: -1},
+			},
+			Data: &wizard.WizardTransactionData{},
+			Fee:  fee,
+		}
+	}
+
+	return txData
+}
+
+//estimateSweepFees builds the transaction once using automatic fees and returns the fees (including the margin) of every payload.
+//Sources that can not pay their own fee are removed
+func (builder *TxsBuilderType) estimateSweepFees(walletName, destination string, ringSize int, sources []*sweepSource, pendingTxs []*transaction.Transaction, ctx context.Context, statusCallback func(string)) ([]*sweepSource, []uint64, error) {
+
+	for len(sources) > 0 {
+
+		txData := builder.createSweepTxData(walletName, destination, ringSize, sources, nil)
+
+		builder.lock.Lock()
+		tx, _, err := builder.buildZetherTx(txData, pendingTxs, ctx, statusCallback)
+		builder.lock.Unlock()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		fees := make([]uint64, len(sources))
+		remaining := make([]*sweepSource, 0, len(sources))
+		for t, payload := range tx.TransactionBaseInterface.(*transaction_zether.TransactionZether).Payloads {
+			fees[t] = payload.Statement.Fee + payload.Statement.Fee*SWEEP_FEE_MARGIN_PERCENT/100 + 1
+			if sources[t].balance > fees[t] {
+				remaining = append(remaining, sources[t])
+			} else {
+				statusCallback(fmt.Sprintf("%s skipped as the balance can not cover the fee", sources[t].addr.AddressEncoded))
+			}
+		}
+
+		if len(remaining) == len(sources) {
+			return sources, fees, nil
+		}
+		sources = remaining
+	}
+
+	return nil, nil, nil
+}
+
+//SweepZether moves all the balances of the wallet addresses to a single destination.
+//Every (address, asset) having a balance becomes one zether payload and the payloads are packed into multi-payload transactions.
+//In case of dryRun, the transactions are only estimated and the fees are returned without creating the final transactions
+func (builder *TxsBuilderType) SweepZether(data *TxBuilderSweepData, dryRun, propagateTx, awaitAnswer, awaitBroadcast bool, ctx context.Context, statusCallback func(string)) ([]*TxBuilderSweepTx, error) {
+
+	if data == nil {
+		return nil, errors.New("Data is missing")
+	}
+
+	destination, err := addresses.DecodeAddr(data.Destination)
+	if err != nil {
+		return nil, err
+	}
+
+	if data.RingSize == 0 {
+		data.RingSize = -1
+	}
+
+	maxPayloads := data.MaxPayloadsPerTx
+	if maxPayloads <= 0 || maxPayloads > SWEEP_MAX_PAYLOADS_PER_TX {
+		maxPayloads = SWEEP_MAX_PAYLOADS_PER_TX
+	}
+
+	walletUsed, err := builder.wallets.GetWallet(data.Wallet)
+	if err != nil {
+		return nil, err
+	}
+
+	pendingTxs := builder.mempool.GetPendingTxs()
+
+	sources, err := builder.getSweepSources(walletUsed, destination, data.Assets, pendingTxs, ctx, statusCallback)
+	if err != nil {
+		return nil, err
+	}
+	if len(sources) == 0 {
+		return nil, errors.New("There are no funds to be swept")
+	}
+
+	if data.RingSize == -1 {
+		//all payloads must use the same ring size as they share the recipient ring
+		payload := &TxBuilderCreateZetherTxPayload{
+			RingConfiguration: &ZetherRingConfiguration{&ZetherSenderRingType{}, &ZetherRecipientRingType{}},
+		}
+		payload.RingSize = -1
+		if err = builder.presetZetherRing(payload); err != nil {
+			return nil, err
+		}
+		data.RingSize = payload.RingSize
+	}
+
+	//a sender can be used only once per transaction, otherwise both rings would be identical
+	chunks := make([][]*sweepSource, 0)
+	for _, source := range sources {
+		found := false
+		for i, chunk := range chunks {
+			if len(chunk) >= maxPayloads {
+				continue
+			}
+			duplicate := false
+			for _, it := range chunk {
+				if it.addr.AddressEncoded == source.addr.AddressEncoded {
+					duplicate = true
+					break
+				}
+			}
+			if !duplicate {
+				chunks[i] = append(chunk, source)
+				found = true
+				break
+			}
+		}
+		if !found {
+			chunks = append(chunks, []*sweepSource{source})
+		}
+	}
+
+	out := make([]*TxBuilderSweepTx, 0)
+
+	for _, chunk := range chunks {
+
+		var fees []uint64
+		if chunk, fees, err = builder.estimateSweepFees(data.Wallet, data.Destination, data.RingSize, chunk, pendingTxs, ctx, statusCallback); err != nil {
+			return nil, err
+		}
+		if len(chunk) == 0 {
+			continue
+		}
+
+		sweepTx := &TxBuilderSweepTx{
+			Payloads: make([]*TxBuilderSweepPayload, len(chunk)),
+		}
+		for t, source := range chunk {
+			sweepTx.Payloads[t] = &TxBuilderSweepPayload{source.addr.AddressEncoded, source.asset, source.balance, fees[t], source.balance - fees[t]}
+		}
+		out = append(out, sweepTx)
+
+		if dryRun {
+			continue
+		}
+
+		txData := builder.createSweepTxData(data.Wallet, data.Destination, data.RingSize, chunk, fees)
+
+		builder.lock.Lock()
+		tx, chainHeight, err := builder.buildZetherTx(txData, pendingTxs, ctx, statusCallback)
+		if err == nil {
+			err = txs_validator.TxsValidator.MarkAsValidatedTx(tx)
+		}
+		builder.lock.Unlock()
+		if err != nil {
+			return out, err
+		}
+
+		if propagateTx {
+			if err = builder.mempool.AddTxToMempool(tx, chainHeight, true, awaitAnswer, awaitBroadcast, advanced_connection_types.UUID_ALL, ctx); err != nil {
+				return out, err
+			}
+		}
+
+		sweepTx.Tx = tx
+		pendingTxs = append(pendingTxs, tx)
+	}
+
+	return out, nil
+}
@@ -3,6 +3,7 @@ package txs_builder
 import "pandora-pay/txs_builder/wizard"
 
 type TxBuilderCreateSimpleTx struct {
+	Wallet     string                        `json:"wallet,omitempty" msgpack:"wallet,omitempty"`
 	Sender     string                        `json:"sender" msgpack:"sender"`
 	Nonce      uint64                        `json:"nonce" msgpack:"nonce"`
 	Data       *wizard.WizardTransactionData `json:"data" msgpack:"data"`
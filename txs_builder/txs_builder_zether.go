@@ -22,12 +22,14 @@ import (
 	"pandora-pay/cryptography/bn256"
 	"pandora-pay/cryptography/crypto"
 	"pandora-pay/gui"
+	"pandora-pay/gui/gui_logger"
 	"pandora-pay/network/websocks/connection/advanced_connection_types"
 	"pandora-pay/store"
 	"pandora-pay/store/store_db/store_db_interface"
 	"pandora-pay/txs_builder/txs_builder_zether_helper"
 	"pandora-pay/txs_builder/wizard"
 	"pandora-pay/txs_validator"
+	"pandora-pay/wallet"
 	"pandora-pay/wallet/wallet_address"
 )
 
@@ -272,6 +274,7 @@ func (builder *TxsBuilderType) prebuild(txData *TxBuilderCreateZetherTxData, pen
 
 	sendersPrivateKeys := make([]*addresses.PrivateKey, len(txData.Payloads))
 	sendersWalletAddresses := make([]*wallet_address.WalletAddress, len(txData.Payloads))
+	sendersWallets := make([]*wallet.Wallet, len(txData.Payloads))
 	sendAssets := make([][]byte, len(txData.Payloads))
 
 	hasRollovers := make(map[string]bool)
@@ -303,7 +306,7 @@ func (builder *TxsBuilderType) prebuild(txData *TxBuilderCreateZetherTxData, pen
 
 		} else {
 
-			addr, err := builder.wallet.GetWalletAddressByEncodedAddress(payload.Sender, true)
+			senderWallet, addr, err := builder.wallets.GetWalletAddressByEncodedAddress(txData.Wallet, payload.Sender)
 			if err != nil {
 				return nil, nil, nil, nil, nil, nil, 0, nil, err
 			}
@@ -316,6 +319,7 @@ func (builder *TxsBuilderType) prebuild(txData *TxBuilderCreateZetherTxData, pen
 				return nil, nil, nil, nil, nil, nil, 0, nil, err
 			}
 			sendersWalletAddresses[t] = addr
+			sendersWallets[t] = senderWallet
 
 		}
 
@@ -577,13 +581,13 @@ func (builder *TxsBuilderType) prebuild(txData *TxBuilderCreateZetherTxData, pen
 		} else if sendersEncryptedBalances[t] != nil {
 
 			if txData.Payloads[t].DecryptedBalance > 0 { // in case it was specified to avoid getting stuck
-				decrypted, err := builder.wallet.DecryptBalance(sendersWalletAddresses[t], sendersEncryptedBalances[t], transfers[t].Asset, true, txData.Payloads[t].DecryptedBalance, true, ctx, statusCallback)
+				decrypted, err := sendersWallets[t].DecryptBalance(sendersWalletAddresses[t], sendersEncryptedBalances[t], transfers[t].Asset, true, txData.Payloads[t].DecryptedBalance, true, ctx, statusCallback)
 				if err != nil {
 					return nil, nil, nil, nil, nil, nil, 0, nil, err
 				}
 				transfers[t].SenderDecryptedBalance = decrypted
 			} else {
-				decrypted, err := builder.wallet.DecryptBalance(sendersWalletAddresses[t], sendersEncryptedBalances[t], transfers[t].Asset, false, 0, true, ctx, statusCallback)
+				decrypted, err := sendersWallets[t].DecryptBalance(sendersWalletAddresses[t], sendersEncryptedBalances[t], transfers[t].Asset, false, 0, true, ctx, statusCallback)
 				if err != nil {
 					return nil, nil, nil, nil, nil, nil, 0, nil, err
 				}
@@ -609,18 +613,12 @@ func (builder *TxsBuilderType) prebuild(txData *TxBuilderCreateZetherTxData, pen
 	return transfers, emap, hasRollovers, ringsSenderMembers, ringsRecipientMembers, publicKeyIndexes, chainHeight, chainKernelHash, nil
 }
 
-func (builder *TxsBuilderType) CreateZetherTx(txData *TxBuilderCreateZetherTxData, pendingTxs []*transaction.Transaction, propagateTx, awaitAnswer, awaitBroadcast bool, validateTx bool, ctx context.Context, statusCallback func(string)) (*transaction.Transaction, error) {
-
-	if pendingTxs == nil {
-		pendingTxs = builder.mempool.Txs.GetTxsOnlyList()
-	}
-
-	builder.lock.Lock()
-	defer builder.lock.Unlock()
+//must be locked before
+func (builder *TxsBuilderType) buildZetherTx(txData *TxBuilderCreateZetherTxData, pendingTxs []*transaction.Transaction, ctx context.Context, statusCallback func(string)) (*transaction.Transaction, uint64, error) {
 
 	transfers, emap, hasRollovers, ringsSenderMembers, ringsRecipientMembers, publicKeyIndexes, chainHeight, chainKernelHash, err := builder.prebuild(txData, pendingTxs, 0, nil, ctx, statusCallback)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	feesFinal := make([]*wizard.WizardTransactionFee, len(txData.Payloads))
@@ -628,8 +626,25 @@ func (builder *TxsBuilderType) CreateZetherTx(txData *TxBuilderCreateZetherTxDat
 		feesFinal[t] = payload.Fee.WizardTransactionFee
 	}
 
-	var tx *transaction.Transaction
-	if tx, err = wizard.CreateZetherTx(transfers, emap, hasRollovers, ringsSenderMembers, ringsRecipientMembers, chainHeight-1, chainKernelHash, publicKeyIndexes, feesFinal, ctx, statusCallback); err != nil {
+	tx, err := wizard.CreateZetherTx(transfers, emap, hasRollovers, ringsSenderMembers, ringsRecipientMembers, chainHeight-1, chainKernelHash, publicKeyIndexes, feesFinal, ctx, statusCallback)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return tx, chainHeight, nil
+}
+
+func (builder *TxsBuilderType) CreateZetherTx(txData *TxBuilderCreateZetherTxData, pendingTxs []*transaction.Transaction, propagateTx, awaitAnswer, awaitBroadcast bool, validateTx bool, ctx context.Context, statusCallback func(string)) (*transaction.Transaction, error) {
+
+	if pendingTxs == nil {
+		pendingTxs = builder.mempool.GetPendingTxs()
+	}
+
+	builder.lock.Lock()
+	defer builder.lock.Unlock()
+
+	tx, chainHeight, err := builder.buildZetherTx(txData, pendingTxs, ctx, statusCallback)
+	if err != nil {
 		return nil, err
 	}
 
@@ -652,7 +667,7 @@ func (builder *TxsBuilderType) CreateForgingTransactions(blkComplete *block_comp
 		pendingTxs = builder.mempool.Txs.GetTxsOnlyList()
 	}
 
-	gui.GUI.Info("CreateForgingTransactions 1")
+	gui.GUI.Info(gui_logger.SUBSYSTEM_WALLET, "CreateForgingTransactions 1")
 	forger, err := addresses.CreateAddr(forgerPublicKey, false, nil, nil, nil, 0, nil)
 	if err != nil {
 		return nil, err
@@ -714,7 +729,7 @@ func (builder *TxsBuilderType) CreateForgingTransactions(blkComplete *block_comp
 		return nil, err
 	}
 
-	gui.GUI.Info("CreateForgingTransactions 2")
+	gui.GUI.Info(gui_logger.SUBSYSTEM_WALLET, "CreateForgingTransactions 2")
 
 	feesFinal := make([]*wizard.WizardTransactionFee, len(txData.Payloads))
 	for t, payload := range txData.Payloads {
@@ -726,7 +741,7 @@ func (builder *TxsBuilderType) CreateForgingTransactions(blkComplete *block_comp
 		return nil, err
 	}
 
-	gui.GUI.Info("CreateForgingTransactions 3")
+	gui.GUI.Info(gui_logger.SUBSYSTEM_WALLET, "CreateForgingTransactions 3")
 
 	if err = txs_validator.TxsValidator.MarkAsValidatedTx(tx); err != nil {
 		return nil, err
@@ -736,7 +751,7 @@ func (builder *TxsBuilderType) CreateForgingTransactions(blkComplete *block_comp
 	//	return nil, err
 	//}
 
-	gui.GUI.Info("CreateForgingTransactions 4")
+	gui.GUI.Info(gui_logger.SUBSYSTEM_WALLET, "CreateForgingTransactions 4")
 
 	return tx, nil
 }
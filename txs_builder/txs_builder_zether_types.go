@@ -37,5 +37,6 @@ type TxBuilderCreateZetherTxPayload struct {
 }
 
 type TxBuilderCreateZetherTxData struct {
+	Wallet   string                            `json:"wallet,omitempty" msgpack:"wallet,omitempty"`
 	Payloads []*TxBuilderCreateZetherTxPayload `json:"payloads" msgpack:"payloads"`
 }
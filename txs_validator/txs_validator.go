@@ -4,11 +4,20 @@ import (
 	"pandora-pay/blockchain/transactions/transaction"
 	"pandora-pay/config"
 	"pandora-pay/gui"
+	"pandora-pay/gui/gui_logger"
 	"pandora-pay/helpers/generics"
+	"pandora-pay/helpers/metrics"
 	"sync/atomic"
 	"time"
 )
 
+var (
+	metricQueue          = metrics.NewGauge("pandora_txs_validator_queue", "Transactions waiting to be verified by txs validator")
+	metricVerified       = metrics.NewCounter("pandora_txs_validator_verified_total", "Transactions verified by txs validator")
+	metricVerifyFailed   = metrics.NewCounter("pandora_txs_validator_failed_total", "Transactions that failed the verification")
+	metricVerifyDuration = metrics.NewHistogram("pandora_txs_validator_duration_seconds", "Time spent verifying a transaction", metrics.DURATION_BUCKETS)
+)
+
 type TxsValidatorType struct {
 	all                 *generics.Map[string, *txValidatedWork]
 	workers             []*TxsValidatorWorker
@@ -35,7 +44,7 @@ func (validator *TxsValidatorType) MarkAsValidatedTx(tx *transaction.Transaction
 
 		if atomic.LoadInt32(&foundWork.status) == TX_VALIDATED_PROCCESSED {
 			if foundWork.result != nil {
-				gui.GUI.Error("Strange Error. FoundWork.result is false")
+				gui.GUI.Error(gui_logger.SUBSYSTEM_CONSENSUS, "Strange Error. FoundWork.result is false")
 				return foundWork.result
 			}
 			tx.TransactionBaseInterface.SetBloomExtra(foundWork.bloomExtra)
@@ -47,12 +56,18 @@ func (validator *TxsValidatorType) MarkAsValidatedTx(tx *transaction.Transaction
 
 }
 
+//blocking until a worker takes the work
+func (validator *TxsValidatorType) queueWork(work *txValidatedWork) {
+	metricQueue.Add(1)
+	validator.newValidationWorkCn <- work
+}
+
 //blocking
 func (validator *TxsValidatorType) ValidateTx(tx *transaction.Transaction) error {
 
 	foundWork, loaded := validator.all.LoadOrStore(tx.Bloom.HashStr, &txValidatedWork{make(chan struct{}), TX_VALIDATED_INIT, tx, 0, nil, nil})
 	if !loaded {
-		validator.newValidationWorkCn <- foundWork
+		validator.queueWork(foundWork)
 	}
 
 	<-foundWork.wait
@@ -71,7 +86,7 @@ func (validator *TxsValidatorType) ValidateTxs(txs []*transaction.Transaction) e
 	for i, tx := range txs {
 		foundWork, loaded := validator.all.LoadOrStore(tx.Bloom.HashStr, &txValidatedWork{make(chan struct{}), TX_VALIDATED_INIT, tx, 0, nil, nil})
 		if !loaded {
-			validator.newValidationWorkCn <- foundWork
+			validator.queueWork(foundWork)
 		}
 		outputs[i] = foundWork
 	}
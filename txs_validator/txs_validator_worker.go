@@ -58,7 +58,9 @@ func (worker *TxsValidatorWorker) run() {
 
 	for {
 		foundWork, _ := <-worker.newValidationWorkCn
+		metricQueue.Add(-1)
 
+		start := time.Now()
 		if err := foundWork.tx.BloomAll(); err != nil {
 			foundWork.result = err
 		} else {
@@ -68,6 +70,12 @@ func (worker *TxsValidatorWorker) run() {
 			}
 		}
 
+		metricVerifyDuration.ObserveDuration(start)
+		metricVerified.Inc()
+		if foundWork.result != nil {
+			metricVerifyFailed.Inc()
+		}
+
 		foundWork.tx = nil
 		foundWork.time = time.Now().Add(EXPIRE_TIME_MS).Unix()
 		atomic.StoreInt32(&foundWork.status, TX_VALIDATED_PROCCESSED)
@@ -1,14 +1,19 @@
 package wallet
 
 import (
+	"github.com/tevino/abool"
 	"pandora-pay/address_balance_decryptor"
 	"pandora-pay/blockchain/blockchain_types"
 	"pandora-pay/blockchain/forging"
 	"pandora-pay/config"
 	"pandora-pay/helpers/multicast"
 	"pandora-pay/mempool"
+	"pandora-pay/store"
 	"pandora-pay/wallet/wallet_address"
+	"pandora-pay/wallet/wallet_contact"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type Wallet struct {
@@ -22,6 +27,13 @@ type Wallet struct {
 	Addresses               []*wallet_address.WalletAddress `json:"addresses" msgpack:"addresses"`
 	Loaded                  bool                            `json:"loaded" msgpack:"loaded"`
 	DelegatesCount          int                             `json:"delegatesCount" msgpack:"delegatesCount"`
+	Contacts                []*wallet_contact.WalletContact `json:"contacts" msgpack:"contacts"`
+	Name                    string                          `json:"-" msgpack:"-"`
+	store                   *store.Store
+	cliEnabled              bool
+	closed                  *abool.AtomicBool
+	lastActivity            atomic.Int64
+	autoLockTimer           *time.Timer
 	addressesMap            map[string]*wallet_address.WalletAddress
 	forging                 *forging.Forging
 	mempool                 *mempool.Mempool
@@ -31,8 +43,12 @@ type Wallet struct {
 	Lock                    sync.RWMutex `json:"-" msgpack:"-"`
 }
 
-func createWallet(forging *forging.Forging, mempool *mempool.Mempool, addressBalanceDecryptor *address_balance_decryptor.AddressBalanceDecryptor, updateNewChainUpdate *multicast.MulticastChannel[*blockchain_types.BlockchainUpdates]) (wallet *Wallet) {
+func createWallet(name string, walletStore *store.Store, cliEnabled bool, forging *forging.Forging, mempool *mempool.Mempool, addressBalanceDecryptor *address_balance_decryptor.AddressBalanceDecryptor, updateNewChainUpdate *multicast.MulticastChannel[*blockchain_types.BlockchainUpdates]) (wallet *Wallet) {
 	wallet = &Wallet{
+		Name:                    name,
+		store:                   walletStore,
+		cliEnabled:              cliEnabled,
+		closed:                  abool.New(),
 		forging:                 forging,
 		mempool:                 mempool,
 		updateNewChainUpdate:    updateNewChainUpdate,
@@ -52,6 +68,7 @@ func (wallet *Wallet) clearWallet() {
 	wallet.CountImportedIndex = 0
 	wallet.Addresses = make([]*wallet_address.WalletAddress, 0)
 	wallet.addressesMap = make(map[string]*wallet_address.WalletAddress)
+	wallet.Contacts = make([]*wallet_contact.WalletContact, 0)
 	wallet.Encryption = createEncryption(wallet)
 	wallet.nonHardening = false
 	wallet.setLoaded(false)
@@ -60,12 +77,27 @@ func (wallet *Wallet) clearWallet() {
 //must be locked before
 func (wallet *Wallet) setLoaded(newValue bool) {
 	wallet.Loaded = newValue
-	wallet.initWalletCLI()
+	wallet.updateAutoLock()
+	if wallet.cliEnabled {
+		wallet.initWalletCLI()
+	}
+}
+
+//must be locked before
+func (wallet *Wallet) setCLIEnabled(newValue bool) {
+	wallet.cliEnabled = newValue
+	if newValue {
+		wallet.initWalletCLI()
+	}
 }
 
 func CreateWallet(forging *forging.Forging, mempool *mempool.Mempool, addressBalanceDecryptor *address_balance_decryptor.AddressBalanceDecryptor) (*Wallet, error) {
+	return openWallet(DEFAULT_WALLET_NAME, store.StoreWallet, true, forging, mempool, addressBalanceDecryptor, nil)
+}
+
+func openWallet(name string, walletStore *store.Store, cliEnabled bool, forging *forging.Forging, mempool *mempool.Mempool, addressBalanceDecryptor *address_balance_decryptor.AddressBalanceDecryptor, updateNewChainUpdate *multicast.MulticastChannel[*blockchain_types.BlockchainUpdates]) (*Wallet, error) {
 
-	wallet := createWallet(forging, mempool, addressBalanceDecryptor, nil)
+	wallet := createWallet(name, walletStore, cliEnabled, forging, mempool, addressBalanceDecryptor, updateNewChainUpdate)
 
 	if err := wallet.loadWallet("", true); err != nil {
 		if err.Error() == "cipher: message authentication failed" {
@@ -0,0 +1,62 @@
+package wallet
+
+import (
+	"pandora-pay/gui"
+	"pandora-pay/gui/gui_logger"
+	"time"
+)
+
+//KeepAlive marks the wallet as being used, postponing the auto lock
+func (wallet *Wallet) KeepAlive() {
+	wallet.lastActivity.Store(time.Now().UnixNano())
+}
+
+//must be locked before
+func (wallet *Wallet) stopAutoLock() {
+	if wallet.autoLockTimer != nil {
+		wallet.autoLockTimer.Stop()
+		wallet.autoLockTimer = nil
+	}
+}
+
+//must be locked before
+func (wallet *Wallet) updateAutoLock() {
+
+	wallet.stopAutoLock()
+
+	if !wallet.Loaded || wallet.Encryption.Encrypted == ENCRYPTED_VERSION_PLAIN_TEXT || wallet.Encryption.AutoLockTimeout == 0 {
+		return
+	}
+
+	wallet.KeepAlive()
+	wallet.autoLockTimer = time.AfterFunc(time.Duration(wallet.Encryption.AutoLockTimeout)*time.Second, wallet.autoLock)
+}
+
+func (wallet *Wallet) autoLock() {
+
+	wallet.Lock.Lock()
+
+	if wallet.autoLockTimer == nil || wallet.closed.IsSet() {
+		wallet.Lock.Unlock()
+		return
+	}
+
+	timeout := time.Duration(wallet.Encryption.AutoLockTimeout) * time.Second
+	remaining := time.Until(time.Unix(0, wallet.lastActivity.Load()).Add(timeout))
+	if remaining > 0 {
+		wallet.autoLockTimer = time.AfterFunc(remaining, wallet.autoLock)
+		wallet.Lock.Unlock()
+		return
+	}
+
+	wallet.autoLockTimer = nil
+	encryption := wallet.Encryption
+	wallet.Lock.Unlock()
+
+	if err := encryption.Logout(); err != nil {
+		gui.GUI.Error(gui_logger.SUBSYSTEM_WALLET, "Error auto locking wallet", err)
+		return
+	}
+
+	gui.GUI.Info(gui_logger.SUBSYSTEM_WALLET, "Wallet was locked automatically after", timeout)
+}
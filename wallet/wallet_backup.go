@@ -0,0 +1,230 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"pandora-pay/cryptography"
+	"pandora-pay/cryptography/encryption"
+	"pandora-pay/helpers"
+	"pandora-pay/helpers/msgpack"
+	"pandora-pay/wallet/wallet_address"
+)
+
+type WalletBackupAddress struct {
+	Name           string `json:"name" msgpack:"name"`
+	AddressEncoded string `json:"addressEncoded" msgpack:"addressEncoded"`
+	Staked         bool   `json:"staked" msgpack:"staked"`
+	IsImported     bool   `json:"isImported" msgpack:"isImported"`
+}
+
+//WalletBackupData is the content of the backup that gets encrypted
+type WalletBackupData struct {
+	Addresses []*WalletBackupAddress `json:"addresses" msgpack:"addresses"`
+	Wallet    []byte                 `json:"wallet" msgpack:"wallet"` //wallet as JSON without the encryption
+}
+
+//WalletBackup is the backup file. The data is encrypted using a passphrase independent of the wallet password
+type WalletBackup struct {
+	Version        BackupVersion `json:"version" msgpack:"version"`
+	WalletVersion  Version       `json:"walletVersion" msgpack:"walletVersion"`
+	AddressesCount int           `json:"addressesCount" msgpack:"addressesCount"`
+	Salt           []byte        `json:"salt" msgpack:"salt"`
+	Difficulty     int           `json:"difficulty" msgpack:"difficulty"`
+	Checksum       []byte        `json:"checksum" msgpack:"checksum"` //SHA3 of the data before encryption
+	Data           []byte        `json:"data" msgpack:"data"`
+}
+
+//getAdditionalData returns the header of the backup. It is authenticated by the encryption, so the version and the key derivation parameters can't be changed
+func (backup *WalletBackup) getAdditionalData() ([]byte, error) {
+	return helpers.GetJSONDataExcept(backup, "data")
+}
+
+func (wallet *Wallet) ExportBackup(passphrase string, difficulty int) ([]byte, error) {
+
+	if passphrase == "" {
+		return nil, errors.New("Backup passphrase can not be empty")
+	}
+	if difficulty <= 0 || difficulty > 10 {
+		return nil, errors.New("Difficulty must be in the interval [1,10]")
+	}
+
+	wallet.Lock.RLock()
+	defer wallet.Lock.RUnlock()
+
+	if !wallet.Loaded {
+		return nil, errors.New("Wallet was not loaded!")
+	}
+
+	backupData := &WalletBackupData{
+		Addresses: make([]*WalletBackupAddress, len(wallet.Addresses)),
+	}
+	for i, addr := range wallet.Addresses {
+		backupData.Addresses[i] = &WalletBackupAddress{addr.Name, addr.AddressEncoded, addr.Staked, addr.IsImported}
+	}
+
+	var err error
+	if backupData.Wallet, err = helpers.GetJSONDataExcept(wallet, "encryption"); err != nil {
+		return nil, err
+	}
+
+	data, err := msgpack.Marshal(backupData)
+	if err != nil {
+		return nil, err
+	}
+
+	backup := &WalletBackup{
+		Version:        BACKUP_VERSION_ENCRYPTION_ARGON2,
+		WalletVersion:  wallet.Version,
+		AddressesCount: len(wallet.Addresses),
+		Salt:           helpers.RandomBytes(32),
+		Difficulty:     difficulty,
+		Checksum:       cryptography.SHA3(data),
+	}
+
+	cipher, err := encryption.CreateEncryptionCipher(passphrase, backup.Salt, uint32(difficulty)*30)
+	if err != nil {
+		return nil, err
+	}
+	additionalData, err := backup.getAdditionalData()
+	if err != nil {
+		return nil, err
+	}
+
+	if backup.Data, err = cipher.EncryptWithAdditionalData(data, additionalData); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(backup)
+}
+
+//ReadBackup decrypts the backup file and verifies its integrity without changing the wallet
+func ReadBackup(input []byte, passphrase string) (*WalletBackup, *Wallet, error) {
+
+	backup := &WalletBackup{}
+	if err := json.Unmarshal(input, backup); err != nil {
+		return nil, nil, errors.New("Error unmarshaling backup")
+	}
+
+	if backup.Version != BACKUP_VERSION_ENCRYPTION_ARGON2 {
+		return nil, nil, errors.New("Backup version is not supported")
+	}
+	if backup.WalletVersion != VERSION_SIMPLE {
+		return nil, nil, errors.New("Wallet version is not supported")
+	}
+	if backup.Difficulty <= 0 || backup.Difficulty > 10 {
+		return nil, nil, errors.New("Backup difficulty is invalid")
+	}
+	if len(backup.Checksum) != cryptography.HashSize {
+		return nil, nil, errors.New("Backup checksum is invalid")
+	}
+
+	cipher, err := encryption.CreateEncryptionCipher(passphrase, backup.Salt, uint32(backup.Difficulty)*30)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(backup.Data) < 12 {
+		return nil, nil, errors.New("Backup data is invalid")
+	}
+
+	additionalData, err := backup.getAdditionalData()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := cipher.DecryptWithAdditionalData(backup.Data, additionalData)
+	if err != nil {
+		return nil, nil, errors.New("Backup passphrase is wrong or the backup is corrupted")
+	}
+
+	if !bytes.Equal(cryptography.SHA3(data), backup.Checksum) {
+		return nil, nil, errors.New("Backup checksum is not matching")
+	}
+
+	backupData := &WalletBackupData{}
+	if err = msgpack.Unmarshal(data, backupData); err != nil {
+		return nil, nil, err
+	}
+
+	restored := &Wallet{}
+	if err = json.Unmarshal(backupData.Wallet, restored); err != nil {
+		return nil, nil, errors.New("Error unmarshaling wallet")
+	}
+
+	if restored.Version != backup.WalletVersion {
+		return nil, nil, errors.New("Wallet version is not matching")
+	}
+	if restored.Count != len(restored.Addresses) || restored.Count != backup.AddressesCount || restored.Count != len(backupData.Addresses) {
+		return nil, nil, errors.New("Wallet addresses count is not matching")
+	}
+
+	for i, addr := range restored.Addresses {
+		//the shared staked addresses have no private key
+		if addr.PrivateKey != nil && !bytes.Equal(addr.PrivateKey.GeneratePublicKey(), addr.PublicKey) {
+			return nil, nil, errors.New("Public Keys are not matching!")
+		}
+		if backupData.Addresses[i].AddressEncoded != addr.AddressEncoded {
+			return nil, nil, errors.New("Wallet addresses are not matching")
+		}
+	}
+
+	return backup, restored, nil
+}
+
+//ImportBackup replaces the current wallet with the one from the backup only after the backup was verified
+func (wallet *Wallet) ImportBackup(input []byte, passphrase string) error {
+
+	_, restored, err := ReadBackup(input, passphrase)
+	if err != nil {
+		return err
+	}
+
+	return wallet.RestoreBackup(restored)
+}
+
+//RestoreBackup replaces the current wallet with a wallet returned by ReadBackup. The current wallet encryption is kept
+//The restored wallet is saved first, deleting the stored addresses of the current wallet in the same transaction, and only then it replaces the current wallet
+func (wallet *Wallet) RestoreBackup(restored *Wallet) (err error) {
+
+	wallet.Lock.Lock()
+	defer wallet.Lock.Unlock()
+
+	if !wallet.Loaded {
+		return errors.New("Wallet was not loaded!")
+	}
+
+	if restored.Contacts == nil {
+		restored.Contacts = wallet.Contacts
+	}
+	restored.Encryption = wallet.Encryption
+	restored.store = wallet.store
+	restored.Loaded = true
+
+	if err = restored.saveWallet(0, restored.Count, restored.Count, false); err != nil {
+		return
+	}
+
+	for _, addr := range wallet.Addresses {
+		wallet.forging.Wallet.RemoveWallet(addr.PublicKey, false, nil, nil, 0)
+	}
+
+	wallet.Version = restored.Version
+	wallet.Mnemonic = restored.Mnemonic
+	wallet.Seed = restored.Seed
+	wallet.SeedIndex = restored.SeedIndex
+	wallet.Count = restored.Count
+	wallet.CountImportedIndex = restored.CountImportedIndex
+	wallet.DelegatesCount = restored.DelegatesCount
+	wallet.Addresses = restored.Addresses
+	wallet.Contacts = restored.Contacts
+	wallet.nonHardening = false
+
+	wallet.addressesMap = make(map[string]*wallet_address.WalletAddress)
+	for _, addr := range wallet.Addresses {
+		wallet.addressesMap[string(addr.PublicKey)] = addr
+	}
+	wallet.setLoaded(true)
+
+	return wallet.walletLoaded(false)
+}
@@ -0,0 +1,104 @@
+package wallet
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/forging"
+	"pandora-pay/config/config_forging"
+	"pandora-pay/gui"
+	"pandora-pay/gui/gui_non_interactive"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/store/store_db/store_db_memory"
+	"pandora-pay/wallet/wallet_address"
+	"pandora-pay/wallet/wallet_address/shared_staked"
+	"strconv"
+	"testing"
+)
+
+func createTestWallet(t *testing.T) *Wallet {
+
+	gui.GUI, _ = gui_non_interactive.CreateGUINonInteractive(nil)
+	config_forging.FORGING_ENABLED = false
+
+	db, err := store_db_memory.CreateStoreDBMemory("wallet")
+	assert.Nil(t, err)
+
+	forging, err := forging.CreateForging(nil, nil)
+	assert.Nil(t, err)
+
+	wallet := createWallet("test", &store.Store{Name: "wallet", Opened: true, DB: db}, false, forging, nil, nil, nil)
+	assert.Nil(t, wallet.CreateEmptyWallet())
+
+	return wallet
+}
+
+func TestWalletBackup(t *testing.T) {
+
+	wallet := createTestWallet(t)
+
+	input, err := wallet.ExportBackup("passphrase", 1)
+	assert.Nil(t, err)
+
+	_, restored, err := ReadBackup(input, "passphrase")
+	assert.Nil(t, err)
+	assert.Equal(t, wallet.Mnemonic, restored.Mnemonic)
+	assert.Equal(t, wallet.Addresses[0].AddressEncoded, restored.Addresses[0].AddressEncoded)
+
+	_, _, err = ReadBackup(input, "wrong")
+	assert.NotNil(t, err, "Wrong passphrase should fail")
+
+	//the header is authenticated
+	backup := map[string]any{}
+	assert.Nil(t, json.Unmarshal(input, &backup))
+	backup["difficulty"] = 2
+	tampered, err := json.Marshal(backup)
+	assert.Nil(t, err)
+
+	_, _, err = ReadBackup(tampered, "passphrase")
+	assert.NotNil(t, err, "Tampered header should fail")
+
+	//the stale addresses are deleted
+	for i := 0; i < 2; i++ {
+		_, err = wallet.AddNewAddress(true, "", false, false, true)
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, 3, wallet.Count)
+
+	assert.Nil(t, wallet.ImportBackup(input, "passphrase"))
+	assert.Equal(t, 1, wallet.Count)
+
+	assert.Nil(t, wallet.store.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+		assert.NotNil(t, reader.Get("wallet-address-0"))
+		for i := 1; i < 3; i++ {
+			assert.Nil(t, reader.Get("wallet-address-"+strconv.Itoa(i)), "Stale address was not deleted")
+		}
+		return nil
+	}))
+}
+
+func TestWalletBackupSharedStaked(t *testing.T) {
+
+	wallet := createTestWallet(t)
+
+	//the shared staked addresses have no private key
+	sharedStakedPrivateKey := addresses.GenerateNewPrivateKey()
+	assert.Nil(t, wallet.AddSharedStakedAddress(&wallet_address.WalletAddress{
+		Version:        wallet_address.VERSION_NORMAL,
+		Name:           "Delegated Stake",
+		PublicKey:      addresses.GenerateNewPrivateKey().GeneratePublicKey(),
+		Staked:         true,
+		IsSharedStaked: true,
+		SharedStaked:   &shared_staked.WalletAddressSharedStaked{PrivateKey: sharedStakedPrivateKey, PublicKey: sharedStakedPrivateKey.GeneratePublicKey()},
+	}, true))
+
+	input, err := wallet.ExportBackup("passphrase", 1)
+	assert.Nil(t, err)
+
+	_, restored, err := ReadBackup(input, "passphrase")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, restored.Count)
+	assert.Nil(t, restored.Addresses[1].PrivateKey)
+	assert.Equal(t, wallet.Addresses[1].AddressEncoded, restored.Addresses[1].AddressEncoded)
+}
@@ -28,6 +28,7 @@ import (
 	"pandora-pay/store/store_db/store_db_interface"
 	"pandora-pay/wallet/wallet_address"
 	"pandora-pay/wallet/wallet_address/shared_staked"
+	"pandora-pay/wallet/wallet_contact"
 	"strconv"
 )
 
@@ -128,6 +129,8 @@ func (wallet *Wallet) CliListAddresses(cmd string, ctx context.Context) (err err
 		addressRegisteredString string
 	}
 
+	wallet.KeepAlive()
+
 	wallet.Lock.RLock()
 	gui.GUI.OutputWrite("Wallet")
 	gui.GUI.OutputWrite("Version: " + wallet.Version.String())
@@ -281,6 +284,46 @@ func (wallet *Wallet) CliSelectAddress(text string, ctx context.Context) (*walle
 	return walletAddress, walletAddress.AddressEncoded, index, nil
 }
 
+func (wallet *Wallet) CliListContacts(cmd string, ctx context.Context) (err error) {
+
+	contacts := wallet.GetContacts(true)
+
+	gui.GUI.OutputWrite("Contacts: " + strconv.Itoa(len(contacts)))
+	for i, contact := range contacts {
+		gui.GUI.OutputWrite(fmt.Sprintf("%d) %s :: %s", i, contact.Label, contact.AddressEncoded))
+		if len(contact.Asset) > 0 {
+			gui.GUI.OutputWrite(fmt.Sprintf("%18s: %s", "Asset", base64.StdEncoding.EncodeToString(contact.Asset)))
+		}
+		if len(contact.PaymentID) > 0 {
+			gui.GUI.OutputWrite(fmt.Sprintf("%18s: %s", "Payment ID", base64.StdEncoding.EncodeToString(contact.PaymentID)))
+		}
+	}
+
+	return
+}
+
+//CliSelectContact returns nil in case the address book is empty or the user left the selection empty
+func (wallet *Wallet) CliSelectContact(text string, ctx context.Context) (*wallet_contact.WalletContact, error) {
+
+	contacts := wallet.GetContacts(true)
+	if len(contacts) == 0 {
+		return nil, nil
+	}
+
+	if err := wallet.CliListContacts("", ctx); err != nil {
+		return nil, err
+	}
+
+	index := gui.GUI.OutputReadInt(text+". Leave empty for none", true, -1, func(value int) bool {
+		return value >= 0 && value < len(contacts)
+	})
+	if index == -1 {
+		return nil, nil
+	}
+
+	return contacts[index], nil
+}
+
 func (wallet *Wallet) initWalletCLI() {
 
 	cliExportAddresses := func(cmd string, ctx context.Context) (err error) {
@@ -415,6 +458,62 @@ func (wallet *Wallet) initWalletCLI() {
 		return
 	}
 
+	cliExportWalletBackup := func(cmd string, ctx context.Context) (err error) {
+
+		filename := gui.GUI.OutputReadFilename("Path to export", "pandorabackup", false)
+		passphrase := gui.GUI.OutputReadString("Passphrase for encrypting the backup. It can be different than the wallet password")
+		if passphrase == "" {
+			return errors.New("Backup passphrase can not be empty")
+		}
+		difficulty := gui.GUI.OutputReadInt("Difficulty for encryption", false, 0, func(value int) bool {
+			return value >= 1 && value <= 10
+		})
+
+		var data []byte
+		if data, err = wallet.ExportBackup(passphrase, difficulty); err != nil {
+			return
+		}
+
+		if err = files.WriteFile(filename, string(data)); err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite("Wallet Backup Exported successfully to: ", filename)
+		return
+	}
+
+	cliRestoreWalletBackup := func(cmd string, ctx context.Context) (err error) {
+
+		str := gui.GUI.OutputReadFilename("Path to import Wallet Backup", "pandorabackup", false)
+
+		data, err := os.ReadFile(str)
+		if err != nil {
+			return
+		}
+
+		passphrase := gui.GUI.OutputReadString("Passphrase of the backup")
+
+		gui.GUI.OutputWrite("Verifying backup...")
+
+		backup, restored, err := ReadBackup(data, passphrase)
+		if err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite(fmt.Sprintf("Backup verified: %s, wallet %s, %d addresses", backup.Version, backup.WalletVersion, backup.AddressesCount))
+
+		if !gui.GUI.OutputReadBool("Your wallet will be REPLACED with this one! y/n", false, false) {
+			return errors.New("You didn't accept REPLACING your existing wallet")
+		}
+
+		if err = wallet.RestoreBackup(restored); err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite("Wallet Restored Successfully from: ", str)
+		return
+	}
+
 	cliCreateNewAddress := func(cmd string, ctx context.Context) (err error) {
 
 		filename := gui.GUI.OutputReadFilename("Name of your new address", "", false)
@@ -605,6 +704,32 @@ func (wallet *Wallet) initWalletCLI() {
 		return
 	}
 
+	cliChangeWalletPassword := func(cmd string, ctx context.Context) (err error) {
+
+		oldPassword := gui.GUI.OutputReadString("Current password")
+		newPassword := gui.GUI.OutputReadString("New password for encrypting wallet")
+		difficulty := gui.GUI.OutputReadInt("Difficulty for encryption", false, 0, func(value int) bool {
+			return value >= 1 && value <= 10
+		})
+
+		gui.GUI.OutputWrite("Wallet re-encrypting...")
+
+		if err = wallet.Encryption.ChangeEncryption(oldPassword, newPassword, difficulty); err == nil {
+			gui.GUI.OutputWrite("Wallet password changed successfully")
+		}
+		return
+	}
+
+	cliSetWalletAutoLock := func(cmd string, ctx context.Context) (err error) {
+
+		timeout := gui.GUI.OutputReadUint64("Auto lock after inactivity (seconds). Use 0 to disable it", false, 0, nil)
+
+		if err = wallet.Encryption.SetAutoLockTimeout(timeout); err == nil {
+			gui.GUI.OutputWrite("Wallet auto lock updated successfully")
+		}
+		return
+	}
+
 	cliRemoveEncryption := func(cmd string, ctx context.Context) (err error) {
 		gui.GUI.OutputWrite("Wallet removing encryption...")
 		if err = wallet.Encryption.RemoveEncryption(); err == nil {
@@ -613,6 +738,55 @@ func (wallet *Wallet) initWalletCLI() {
 		return
 	}
 
+	cliAddContact := func(cmd string, ctx context.Context) (err error) {
+
+		contact := &wallet_contact.WalletContact{}
+		contact.Label = gui.GUI.OutputReadString("Label")
+
+		for {
+			contact.AddressEncoded = gui.GUI.OutputReadString("Address")
+			if _, err = addresses.DecodeAddr(contact.AddressEncoded); err != nil {
+				gui.GUI.OutputWrite("Invalid Address")
+				continue
+			}
+			break
+		}
+
+		contact.Asset = gui.GUI.OutputReadBytes("Default Asset. Leave empty for Native Asset", func(input []byte) bool {
+			return len(input) == 0 || len(input) == config_coins.ASSET_LENGTH
+		})
+		contact.PaymentID = gui.GUI.OutputReadBytes("Default Payment ID. Leave empty for none", func(input []byte) bool {
+			return len(input) == 0 || len(input) == 8
+		})
+
+		if err = wallet.AddContact(contact, true); err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite("Contact added: " + contact.Label)
+		return wallet.CliListContacts(cmd, ctx)
+	}
+
+	cliRemoveContact := func(cmd string, ctx context.Context) (err error) {
+
+		var contact *wallet_contact.WalletContact
+		if contact, err = wallet.CliSelectContact("Select Contact to be Removed", ctx); err != nil || contact == nil {
+			return
+		}
+
+		var success bool
+		if success, err = wallet.RemoveContact(contact.Label, true); err != nil {
+			return
+		}
+
+		if success {
+			gui.GUI.OutputWrite("Contact removed")
+		} else {
+			gui.GUI.OutputWrite("Contact was NOT removed ")
+		}
+		return
+	}
+
 	cliCreatePair := func(cmd string, ctx context.Context) (err error) {
 		key := addresses.GenerateNewPrivateKey()
 		pub := key.GeneratePublicKey()
@@ -722,13 +896,20 @@ func (wallet *Wallet) initWalletCLI() {
 	gui.GUI.CommandDefineCallback("Import Address Secret Key", cliImportAddressSecretKey, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Remove Address", cliRemoveAddress, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Export Staked Staked Address", cliExportSharedStakedAddress, wallet.Loaded)
+	gui.GUI.CommandDefineCallback("List Contacts", wallet.CliListContacts, wallet.Loaded)
+	gui.GUI.CommandDefineCallback("Add Contact", cliAddContact, wallet.Loaded)
+	gui.GUI.CommandDefineCallback("Remove Contact", cliRemoveContact, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Export Addresses", cliExportAddresses, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Export Address JSON", cliExportAddressJSON, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Import Address JSON", cliImportAddressJSON, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Export Wallet JSON", cliExportWalletJSON, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Import Wallet JSON", cliImportWalletJSON, wallet.Loaded)
+	gui.GUI.CommandDefineCallback("Export Wallet Backup", cliExportWalletBackup, wallet.Loaded)
+	gui.GUI.CommandDefineCallback("Restore Wallet Backup", cliRestoreWalletBackup, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Encrypt Wallet", cliEncryptWallet, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Remove Encryption", cliRemoveEncryption, wallet.Loaded)
+	gui.GUI.CommandDefineCallback("Change Wallet Password", cliChangeWalletPassword, wallet.Loaded)
+	gui.GUI.CommandDefineCallback("Set Wallet Auto Lock", cliSetWalletAutoLock, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Decrypt Wallet", cliDecryptWallet, !wallet.Loaded)
 
 	gui.GUI.CommandDefineCallback("Create (PublicKey, PrivateKey) pair", cliCreatePair, true)
@@ -0,0 +1,65 @@
+package wallet_contact
+
+import (
+	"bytes"
+	"errors"
+	"pandora-pay/addresses"
+	"pandora-pay/config/config_coins"
+)
+
+type WalletContact struct {
+	Label          string `json:"label" msgpack:"label"`
+	AddressEncoded string `json:"addressEncoded" msgpack:"addressEncoded"`
+	Asset          []byte `json:"asset,omitempty" msgpack:"asset,omitempty"`         //default asset
+	PaymentID      []byte `json:"paymentID,omitempty" msgpack:"paymentID,omitempty"` //default payment id
+}
+
+func (contact *WalletContact) Validate() error {
+
+	if len(contact.Label) == 0 || len(contact.Label) > 255 {
+		return errors.New("Contact label is invalid")
+	}
+
+	address, err := addresses.DecodeAddr(contact.AddressEncoded)
+	if err != nil {
+		return err
+	}
+
+	if len(contact.Asset) != 0 && len(contact.Asset) != config_coins.ASSET_LENGTH {
+		return errors.New("Invalid Asset size")
+	}
+	if len(contact.PaymentID) != 0 && len(contact.PaymentID) != 8 {
+		return errors.New("Invalid PaymentID. It must be an 8 byte")
+	}
+
+	if address.IsIntegratedPaymentID() && len(contact.PaymentID) > 0 && !bytes.Equal(address.PaymentID, contact.PaymentID) {
+		return errors.New("PaymentID is different than the one integrated in the address")
+	}
+	if address.IsIntegratedPaymentAsset() && len(contact.Asset) > 0 && !bytes.Equal(address.PaymentAsset, contact.Asset) {
+		return errors.New("Asset is different than the one integrated in the address")
+	}
+
+	return nil
+}
+
+//GetAsset returns the default asset of the contact. In case it is not specified, it returns the native asset
+func (contact *WalletContact) GetAsset() []byte {
+	if len(contact.Asset) == 0 {
+		return config_coins.NATIVE_ASSET_FULL
+	}
+	return contact.Asset
+}
+
+func (contact *WalletContact) Clone() *WalletContact {
+
+	if contact == nil {
+		return nil
+	}
+
+	return &WalletContact{
+		contact.Label,
+		contact.AddressEncoded,
+		contact.Asset,
+		contact.PaymentID,
+	}
+}
@@ -0,0 +1,137 @@
+package wallet
+
+import (
+	"errors"
+	"pandora-pay/config/globals"
+	"pandora-pay/wallet/wallet_contact"
+)
+
+//must be locked before
+func (wallet *Wallet) getContactIndex(label string) int {
+	for i, contact := range wallet.Contacts {
+		if contact.Label == label {
+			return i
+		}
+	}
+	return -1
+}
+
+func (wallet *Wallet) GetContacts(lock bool) []*wallet_contact.WalletContact {
+
+	if lock {
+		wallet.Lock.RLock()
+		defer wallet.Lock.RUnlock()
+	}
+
+	list := make([]*wallet_contact.WalletContact, len(wallet.Contacts))
+	for i, contact := range wallet.Contacts {
+		list[i] = contact.Clone()
+	}
+	return list
+}
+
+func (wallet *Wallet) GetContact(label string, lock bool) (*wallet_contact.WalletContact, error) {
+
+	if lock {
+		wallet.Lock.RLock()
+		defer wallet.Lock.RUnlock()
+	}
+
+	index := wallet.getContactIndex(label)
+	if index == -1 {
+		return nil, errors.New("Contact was not found")
+	}
+	return wallet.Contacts[index].Clone(), nil
+}
+
+func (wallet *Wallet) AddContact(contact *wallet_contact.WalletContact, lock bool) error {
+
+	if lock {
+		wallet.Lock.Lock()
+		defer wallet.Lock.Unlock()
+	}
+
+	if !wallet.Loaded {
+		return errors.New("Wallet was not loaded!")
+	}
+
+	if err := contact.Validate(); err != nil {
+		return err
+	}
+
+	if wallet.getContactIndex(contact.Label) != -1 {
+		return errors.New("Contact with the same label already exists")
+	}
+
+	contact = contact.Clone()
+	wallet.Contacts = append(wallet.Contacts, contact)
+
+	if err := wallet.saveWallet(0, 0, -1, false); err != nil {
+		return err
+	}
+
+	globals.MainEvents.BroadcastEvent("wallet/contact-added", contact.Clone())
+	return nil
+}
+
+//UpdateContact replaces the contact identified by label. The label can be changed as well
+func (wallet *Wallet) UpdateContact(label string, contact *wallet_contact.WalletContact, lock bool) error {
+
+	if lock {
+		wallet.Lock.Lock()
+		defer wallet.Lock.Unlock()
+	}
+
+	if !wallet.Loaded {
+		return errors.New("Wallet was not loaded!")
+	}
+
+	if err := contact.Validate(); err != nil {
+		return err
+	}
+
+	index := wallet.getContactIndex(label)
+	if index == -1 {
+		return errors.New("Contact was not found")
+	}
+	if label != contact.Label && wallet.getContactIndex(contact.Label) != -1 {
+		return errors.New("Contact with the same label already exists")
+	}
+
+	contact = contact.Clone()
+	wallet.Contacts[index] = contact
+
+	if err := wallet.saveWallet(0, 0, -1, false); err != nil {
+		return err
+	}
+
+	globals.MainEvents.BroadcastEvent("wallet/contact-updated", contact.Clone())
+	return nil
+}
+
+func (wallet *Wallet) RemoveContact(label string, lock bool) (bool, error) {
+
+	if lock {
+		wallet.Lock.Lock()
+		defer wallet.Lock.Unlock()
+	}
+
+	if !wallet.Loaded {
+		return false, errors.New("Wallet was not loaded!")
+	}
+
+	index := wallet.getContactIndex(label)
+	if index == -1 {
+		return false, nil
+	}
+
+	removing := wallet.Contacts[index]
+	wallet.Contacts = append(wallet.Contacts[:index], wallet.Contacts[index+1:]...)
+
+	if err := wallet.saveWallet(0, 0, -1, false); err != nil {
+		return false, err
+	}
+
+	globals.MainEvents.BroadcastEvent("wallet/contact-removed", removing)
+	return true, nil
+}
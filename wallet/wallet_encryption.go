@@ -12,6 +12,7 @@ type WalletEncryption struct {
 	Encrypted        EncryptedVersion `json:"encrypted" msgpack:"encrypted"`
 	Salt             []byte           `json:"salt" msgpack:"salt"`
 	Difficulty       int              `json:"difficulty" msgpack:"difficulty"`
+	AutoLockTimeout  uint64           `json:"autoLockTimeout" msgpack:"autoLockTimeout"` //seconds of inactivity after the wallet is logged out. 0 disables it
 	password         string
 	encryptionCipher *encryption.EncryptionCipher
 }
@@ -52,10 +53,78 @@ func (self *WalletEncryption) Encrypt(newPassword string, difficulty int) (err e
 		return
 	}
 
+	self.wallet.updateAutoLock()
+
 	globals.MainEvents.BroadcastEvent("wallet/encrypted", true)
 	return
 }
 
+//ChangeEncryption re-encrypts the wallet using a new password and/or a new difficulty.
+//The entire wallet is re-encrypted and saved in a single store transaction, so the wallet is never written in clear.
+//In case saving fails, the previous encryption is restored
+func (self *WalletEncryption) ChangeEncryption(oldPassword, newPassword string, difficulty int) (err error) {
+	self.wallet.Lock.Lock()
+	defer self.wallet.Lock.Unlock()
+
+	if !self.wallet.Loaded {
+		return errors.New("Wallet was not loaded!")
+	}
+	if self.Encrypted == ENCRYPTED_VERSION_PLAIN_TEXT {
+		return errors.New("Wallet is not encrypted!")
+	}
+	if self.password != oldPassword {
+		return errors.New("Password is not matching")
+	}
+	if newPassword == "" {
+		return errors.New("New password can not be empty")
+	}
+	if difficulty <= 0 || difficulty > 10 {
+		return errors.New("Difficulty must be in the interval [1,10]")
+	}
+
+	oldSalt, oldDifficulty, oldEncryptionCipher := self.Salt, self.Difficulty, self.encryptionCipher
+
+	self.password = newPassword
+	self.Salt = helpers.RandomBytes(32)
+	self.Difficulty = difficulty
+
+	if err = self.createEncryptionCipher(); err == nil {
+		err = self.wallet.saveWalletEntire(false)
+	}
+
+	if err != nil {
+		self.password, self.Salt, self.Difficulty, self.encryptionCipher = oldPassword, oldSalt, oldDifficulty, oldEncryptionCipher
+		return
+	}
+
+	self.wallet.updateAutoLock()
+
+	globals.MainEvents.BroadcastEvent("wallet/encryption-changed", true)
+	return
+}
+
+func (self *WalletEncryption) SetAutoLockTimeout(timeout uint64) (err error) {
+	self.wallet.Lock.Lock()
+	defer self.wallet.Lock.Unlock()
+
+	if !self.wallet.Loaded {
+		return errors.New("Wallet was not loaded!")
+	}
+
+	oldTimeout := self.AutoLockTimeout
+	self.AutoLockTimeout = timeout
+
+	if err = self.wallet.saveWallet(0, 0, -1, false); err != nil {
+		self.AutoLockTimeout = oldTimeout
+		return
+	}
+
+	self.wallet.updateAutoLock()
+
+	globals.MainEvents.BroadcastEvent("wallet/auto-lock", timeout)
+	return
+}
+
 func (self *WalletEncryption) encryptData(input []byte) ([]byte, error) {
 	if self.Encrypted == ENCRYPTED_VERSION_ENCRYPTION_ARGON2 {
 		return self.encryptionCipher.Encrypt(input)
@@ -102,6 +171,7 @@ func (self *WalletEncryption) CheckPassword(password string, requirePassword boo
 		return errors.New("Password is not matching")
 	}
 
+	self.wallet.KeepAlive()
 	return nil
 }
 
@@ -124,6 +194,8 @@ func (self *WalletEncryption) RemoveEncryption() (err error) {
 		return
 	}
 
+	self.wallet.updateAutoLock()
+
 	globals.MainEvents.BroadcastEvent("wallet/removed-encryption", true)
 	return
 }
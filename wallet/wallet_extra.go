@@ -9,6 +9,7 @@ import (
 	"pandora-pay/config/config_coins"
 	"pandora-pay/config/config_forging"
 	"pandora-pay/gui"
+	"pandora-pay/gui/gui_logger"
 	"pandora-pay/helpers/recovery"
 	"pandora-pay/store"
 	"pandora-pay/store/store_db/store_db_interface"
@@ -23,6 +24,10 @@ func (wallet *Wallet) processRefreshWallets() {
 
 		for {
 
+			if wallet.closed.IsSet() {
+				return
+			}
+
 			if config_forging.FORGING_ENABLED {
 
 				accsList := []*account.Account{}
@@ -44,6 +49,9 @@ func (wallet *Wallet) processRefreshWallets() {
 					visited := make(map[string]bool)
 					for i := 0; i < 50; i++ {
 						addr := wallet.GetRandomAddress()
+						if addr == nil {
+							break
+						}
 						if visited[string(addr.PublicKey)] {
 							continue
 						}
@@ -66,7 +74,7 @@ func (wallet *Wallet) processRefreshWallets() {
 
 					return
 				}); err != nil {
-					gui.GUI.Error("Error processRefreshWallets", err)
+					gui.GUI.Error(gui_logger.SUBSYSTEM_WALLET, "Error processRefreshWallets", err)
 				}
 
 				for i, acc := range accsList {
@@ -27,6 +27,9 @@ func (wallet *Wallet) GetAddressesCount() int {
 func (wallet *Wallet) GetRandomAddress() *wallet_address.WalletAddress {
 	wallet.Lock.RLock()
 	defer wallet.Lock.RUnlock()
+	if len(wallet.Addresses) == 0 {
+		return nil
+	}
 	index := rand.Intn(len(wallet.Addresses))
 	return wallet.Addresses[index].Clone()
 }
@@ -591,7 +594,7 @@ func (wallet *Wallet) TryDecryptBalance(addr *wallet_address.WalletAddress, encr
 
 func (wallet *Wallet) ImportWalletJSON(data []byte) (err error) {
 
-	wallet2 := createWallet(wallet.forging, wallet.mempool, wallet.addressBalanceDecryptor, wallet.updateNewChainUpdate)
+	wallet2 := createWallet(wallet.Name, wallet.store, false, wallet.forging, wallet.mempool, wallet.addressBalanceDecryptor, wallet.updateNewChainUpdate)
 	if err = json.Unmarshal(data, wallet2); err != nil {
 		return errors.New("Error unmarshaling wallet")
 	}
@@ -629,5 +632,9 @@ func (wallet *Wallet) SetNonHardening(value bool) {
 }
 
 func (wallet *Wallet) Close() {
+	wallet.closed.Set()
 
+	wallet.Lock.Lock()
+	wallet.stopAutoLock()
+	wallet.Lock.Unlock()
 }
@@ -12,6 +12,7 @@ import (
 	"pandora-pay/config/config_forging"
 	"pandora-pay/config/globals"
 	"pandora-pay/gui"
+	"pandora-pay/gui/gui_logger"
 	"pandora-pay/helpers"
 	"pandora-pay/helpers/generics"
 	"pandora-pay/helpers/msgpack"
@@ -42,9 +43,10 @@ func (wallet *Wallet) saveWalletEntire(lock bool) error {
 		wallet.Lock.RLock()
 		defer wallet.Lock.RUnlock()
 	}
-	return wallet.saveWallet(0, wallet.Count, -1, false)
+	return wallet.saveWallet(0, wallet.Count, wallet.Count, false)
 }
 
+//saveWallet saves the addresses in [start, end) and deletes the stored addresses starting with deleteIndex
 func (wallet *Wallet) saveWallet(start, end, deleteIndex int, lock bool) error {
 
 	if lock {
@@ -59,7 +61,7 @@ func (wallet *Wallet) saveWallet(start, end, deleteIndex int, lock bool) error {
 		return errors.New("Can't save your wallet because your stored wallet on the drive was not successfully loaded")
 	}
 
-	return store.StoreWallet.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+	return wallet.store.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
 
 		var marshal []byte
 
@@ -89,7 +91,9 @@ func (wallet *Wallet) saveWallet(start, end, deleteIndex int, lock bool) error {
 			writer.Put("wallet-address-"+strconv.Itoa(i), marshal)
 		}
 		if deleteIndex != -1 {
-			writer.Delete("wallet-address-" + strconv.Itoa(deleteIndex))
+			for i := deleteIndex; writer.Get("wallet-address-"+strconv.Itoa(i)) != nil; i++ {
+				writer.Delete("wallet-address-" + strconv.Itoa(i))
+			}
 		}
 
 		writer.Put("saved", []byte{1})
@@ -107,7 +111,7 @@ func (wallet *Wallet) loadWallet(password string, firstTime bool) error {
 
 	wallet.clearWallet()
 
-	return store.StoreWallet.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+	return wallet.store.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
 
 		saved := reader.Get("saved") //safe only internal
 		if saved == nil {
@@ -116,7 +120,7 @@ func (wallet *Wallet) loadWallet(password string, firstTime bool) error {
 
 		if bytes.Equal(saved, []byte{1}) {
 
-			gui.GUI.Log("Wallet Loading... ")
+			gui.GUI.Log(gui_logger.SUBSYSTEM_WALLET, "Wallet Loading... ")
 
 			var unmarshal []byte
 
@@ -194,7 +198,7 @@ func (wallet *Wallet) walletLoaded(firstTime bool) error {
 
 	wallet.updateWallet()
 	globals.MainEvents.BroadcastEvent("wallet/loaded", wallet.Count)
-	gui.GUI.Log("Wallet Loaded! " + strconv.Itoa(wallet.Count))
+	gui.GUI.Log(gui_logger.SUBSYSTEM_WALLET, "Wallet Loaded! "+strconv.Itoa(wallet.Count))
 
 	return nil
 }
@@ -32,3 +32,18 @@ func (e EncryptedVersion) String() string {
 		return "Unknown EncryptedVersion"
 	}
 }
+
+type BackupVersion int
+
+const (
+	BACKUP_VERSION_ENCRYPTION_ARGON2 BackupVersion = iota
+)
+
+func (e BackupVersion) String() string {
+	switch e {
+	case BACKUP_VERSION_ENCRYPTION_ARGON2:
+		return "BACKUP_VERSION_ENCRYPTION_ARGON2"
+	default:
+		return "Unknown BackupVersion"
+	}
+}
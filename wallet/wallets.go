@@ -0,0 +1,404 @@
+package wallet
+
+import (
+	"errors"
+	"pandora-pay/address_balance_decryptor"
+	"pandora-pay/blockchain/blockchain_types"
+	"pandora-pay/blockchain/forging"
+	"pandora-pay/config/globals"
+	"pandora-pay/helpers/msgpack"
+	"pandora-pay/helpers/multicast"
+	"pandora-pay/mempool"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/wallet/wallet_address"
+	"regexp"
+	"sync"
+)
+
+const DEFAULT_WALLET_NAME = "default"
+
+var walletNameRegexp = regexp.MustCompile("^[a-zA-Z0-9_-]{1,32}$")
+
+type WalletInfo struct {
+	Name      string           `json:"name" msgpack:"name"`
+	Opened    bool             `json:"opened" msgpack:"opened"`
+	Loaded    bool             `json:"loaded" msgpack:"loaded"`
+	Selected  bool             `json:"selected" msgpack:"selected"`
+	Encrypted EncryptedVersion `json:"encrypted" msgpack:"encrypted"`
+	Count     int              `json:"count" msgpack:"count"`
+}
+
+//Wallets keeps the default wallet (stored in store.StoreWallet) and all the named wallets that were opened.
+//Every named wallet has its own store and its own encryption. The selected wallet is used only by the CLI
+type Wallets struct {
+	names                   []string
+	list                    map[string]*Wallet
+	defaultWallet           *Wallet
+	selected                *Wallet
+	forging                 *forging.Forging
+	mempool                 *mempool.Mempool
+	addressBalanceDecryptor *address_balance_decryptor.AddressBalanceDecryptor
+	updateNewChainUpdate    *multicast.MulticastChannel[*blockchain_types.BlockchainUpdates]
+	Lock                    sync.RWMutex
+}
+
+func CreateWallets(defaultWallet *Wallet, updateNewChainUpdate *multicast.MulticastChannel[*blockchain_types.BlockchainUpdates]) (*Wallets, error) {
+
+	wallets := &Wallets{
+		names:                   []string{DEFAULT_WALLET_NAME},
+		list:                    map[string]*Wallet{DEFAULT_WALLET_NAME: defaultWallet},
+		defaultWallet:           defaultWallet,
+		selected:                defaultWallet,
+		forging:                 defaultWallet.forging,
+		mempool:                 defaultWallet.mempool,
+		addressBalanceDecryptor: defaultWallet.addressBalanceDecryptor,
+		updateNewChainUpdate:    updateNewChainUpdate,
+	}
+
+	if err := wallets.loadWalletsNames(); err != nil {
+		return nil, err
+	}
+
+	wallets.initWalletsCLI()
+
+	return wallets, nil
+}
+
+func (wallets *Wallets) loadWalletsNames() error {
+	return store.StoreWallet.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		data := reader.Get("wallets")
+		if data == nil {
+			return
+		}
+
+		names := []string{}
+		if err = msgpack.Unmarshal(data, &names); err != nil {
+			return
+		}
+
+		wallets.names = append(wallets.names, names...)
+		return
+	})
+}
+
+//must be locked before
+func (wallets *Wallets) saveWalletsNames() error {
+	return store.StoreWallet.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		var marshal []byte
+		if marshal, err = msgpack.Marshal(wallets.names[1:]); err != nil {
+			return
+		}
+
+		writer.Put("wallets", marshal)
+		return
+	})
+}
+
+//must be locked before
+func (wallets *Wallets) exists(name string) bool {
+	for _, it := range wallets.names {
+		if it == name {
+			return true
+		}
+	}
+	return false
+}
+
+//must be locked before
+func (wallets *Wallets) openWallet(name string, password string) (wallet *Wallet, err error) {
+
+	var walletStore *store.Store
+	if walletStore, err = store.CreateStoreWallet(name); err != nil {
+		return
+	}
+
+	if wallet, err = openWallet(name, walletStore, false, wallets.forging, wallets.mempool, wallets.addressBalanceDecryptor, nil); err != nil {
+		walletStore.Close()
+		return
+	}
+
+	if password != "" {
+		if err = wallet.Encryption.Decrypt(password); err != nil {
+			wallet.Close()
+			walletStore.Close()
+			return
+		}
+	}
+
+	wallet.InitializeWallet(wallets.updateNewChainUpdate)
+	if err = wallet.StartWallet(); err != nil {
+		wallet.Close()
+		walletStore.Close()
+		return
+	}
+
+	wallets.list[name] = wallet
+	return
+}
+
+func (wallets *Wallets) CreateWallet(name string) (*Wallet, error) {
+
+	wallets.Lock.Lock()
+	defer wallets.Lock.Unlock()
+
+	if !walletNameRegexp.MatchString(name) {
+		return nil, errors.New("Wallet name is invalid. Only letters, digits, '-' and '_' are allowed")
+	}
+	if wallets.exists(name) {
+		return nil, errors.New("Wallet already exists")
+	}
+
+	wallet, err := wallets.openWallet(name, "")
+	if err != nil {
+		return nil, err
+	}
+
+	wallets.names = append(wallets.names, name)
+	if err = wallets.saveWalletsNames(); err != nil {
+		wallets.names = wallets.names[:len(wallets.names)-1]
+		wallets.closeWallet(name, wallet)
+		return nil, err
+	}
+
+	globals.MainEvents.BroadcastEvent("wallets/created", name)
+	return wallet, nil
+}
+
+func (wallets *Wallets) OpenWallet(name, password string) (*Wallet, error) {
+
+	wallets.Lock.Lock()
+	defer wallets.Lock.Unlock()
+
+	if !wallets.exists(name) {
+		return nil, errors.New("Wallet doesn't exist")
+	}
+
+	if wallet := wallets.list[name]; wallet != nil {
+		wallet.Lock.RLock()
+		loaded := wallet.Loaded
+		wallet.Lock.RUnlock()
+
+		if password != "" && !loaded {
+			if err := wallet.Encryption.Decrypt(password); err != nil {
+				return nil, err
+			}
+		}
+		return wallet, nil
+	}
+
+	wallet, err := wallets.openWallet(name, password)
+	if err != nil {
+		return nil, err
+	}
+
+	globals.MainEvents.BroadcastEvent("wallets/opened", name)
+	return wallet, nil
+}
+
+func (wallets *Wallets) CloseWallet(name string) error {
+
+	wallets.Lock.Lock()
+	defer wallets.Lock.Unlock()
+
+	if name == DEFAULT_WALLET_NAME {
+		return errors.New("The default wallet can not be closed")
+	}
+
+	wallet := wallets.list[name]
+	if wallet == nil {
+		return errors.New("Wallet is not opened")
+	}
+
+	if wallets.selected == wallet {
+		wallets.selectWallet(wallets.defaultWallet)
+	}
+
+	err := wallets.closeWallet(name, wallet)
+
+	globals.MainEvents.BroadcastEvent("wallets/closed", name)
+	return err
+}
+
+//must be locked before
+func (wallets *Wallets) isPublicKeyHeld(publicKey []byte) bool {
+	for _, wallet := range wallets.list {
+		wallet.Lock.RLock()
+		held := wallet.addressesMap[string(publicKey)] != nil
+		wallet.Lock.RUnlock()
+		if held {
+			return true
+		}
+	}
+	return false
+}
+
+//closeWallet stops forging only the keys which are not held by another opened wallet. must be locked before
+func (wallets *Wallets) closeWallet(name string, wallet *Wallet) error {
+
+	delete(wallets.list, name)
+
+	wallet.Lock.Lock()
+	addresses := wallet.Addresses
+	wallet.clearWallet()
+	wallet.Lock.Unlock()
+
+	for _, addr := range addresses {
+		if !wallets.isPublicKeyHeld(addr.PublicKey) {
+			wallet.forging.Wallet.RemoveWallet(addr.PublicKey, false, nil, nil, 0)
+		}
+	}
+
+	wallet.Close()
+	return wallet.store.Close()
+}
+
+//must be locked before
+func (wallets *Wallets) selectWallet(wallet *Wallet) {
+
+	if wallets.selected == wallet {
+		return
+	}
+
+	wallets.selected.Lock.Lock()
+	wallets.selected.setCLIEnabled(false)
+	wallets.selected.Lock.Unlock()
+
+	wallet.Lock.Lock()
+	wallet.setCLIEnabled(true)
+	wallet.updateWallet()
+	wallet.Lock.Unlock()
+
+	wallets.selected = wallet
+}
+
+func (wallets *Wallets) SelectWallet(name string) error {
+
+	wallets.Lock.Lock()
+	defer wallets.Lock.Unlock()
+
+	wallet := wallets.list[name]
+	if wallet == nil {
+		return errors.New("Wallet is not opened")
+	}
+
+	wallets.selectWallet(wallet)
+
+	globals.MainEvents.BroadcastEvent("wallets/selected", name)
+	return nil
+}
+
+//GetWallet returns the opened wallet with the given name. An empty name returns the default wallet
+func (wallets *Wallets) GetWallet(name string) (*Wallet, error) {
+
+	wallets.Lock.RLock()
+	defer wallets.Lock.RUnlock()
+
+	wallet := wallets.defaultWallet
+	if name != "" {
+		if wallet = wallets.list[name]; wallet == nil {
+			return nil, errors.New("Wallet is not opened")
+		}
+	}
+
+	wallet.KeepAlive()
+	return wallet, nil
+}
+
+//GetOpenedWallets returns the opened wallets without keeping them alive
+func (wallets *Wallets) GetOpenedWallets() map[string]*Wallet {
+
+	wallets.Lock.RLock()
+	defer wallets.Lock.RUnlock()
+
+	list := make(map[string]*Wallet, len(wallets.list))
+	for name, wallet := range wallets.list {
+		list[name] = wallet
+	}
+	return list
+}
+
+func (wallets *Wallets) GetSelectedWallet() *Wallet {
+	wallets.Lock.RLock()
+	defer wallets.Lock.RUnlock()
+	return wallets.selected
+}
+
+func (wallets *Wallets) GetDefaultWallet() *Wallet {
+	return wallets.defaultWallet
+}
+
+//GetWalletAddressByEncodedAddress looks for the address in the given wallet. An empty name will search the default wallet first and afterwards all the opened wallets
+func (wallets *Wallets) GetWalletAddressByEncodedAddress(name, addressEncoded string) (*Wallet, *wallet_address.WalletAddress, error) {
+
+	if name != "" {
+		wallet, err := wallets.GetWallet(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		addr, err := wallet.GetWalletAddressByEncodedAddress(addressEncoded, true)
+		if err != nil {
+			return nil, nil, err
+		}
+		return wallet, addr, nil
+	}
+
+	wallets.Lock.RLock()
+	list := make([]*Wallet, 0, len(wallets.list))
+	list = append(list, wallets.defaultWallet)
+	for _, wallet := range wallets.list {
+		if wallet != wallets.defaultWallet {
+			list = append(list, wallet)
+		}
+	}
+	wallets.Lock.RUnlock()
+
+	var err error
+	for _, wallet := range list {
+		var addr *wallet_address.WalletAddress
+		if addr, err = wallet.GetWalletAddressByEncodedAddress(addressEncoded, true); err == nil {
+			return wallet, addr, nil
+		}
+	}
+
+	return nil, nil, err
+}
+
+func (wallets *Wallets) GetWalletsInfo() []*WalletInfo {
+
+	wallets.Lock.RLock()
+	defer wallets.Lock.RUnlock()
+
+	list := make([]*WalletInfo, len(wallets.names))
+	for i, name := range wallets.names {
+
+		list[i] = &WalletInfo{Name: name}
+
+		if wallet := wallets.list[name]; wallet != nil {
+			wallet.Lock.RLock()
+			list[i].Opened = true
+			list[i].Loaded = wallet.Loaded
+			list[i].Selected = wallet == wallets.selected
+			list[i].Encrypted = wallet.Encryption.Encrypted
+			list[i].Count = wallet.Count
+			wallet.Lock.RUnlock()
+		}
+	}
+
+	return list
+}
+
+func (wallets *Wallets) Close() {
+
+	wallets.Lock.Lock()
+	defer wallets.Lock.Unlock()
+
+	for name, wallet := range wallets.list {
+		wallet.Close()
+		if name != DEFAULT_WALLET_NAME {
+			wallet.store.Close()
+		}
+	}
+}
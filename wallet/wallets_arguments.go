@@ -0,0 +1,43 @@
+package wallet
+
+import (
+	"os"
+	"pandora-pay/config/arguments"
+	"strings"
+)
+
+func (wallets *Wallets) ProcessWalletsArguments() (err error) {
+
+	if str := arguments.Arguments["--wallet-open"]; str != nil {
+		name := str.(string)
+
+		//the password is read from a file to not expose it in the process arguments
+		password := ""
+		if path := arguments.Arguments["--wallet-open-password-file"]; path != nil {
+			var data []byte
+			if data, err = os.ReadFile(path.(string)); err != nil {
+				return
+			}
+			password = strings.TrimRight(string(data), "\r\n")
+		}
+
+		wallets.Lock.RLock()
+		exists := wallets.exists(name)
+		wallets.Lock.RUnlock()
+
+		if exists {
+			_, err = wallets.OpenWallet(name, password)
+		} else {
+			_, err = wallets.CreateWallet(name)
+		}
+		if err != nil {
+			return
+		}
+
+		if err = wallets.SelectWallet(name); err != nil {
+			return
+		}
+	}
+
+	return
+}
@@ -0,0 +1,93 @@
+package wallet
+
+import (
+	"context"
+	"fmt"
+	"pandora-pay/gui"
+)
+
+func (wallets *Wallets) CliListWallets(cmd string, ctx context.Context) (err error) {
+
+	gui.GUI.OutputWrite("Wallets")
+
+	for i, info := range wallets.GetWalletsInfo() {
+
+		if !info.Opened {
+			gui.GUI.OutputWrite(fmt.Sprintf("%d) %s :: %s", i, info.Name, "CLOSED"))
+			continue
+		}
+
+		selected := ""
+		if info.Selected {
+			selected = " [SELECTED]"
+		}
+
+		if !info.Loaded {
+			gui.GUI.OutputWrite(fmt.Sprintf("%d) %s :: %s%s", i, info.Name, "LOCKED", selected))
+			continue
+		}
+
+		gui.GUI.OutputWrite(fmt.Sprintf("%d) %s :: %d addresses %s%s", i, info.Name, info.Count, info.Encrypted, selected))
+	}
+
+	return
+}
+
+func (wallets *Wallets) initWalletsCLI() {
+
+	cliCreateWallet := func(cmd string, ctx context.Context) (err error) {
+
+		name := gui.GUI.OutputReadString("Name of the new wallet")
+
+		if _, err = wallets.CreateWallet(name); err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite("Wallet created: " + name)
+		return wallets.CliListWallets(cmd, ctx)
+	}
+
+	cliOpenWallet := func(cmd string, ctx context.Context) (err error) {
+
+		name := gui.GUI.OutputReadString("Name of the wallet to be opened")
+		password := gui.GUI.OutputReadString("Password for decrypting wallet. Leave empty if it is not encrypted")
+
+		if _, err = wallets.OpenWallet(name, password); err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite("Wallet opened: " + name)
+		return wallets.CliListWallets(cmd, ctx)
+	}
+
+	cliCloseWallet := func(cmd string, ctx context.Context) (err error) {
+
+		name := gui.GUI.OutputReadString("Name of the wallet to be closed")
+
+		if err = wallets.CloseWallet(name); err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite("Wallet closed: " + name)
+		return wallets.CliListWallets(cmd, ctx)
+	}
+
+	cliSelectWallet := func(cmd string, ctx context.Context) (err error) {
+
+		name := gui.GUI.OutputReadString("Name of the wallet to be selected")
+
+		if err = wallets.SelectWallet(name); err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite("Wallet selected: " + name)
+		return
+	}
+
+	gui.GUI.CommandDefineCallback("List Wallets", wallets.CliListWallets, true)
+	gui.GUI.CommandDefineCallback("Create Wallet", cliCreateWallet, true)
+	gui.GUI.CommandDefineCallback("Open Wallet", cliOpenWallet, true)
+	gui.GUI.CommandDefineCallback("Close Wallet", cliCloseWallet, true)
+	gui.GUI.CommandDefineCallback("Select Wallet", cliSelectWallet, true)
+
+}
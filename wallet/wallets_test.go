@@ -0,0 +1,40 @@
+package wallet
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestWalletsCloseSharedKey(t *testing.T) {
+
+	defaultWallet := createTestWallet(t)
+	other := createTestWallet(t)
+
+	//the other wallet holds a key of the default wallet too
+	shared := defaultWallet.Addresses[0].Clone()
+	assert.Nil(t, other.AddAddress(shared, false, false, true, false, true, true))
+	own := other.Addresses[0].PublicKey
+
+	wallets := &Wallets{
+		names:         []string{DEFAULT_WALLET_NAME, "other"},
+		list:          map[string]*Wallet{DEFAULT_WALLET_NAME: defaultWallet, "other": other},
+		defaultWallet: defaultWallet,
+		selected:      defaultWallet,
+		forging:       defaultWallet.forging,
+	}
+
+	assert.True(t, wallets.isPublicKeyHeld(own))
+
+	wallets.Lock.Lock()
+	err := wallets.closeWallet("other", other)
+	wallets.Lock.Unlock()
+	assert.Nil(t, err)
+
+	assert.Nil(t, wallets.list["other"])
+	assert.False(t, wallets.isPublicKeyHeld(own))
+	assert.True(t, wallets.isPublicKeyHeld(shared.PublicKey), "The key held by the default wallet must keep forging")
+
+	wallet, err := wallets.GetWallet("")
+	assert.Nil(t, err)
+	assert.Equal(t, defaultWallet, wallet)
+}
@@ -0,0 +1,348 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"pandora-pay/blockchain/blockchain_types"
+	"pandora-pay/blockchain/transactions/transaction/transaction_type"
+	"pandora-pay/gui"
+	"pandora-pay/helpers"
+	"pandora-pay/helpers/multicast"
+	"pandora-pay/helpers/recovery"
+	"pandora-pay/network/network_config/network_config_auth"
+	"pandora-pay/wallet"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	WEBHOOKS_MAX_ATTEMPTS          = 15
+	WEBHOOKS_RETRY_INITIAL         = 5 * time.Second
+	WEBHOOKS_RETRY_MAX             = 1 * time.Hour
+	WEBHOOKS_OUTBOX_MAX            = 10000
+	WEBHOOKS_TIMEOUT               = 10 * time.Second
+	WEBHOOKS_WORKERS               = 8               //users delivered in parallel
+	WEBHOOKS_PASS_MAX              = 1 * time.Minute //time spent delivering to a user in a pass
+	WEBHOOKS_DEFAULT_CONFIRMATIONS = uint64(6)
+)
+
+type webhooksType struct {
+	wallets  *wallet.Wallets
+	outbox   []*webhookDelivery
+	pending  []*webhookPendingConfirmation
+	balances map[string]uint64 //last amount sent, ring members change their encrypted balances without changing the amount
+	client   *http.Client
+	notifyCn chan struct{}
+	lock     sync.Mutex
+}
+
+//walletEvent is computed once per wallet and then delivered to all the users watching the wallet
+type walletEvent struct {
+	eventType WebhookEventType
+	data      any
+	tx        *webhookPendingConfirmation
+}
+
+var Webhooks *webhooksType
+
+func getWebhooksUsers() []*network_config_auth.ConfigAuth {
+	list := make([]*network_config_auth.ConfigAuth, 0)
+	for _, user := range network_config_auth.GetUsers() {
+		if user.Webhook != nil && user.Webhook.URL != "" {
+			list = append(list, user)
+		}
+	}
+	return list
+}
+
+func isWalletWatched(user *network_config_auth.ConfigAuth, name string) bool {
+	if len(user.Webhook.Wallets) == 0 {
+		return true
+	}
+	for _, it := range user.Webhook.Wallets {
+		if it == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (this *webhooksType) addDelivery(user string, eventType WebhookEventType, data any) error {
+
+	event := &WebhookEvent{
+		hex.EncodeToString(helpers.RandomBytes(16)),
+		eventType,
+		time.Now().Unix(),
+		data,
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	if len(this.outbox) >= WEBHOOKS_OUTBOX_MAX {
+		gui.GUI.Warning("Webhooks outbox is full. Dropping delivery", this.outbox[0].ID)
+		this.outbox = this.outbox[1:]
+	}
+
+	this.outbox = append(this.outbox, &webhookDelivery{event.ID, user, eventType, body, 0, 0})
+	return nil
+}
+
+func (this *webhooksType) getWalletEvents(name string, w *wallet.Wallet, update *blockchain_types.BlockchainUpdates) []*walletEvent {
+
+	events := make([]*walletEvent, 0)
+
+	for _, accs := range update.AccsCollection.GetAllMaps() {
+		for key, v := range accs.HashMap.Committed {
+
+			if v.Element == nil {
+				continue
+			}
+
+			addr := w.GetWalletAddressByPublicKey([]byte(key), true)
+			if addr == nil {
+				continue
+			}
+
+			balance := v.Element.Balance.Amount.Serialize()
+			amount, err := w.DecryptBalance(addr, balance, accs.Asset, false, 0, true, context.Background(), func(string) {})
+			if err != nil {
+				gui.GUI.Error("Webhooks error decrypting balance", addr.AddressEncoded, err)
+				continue
+			}
+
+			balanceKey := name + ":" + key + ":" + string(accs.Asset)
+			if last, ok := this.balances[balanceKey]; ok && last == amount {
+				continue
+			}
+			this.balances[balanceKey] = amount
+
+			events = append(events, &walletEvent{WEBHOOK_EVENT_BALANCE, &WebhookBalanceData{
+				name, addr.AddressEncoded, accs.Asset, balance, amount, update.BlockHeight,
+			}, nil})
+		}
+	}
+
+	for _, blkComplete := range update.InsertedBlocks {
+		for _, tx := range blkComplete.Txs {
+
+			if tx.Version != transaction_type.TX_ZETHER {
+				continue
+			}
+
+			for key := range tx.GetAllKeys() {
+
+				addr := w.GetWalletAddressByPublicKey([]byte(key), true)
+				if addr == nil {
+					continue
+				}
+
+				decrypted, err := w.DecryptTx(tx, addr.PublicKey)
+				if err != nil || decrypted.ZetherTx == nil {
+					continue
+				}
+
+				involved := false
+				payloads := make([]*WebhookPaymentPayload, 0)
+				for _, payload := range decrypted.ZetherTx.Payloads {
+					if payload == nil {
+						continue
+					}
+					if payload.WhisperSenderValid {
+						involved = true
+					}
+					if payload.WhisperRecipientValid && payload.ReceivedAmount > 0 {
+						involved = true
+						payloads = append(payloads, &WebhookPaymentPayload{payload.Asset, payload.ReceivedAmount, payload.Message})
+					}
+				}
+
+				if !involved {
+					continue
+				}
+
+				tracked := &webhookPendingConfirmation{"", name, addr.AddressEncoded, tx.Bloom.Hash, blkComplete.Block.Height, 0}
+				if len(payloads) > 0 {
+					events = append(events, &walletEvent{WEBHOOK_EVENT_PAYMENT, &WebhookPaymentData{
+						name, addr.AddressEncoded, tx.Bloom.Hash, blkComplete.Block.Height, payloads,
+					}, tracked})
+				} else {
+					events = append(events, &walletEvent{"", nil, tracked})
+				}
+			}
+		}
+	}
+
+	return events
+}
+
+func (this *webhooksType) processChainUpdate(update *blockchain_types.BlockchainUpdates) (err error) {
+
+	users := getWebhooksUsers()
+
+	//decrypting is done without locking the outbox
+	walletsEvents := make(map[string][]*walletEvent)
+	if len(users) > 0 {
+		for name, w := range this.wallets.GetOpenedWallets() {
+
+			w.Lock.RLock()
+			loaded := w.Loaded
+			w.Lock.RUnlock()
+			if !loaded {
+				continue
+			}
+
+			for _, user := range users {
+				if isWalletWatched(user, name) {
+					walletsEvents[name] = this.getWalletEvents(name, w, update)
+					break
+				}
+			}
+		}
+	}
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	return this.addEvents(update, users, walletsEvents)
+}
+
+//addEvents adds the deliveries of the chain update and of the wallets events to the outbox. It is called with the lock acquired
+func (this *webhooksType) addEvents(update *blockchain_types.BlockchainUpdates, users []*network_config_auth.ConfigAuth, walletsEvents map[string][]*walletEvent) (err error) {
+
+	changed := false
+
+	if len(update.RemovedHeights) > 0 {
+
+		//txs included in the removed blocks need to be confirmed again
+		pending := make([]*webhookPendingConfirmation, 0, len(this.pending))
+		for _, it := range this.pending {
+			if it.BlockHeight < update.RemovedHeights[0] {
+				pending = append(pending, it)
+			}
+		}
+		changed = len(pending) != len(this.pending)
+		this.pending = pending
+
+		addedHeights := make([]uint64, len(update.InsertedBlocks))
+		for i, blkComplete := range update.InsertedBlocks {
+			addedHeights[i] = blkComplete.Block.Height
+		}
+
+		for _, user := range users {
+			if err = this.addDelivery(user.Username, WEBHOOK_EVENT_REORG, &WebhookReorgData{update.RemovedHeights, addedHeights, update.BlockHeight, update.BlockHash}); err != nil {
+				return
+			}
+			changed = true
+		}
+	}
+
+	for _, user := range users {
+
+		confirmations := user.Webhook.Confirmations
+		if confirmations == 0 {
+			confirmations = WEBHOOKS_DEFAULT_CONFIRMATIONS
+		}
+
+		for name, events := range walletsEvents {
+			if !isWalletWatched(user, name) {
+				continue
+			}
+
+			for _, event := range events {
+				if event.data != nil {
+					if err = this.addDelivery(user.Username, event.eventType, event.data); err != nil {
+						return
+					}
+					changed = true
+				}
+				if event.tx != nil && !this.isPending(user.Username, event.tx) {
+					tx := *event.tx
+					tx.User = user.Username
+					tx.Confirmations = confirmations
+					this.pending = append(this.pending, &tx)
+					changed = true
+				}
+			}
+		}
+	}
+
+	//BlockHeight is the number of blocks, a tx included in the last block has 1 confirmation
+	pending := make([]*webhookPendingConfirmation, 0, len(this.pending))
+	for _, it := range this.pending {
+		if update.BlockHeight >= it.BlockHeight+it.Confirmations {
+			if network_config_auth.GetUser(it.User) != nil {
+				if err = this.addDelivery(it.User, WEBHOOK_EVENT_CONFIRMATION, &WebhookConfirmationData{
+					it.Wallet, it.Address, it.TxHash, it.BlockHeight, update.BlockHeight - it.BlockHeight,
+				}); err != nil {
+					return
+				}
+			}
+			changed = true
+			continue
+		}
+		pending = append(pending, it)
+	}
+	this.pending = pending
+
+	if changed {
+		if err = this.save(); err != nil {
+			return
+		}
+		this.notify()
+	}
+
+	return
+}
+
+func (this *webhooksType) isPending(user string, tx *webhookPendingConfirmation) bool {
+	for _, it := range this.pending {
+		if it.User == user && it.Address == tx.Address && string(it.TxHash) == string(tx.TxHash) {
+			return true
+		}
+	}
+	return false
+}
+
+func (this *webhooksType) processChainUpdates(updateNewChainUpdate *multicast.MulticastChannel[*blockchain_types.BlockchainUpdates]) {
+
+	updateNewChainUpdateCn := updateNewChainUpdate.AddListener()
+	defer updateNewChainUpdate.RemoveChannel(updateNewChainUpdateCn)
+
+	for {
+		update, ok := <-updateNewChainUpdateCn
+		if !ok {
+			return
+		}
+
+		if err := this.processChainUpdate(update); err != nil {
+			gui.GUI.Error("Webhooks error processing chain update "+strconv.FormatUint(update.BlockHeight, 10), err)
+		}
+	}
+}
+
+func InitWebhooks(wallets *wallet.Wallets, updateNewChainUpdate *multicast.MulticastChannel[*blockchain_types.BlockchainUpdates]) error {
+
+	Webhooks = &webhooksType{
+		wallets:  wallets,
+		balances: make(map[string]uint64),
+		client:   &http.Client{Timeout: WEBHOOKS_TIMEOUT},
+		notifyCn: make(chan struct{}, 1),
+	}
+
+	if err := Webhooks.load(); err != nil {
+		return err
+	}
+
+	recovery.SafeGo(func() {
+		Webhooks.processChainUpdates(updateNewChainUpdate)
+	})
+	recovery.SafeGo(Webhooks.processOutbox)
+
+	return nil
+}
@@ -0,0 +1,216 @@
+package webhooks
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"pandora-pay/gui"
+	"pandora-pay/helpers/msgpack"
+	"pandora-pay/helpers/recovery"
+	"pandora-pay/network/network_config/network_config_auth"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"strconv"
+	"sync"
+	"time"
+)
+
+//the outbox and the pending confirmations are stored in the settings store to survive restarts
+func (this *webhooksType) save() error {
+	return store.StoreSettings.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+
+		marshal, err := msgpack.Marshal(&webhooksStored{this.outbox, this.pending})
+		if err != nil {
+			return err
+		}
+
+		writer.Put("webhooks", marshal)
+		return nil
+	})
+}
+
+func (this *webhooksType) load() error {
+	return store.StoreSettings.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+
+		data := reader.Get("webhooks")
+		if data == nil {
+			return nil
+		}
+
+		stored := &webhooksStored{}
+		if err := msgpack.Unmarshal(data, stored); err != nil {
+			return err
+		}
+
+		this.outbox = stored.Outbox
+		this.pending = stored.Pending
+		if len(this.outbox) > 0 {
+			gui.GUI.Log("Webhooks outbox loaded " + strconv.Itoa(len(this.outbox)))
+		}
+
+		return nil
+	})
+}
+
+func (this *webhooksType) notify() {
+	select {
+	case this.notifyCn <- struct{}{}:
+	default:
+	}
+}
+
+//getRetryDelay doubles the delay after every failed attempt
+func getRetryDelay(attempts int) time.Duration {
+	delay := WEBHOOKS_RETRY_INITIAL
+	for i := 1; i < attempts && delay < WEBHOOKS_RETRY_MAX; i++ {
+		delay *= 2
+	}
+	if delay > WEBHOOKS_RETRY_MAX {
+		delay = WEBHOOKS_RETRY_MAX
+	}
+	return delay
+}
+
+//deliver returns retry false when the delivery can't be done anymore
+func (this *webhooksType) deliver(delivery *webhookDelivery) (retry bool, err error) {
+
+	user := network_config_auth.GetUser(delivery.User)
+	if user == nil || user.Webhook == nil || user.Webhook.URL == "" {
+		return false, errors.New("User doesn't have a webhook anymore")
+	}
+
+	req, err := http.NewRequest(http.MethodPost, user.Webhook.URL, bytes.NewReader(delivery.Body))
+	if err != nil {
+		return false, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HEADER_USER, delivery.User)
+	req.Header.Set(HEADER_EVENT, string(delivery.Event))
+	req.Header.Set(HEADER_DELIVERY, delivery.ID)
+	req.Header.Set(HEADER_TIMESTAMP, timestamp)
+	req.Header.Set(HEADER_SIGNATURE, Sign(user.Webhook.Secret, timestamp, delivery.Body))
+
+	resp, err := this.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return true, errors.New("Webhook answered with status " + strconv.Itoa(resp.StatusCode))
+	}
+
+	return false, nil
+}
+
+func (this *webhooksType) removeDelivery(delivery *webhookDelivery) {
+	for i, it := range this.outbox {
+		if it == delivery {
+			this.outbox = append(this.outbox[:i], this.outbox[i+1:]...)
+			return
+		}
+	}
+}
+
+//processDelivery returns false when the delivery failed
+func (this *webhooksType) processDelivery(delivery *webhookDelivery) bool {
+
+	retry, err := this.deliver(delivery)
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	if err == nil {
+		this.removeDelivery(delivery)
+		return true
+	}
+
+	delivery.Attempts += 1
+	if !retry || delivery.Attempts >= WEBHOOKS_MAX_ATTEMPTS {
+		gui.GUI.Error("Webhook delivery "+delivery.ID+" to "+delivery.User+" dropped", err)
+		this.removeDelivery(delivery)
+	} else {
+		gui.GUI.Warning("Webhook delivery "+delivery.ID+" to "+delivery.User+" failed", err)
+		delivery.NextAttempt = time.Now().Add(getRetryDelay(delivery.Attempts)).UnixMilli()
+	}
+	return false
+}
+
+//processDueDeliveries delivers the due deliveries of every user in order and the users in parallel. A failed delivery or WEBHOOKS_PASS_MAX stop the deliveries of the user until the next pass, so a dead endpoint delays only its own user
+//It returns how long to wait until the next delivery is due
+func (this *webhooksType) processDueDeliveries() time.Duration {
+
+	this.lock.Lock()
+	now := time.Now().UnixMilli()
+	users := make([]string, 0)
+	due := make(map[string][]*webhookDelivery)
+	for _, delivery := range this.outbox {
+		if delivery.NextAttempt <= now {
+			if due[delivery.User] == nil {
+				users = append(users, delivery.User)
+			}
+			due[delivery.User] = append(due[delivery.User], delivery)
+		}
+	}
+	this.lock.Unlock()
+
+	wg := sync.WaitGroup{}
+	workers := make(chan struct{}, WEBHOOKS_WORKERS)
+	for _, user := range users {
+
+		deliveries := due[user]
+
+		wg.Add(1)
+		workers <- struct{}{}
+		recovery.SafeGo(func() {
+			defer func() {
+				<-workers
+				wg.Done()
+			}()
+
+			deadline := time.Now().Add(WEBHOOKS_PASS_MAX)
+			for _, delivery := range deliveries {
+				if !this.processDelivery(delivery) || time.Now().After(deadline) {
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	if len(users) > 0 {
+		if err := this.save(); err != nil {
+			gui.GUI.Error("Webhooks error saving outbox", err)
+		}
+	}
+
+	wait := WEBHOOKS_RETRY_MAX
+	now = time.Now().UnixMilli()
+	for _, delivery := range this.outbox {
+		if delay := time.Duration(delivery.NextAttempt-now) * time.Millisecond; delay < wait {
+			wait = delay
+		}
+	}
+	if wait < 0 {
+		wait = 0
+	}
+	return wait
+}
+
+func (this *webhooksType) processOutbox() {
+	for {
+		wait := this.processDueDeliveries()
+		select {
+		case <-this.notifyCn:
+		case <-time.After(wait):
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package webhooks
+
+import (
+	"io"
+	"net/http"
+	"pandora-pay/gui"
+	"pandora-pay/network/network_config/network_config_auth"
+	"strconv"
+	"time"
+)
+
+const WEBHOOKS_TEST_STUB_MAX_AGE = 5 * 60 //seconds
+
+//TestStubHandler is a local receiver used to test the webhooks. It verifies the signature and logs the deliveries
+func TestStubHandler(w http.ResponseWriter, req *http.Request) {
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, 1<<20))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	user := network_config_auth.GetUser(req.Header.Get(HEADER_USER))
+	if user == nil || user.Webhook == nil {
+		http.Error(w, "User was not found", http.StatusUnauthorized)
+		return
+	}
+
+	timestamp := req.Header.Get(HEADER_TIMESTAMP)
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || time.Now().Unix()-unix > WEBHOOKS_TEST_STUB_MAX_AGE {
+		http.Error(w, "Timestamp is invalid", http.StatusUnauthorized)
+		return
+	}
+
+	if !VerifySignature(user.Webhook.Secret, timestamp, body, req.Header.Get(HEADER_SIGNATURE)) {
+		http.Error(w, "Signature is invalid", http.StatusUnauthorized)
+		return
+	}
+
+	gui.GUI.Info("Webhook test stub received", req.Header.Get(HEADER_EVENT), req.Header.Get(HEADER_DELIVERY), string(body))
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,308 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"pandora-pay/blockchain/blockchain_types"
+	"pandora-pay/config/arguments"
+	"pandora-pay/gui"
+	"pandora-pay/gui/gui_non_interactive"
+	"pandora-pay/network/network_config/network_config_auth"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_memory"
+	"pandora-pay/wallet"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSignature(t *testing.T) {
+
+	body := []byte(`{"id":"1","type":"reorg"}`)
+
+	signature := Sign("secret", "1700000000", body)
+	assert.Equal(t, "sha256=", signature[:7])
+	assert.True(t, VerifySignature("secret", "1700000000", body, signature))
+
+	assert.False(t, VerifySignature("secret2", "1700000000", body, signature))
+	assert.False(t, VerifySignature("secret", "1700000001", body, signature))
+	assert.False(t, VerifySignature("secret", "1700000000", []byte(`{"id":"2","type":"reorg"}`), signature))
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, WEBHOOKS_RETRY_INITIAL, getRetryDelay(1))
+	assert.Equal(t, 2*WEBHOOKS_RETRY_INITIAL, getRetryDelay(2))
+	assert.Equal(t, 8*WEBHOOKS_RETRY_INITIAL, getRetryDelay(4))
+	assert.Equal(t, WEBHOOKS_RETRY_MAX, getRetryDelay(WEBHOOKS_MAX_ATTEMPTS))
+	assert.True(t, getRetryDelay(WEBHOOKS_MAX_ATTEMPTS-1) <= time.Hour)
+}
+
+func TestWebhookSecretRequired(t *testing.T) {
+
+	assert.Nil(t, arguments.InitArguments([]string{`--auth-users=[{"user":"user","pass":"pass","webhook":{"url":"http://127.0.0.1/hook"}}]`}))
+	assert.NotNil(t, network_config_auth.InitConfig(), "Empty webhook secret should be rejected")
+
+	assert.Nil(t, arguments.InitArguments([]string{`--auth-users=[{"user":"user","pass":"pass","webhook":{"url":"http://127.0.0.1/hook","secret":"secret"}}]`}))
+	assert.Nil(t, network_config_auth.InitConfig())
+	assert.Equal(t, "secret", network_config_auth.GetUser("user").Webhook.Secret)
+}
+
+//createTestWebhooks uses a memory settings store. The wallets are not opened, so only the pending confirmations and the reorgs create events
+func createTestWebhooks(t *testing.T, url string) *webhooksType {
+	return createTestWebhooksUsers(t, `[{"user":"user","pass":"pass","webhook":{"url":"`+url+`","secret":"secret","confirmations":3}}]`)
+}
+
+func createTestWebhooksUsers(t *testing.T, users string) *webhooksType {
+
+	gui.GUI, _ = gui_non_interactive.CreateGUINonInteractive(nil)
+
+	settings, _ := store_db_memory.CreateStoreDBMemory("settings")
+	store.StoreSettings = &store.Store{Name: "settings", Opened: true, DB: settings}
+
+	assert.Nil(t, arguments.InitArguments([]string{"--auth-users=" + users}))
+	assert.Nil(t, network_config_auth.InitConfig())
+
+	return newTestWebhooks(t)
+}
+
+//newTestWebhooks loads the outbox stored, like after a restart
+func newTestWebhooks(t *testing.T) *webhooksType {
+	webhooks := &webhooksType{
+		wallets:  &wallet.Wallets{},
+		balances: make(map[string]uint64),
+		client:   &http.Client{Timeout: WEBHOOKS_TIMEOUT},
+		notifyCn: make(chan struct{}, 1),
+	}
+	assert.Nil(t, webhooks.load())
+	return webhooks
+}
+
+func getTestEvent[T any](t *testing.T, delivery *webhookDelivery) *T {
+	event := &struct {
+		Type WebhookEventType `json:"type"`
+		Data *T               `json:"data"`
+	}{}
+	assert.Nil(t, json.Unmarshal(delivery.Body, event))
+	assert.Equal(t, delivery.Event, event.Type)
+	return event.Data
+}
+
+func TestWebhooksConfirmations(t *testing.T) {
+
+	webhooks := createTestWebhooks(t, "http://127.0.0.1/hook")
+	webhooks.pending = []*webhookPendingConfirmation{{"user", "wallet", "address", []byte("tx"), 10, 3}}
+
+	//the tx included in the block 10 has 1 confirmation at height 11
+	assert.Nil(t, webhooks.processChainUpdate(&blockchain_types.BlockchainUpdates{BlockHeight: 11}))
+	assert.Nil(t, webhooks.processChainUpdate(&blockchain_types.BlockchainUpdates{BlockHeight: 12}))
+	assert.Equal(t, 0, len(webhooks.outbox))
+	assert.Equal(t, 1, len(webhooks.pending))
+
+	assert.Nil(t, webhooks.processChainUpdate(&blockchain_types.BlockchainUpdates{BlockHeight: 13}))
+	assert.Equal(t, 0, len(webhooks.pending))
+	assert.Equal(t, 1, len(webhooks.outbox))
+	assert.Equal(t, WEBHOOK_EVENT_CONFIRMATION, webhooks.outbox[0].Event)
+
+	data := getTestEvent[WebhookConfirmationData](t, webhooks.outbox[0])
+	assert.Equal(t, []byte("tx"), data.TxHash)
+	assert.Equal(t, uint64(10), data.BlockHeight)
+	assert.Equal(t, uint64(3), data.Confirmations)
+
+	//the confirmation is delivered only once
+	assert.Nil(t, webhooks.processChainUpdate(&blockchain_types.BlockchainUpdates{BlockHeight: 14}))
+	assert.Equal(t, 1, len(webhooks.outbox))
+}
+
+func TestWebhooksReorg(t *testing.T) {
+
+	webhooks := createTestWebhooks(t, "http://127.0.0.1/hook")
+	webhooks.pending = []*webhookPendingConfirmation{
+		{"user", "wallet", "address", []byte("tx1"), 10, 3},
+		{"user", "wallet", "address", []byte("tx2"), 8, 3},
+	}
+
+	//the blocks 10 and 11 are replaced by a single block
+	assert.Nil(t, webhooks.processChainUpdate(&blockchain_types.BlockchainUpdates{BlockHeight: 10, BlockHash: []byte("hash"), RemovedHeights: []uint64{10, 11}}))
+
+	assert.Equal(t, 1, len(webhooks.pending))
+	assert.Equal(t, []byte("tx2"), webhooks.pending[0].TxHash)
+
+	assert.Equal(t, 1, len(webhooks.outbox))
+	assert.Equal(t, WEBHOOK_EVENT_REORG, webhooks.outbox[0].Event)
+
+	data := getTestEvent[WebhookReorgData](t, webhooks.outbox[0])
+	assert.Equal(t, []uint64{10, 11}, data.RemovedHeights)
+	assert.Equal(t, uint64(10), data.Height)
+	assert.Equal(t, []byte("hash"), data.Hash)
+
+	//the tx kept is still confirmed
+	assert.Nil(t, webhooks.processChainUpdate(&blockchain_types.BlockchainUpdates{BlockHeight: 11}))
+	assert.Equal(t, 0, len(webhooks.pending))
+	assert.Equal(t, WEBHOOK_EVENT_CONFIRMATION, webhooks.outbox[1].Event)
+}
+
+func TestWebhooksOutboxReplay(t *testing.T) {
+
+	var lock sync.Mutex
+	received := make([]*http.Request, 0)
+	bodies := make([][]byte, 0)
+	status := http.StatusInternalServerError
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		lock.Lock()
+		defer lock.Unlock()
+		received = append(received, req)
+		bodies = append(bodies, body)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	webhooks := createTestWebhooks(t, server.URL)
+	webhooks.pending = []*webhookPendingConfirmation{{"user", "wallet", "address", []byte("tx"), 10, 3}}
+	assert.Nil(t, webhooks.processChainUpdate(&blockchain_types.BlockchainUpdates{BlockHeight: 13}))
+
+	//the failed delivery is kept with its attempts
+	webhooks.processDueDeliveries()
+	assert.Equal(t, 1, len(received))
+	assert.Equal(t, 1, len(webhooks.outbox))
+	assert.Equal(t, 1, webhooks.outbox[0].Attempts)
+
+	//the node restarts
+	restarted := newTestWebhooks(t)
+	assert.Equal(t, 1, len(restarted.outbox))
+	assert.Equal(t, webhooks.outbox[0].ID, restarted.outbox[0].ID)
+	assert.Equal(t, 1, restarted.outbox[0].Attempts)
+	assert.Equal(t, 0, len(restarted.pending))
+
+	lock.Lock()
+	status = http.StatusOK
+	lock.Unlock()
+
+	restarted.outbox[0].NextAttempt = 0
+	restarted.processDueDeliveries()
+
+	assert.Equal(t, 2, len(received))
+	assert.Equal(t, bodies[0], bodies[1], "Replayed delivery should be the same")
+	assert.Equal(t, 0, len(restarted.outbox))
+
+	req := received[1]
+	assert.Equal(t, "user", req.Header.Get(HEADER_USER))
+	assert.Equal(t, string(WEBHOOK_EVENT_CONFIRMATION), req.Header.Get(HEADER_EVENT))
+	assert.True(t, VerifySignature("secret", req.Header.Get(HEADER_TIMESTAMP), bodies[1], req.Header.Get(HEADER_SIGNATURE)))
+
+	//the delivered event is removed from the stored outbox
+	assert.Equal(t, 0, len(newTestWebhooks(t).outbox))
+}
+
+func TestWebhooksWalletEvents(t *testing.T) {
+
+	webhooks := createTestWebhooksUsers(t, `[
+		{"user":"user","pass":"pass","webhook":{"url":"http://127.0.0.1/hook","secret":"secret","confirmations":3}},
+		{"user":"user2","pass":"pass","webhook":{"url":"http://127.0.0.1/hook2","secret":"secret","wallets":["other"]}}
+	]`)
+
+	payment := &webhookPendingConfirmation{"", "wallet", "address", []byte("tx1"), 10, 0}
+	sent := &webhookPendingConfirmation{"", "wallet", "address", []byte("tx2"), 10, 0}
+
+	walletsEvents := map[string][]*walletEvent{
+		"wallet": {
+			{WEBHOOK_EVENT_BALANCE, &WebhookBalanceData{"wallet", "address", []byte{}, []byte("balance"), 100, 11}, nil},
+			{WEBHOOK_EVENT_PAYMENT, &WebhookPaymentData{"wallet", "address", []byte("tx1"), 10, []*WebhookPaymentPayload{{[]byte{}, 100, []byte("message")}}}, payment},
+			{"", nil, sent},
+		},
+	}
+
+	webhooks.lock.Lock()
+	assert.Nil(t, webhooks.addEvents(&blockchain_types.BlockchainUpdates{BlockHeight: 11}, getWebhooksUsers(), walletsEvents))
+	webhooks.lock.Unlock()
+
+	//user2 doesn't watch the wallet
+	assert.Equal(t, 2, len(webhooks.outbox))
+	for _, delivery := range webhooks.outbox {
+		assert.Equal(t, "user", delivery.User)
+	}
+
+	assert.Equal(t, WEBHOOK_EVENT_BALANCE, webhooks.outbox[0].Event)
+	balance := getTestEvent[WebhookBalanceData](t, webhooks.outbox[0])
+	assert.Equal(t, uint64(100), balance.Amount)
+	assert.Equal(t, []byte("balance"), balance.Balance)
+
+	assert.Equal(t, WEBHOOK_EVENT_PAYMENT, webhooks.outbox[1].Event)
+	data := getTestEvent[WebhookPaymentData](t, webhooks.outbox[1])
+	assert.Equal(t, []byte("tx1"), data.TxHash)
+	assert.Equal(t, uint64(100), data.Payloads[0].Amount)
+	assert.Equal(t, []byte("message"), data.Payloads[0].Message)
+
+	//the received and the sent txs wait for the confirmations of the user
+	assert.Equal(t, 2, len(webhooks.pending))
+	for _, it := range webhooks.pending {
+		assert.Equal(t, "user", it.User)
+		assert.Equal(t, uint64(3), it.Confirmations)
+	}
+
+	//the txs detected again are confirmed only once
+	webhooks.lock.Lock()
+	assert.Nil(t, webhooks.addEvents(&blockchain_types.BlockchainUpdates{BlockHeight: 13}, getWebhooksUsers(), map[string][]*walletEvent{"wallet": {{"", nil, payment}, {"", nil, sent}}}))
+	webhooks.lock.Unlock()
+	assert.Equal(t, 4, len(webhooks.outbox))
+	assert.Equal(t, WEBHOOK_EVENT_CONFIRMATION, webhooks.outbox[2].Event)
+	assert.Equal(t, WEBHOOK_EVENT_CONFIRMATION, webhooks.outbox[3].Event)
+	assert.Equal(t, 0, len(webhooks.pending))
+}
+
+func TestWebhooksDeadEndpoint(t *testing.T) {
+
+	var lock sync.Mutex
+	received := map[string]int{}
+
+	done := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		lock.Lock()
+		received[req.URL.Path] += 1
+		lock.Unlock()
+
+		//the dead endpoint never answers
+		if req.URL.Path == "/dead" {
+			select {
+			case <-done:
+			case <-req.Context().Done():
+			}
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	defer close(done)
+
+	webhooks := createTestWebhooksUsers(t, `[
+		{"user":"dead","pass":"pass","webhook":{"url":"`+server.URL+`/dead","secret":"secret"}},
+		{"user":"alive","pass":"pass","webhook":{"url":"`+server.URL+`/alive","secret":"secret"}}
+	]`)
+	webhooks.client = &http.Client{Timeout: 500 * time.Millisecond}
+
+	for i := 0; i < 3; i++ {
+		assert.Nil(t, webhooks.addDelivery("dead", WEBHOOK_EVENT_REORG, &WebhookReorgData{}))
+		assert.Nil(t, webhooks.addDelivery("alive", WEBHOOK_EVENT_REORG, &WebhookReorgData{}))
+	}
+
+	start := time.Now()
+	webhooks.processDueDeliveries()
+	assert.Less(t, time.Since(start), 1500*time.Millisecond, "Dead endpoint delayed the pass")
+
+	lock.Lock()
+	assert.Equal(t, 3, received["/alive"])
+	assert.Equal(t, 1, received["/dead"], "Deliveries of the dead endpoint should stop at the first failure")
+	lock.Unlock()
+
+	//the deliveries of the dead endpoint are kept in order
+	assert.Equal(t, 3, len(webhooks.outbox))
+	assert.Equal(t, 1, webhooks.outbox[0].Attempts)
+	for _, delivery := range webhooks.outbox {
+		assert.Equal(t, "dead", delivery.User)
+	}
+}
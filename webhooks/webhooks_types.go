@@ -0,0 +1,88 @@
+package webhooks
+
+type WebhookEventType string
+
+const (
+	WEBHOOK_EVENT_BALANCE      WebhookEventType = "balance"
+	WEBHOOK_EVENT_PAYMENT      WebhookEventType = "payment"
+	WEBHOOK_EVENT_CONFIRMATION WebhookEventType = "confirmation"
+	WEBHOOK_EVENT_REORG        WebhookEventType = "reorg"
+)
+
+const (
+	HEADER_USER      = "X-PandoraPay-User"
+	HEADER_EVENT     = "X-PandoraPay-Event"
+	HEADER_DELIVERY  = "X-PandoraPay-Delivery"
+	HEADER_TIMESTAMP = "X-PandoraPay-Timestamp"
+	HEADER_SIGNATURE = "X-PandoraPay-Signature"
+)
+
+type WebhookEvent struct {
+	ID        string           `json:"id"`
+	Type      WebhookEventType `json:"type"`
+	Timestamp int64            `json:"timestamp"`
+	Data      any              `json:"data"`
+}
+
+type WebhookBalanceData struct {
+	Wallet  string `json:"wallet"`
+	Address string `json:"address"`
+	Asset   []byte `json:"asset"`
+	Balance []byte `json:"balance"`
+	Amount  uint64 `json:"amount"`
+	Height  uint64 `json:"height"`
+}
+
+type WebhookPaymentPayload struct {
+	Asset   []byte `json:"asset"`
+	Amount  uint64 `json:"amount"`
+	Message []byte `json:"message,omitempty"`
+}
+
+type WebhookPaymentData struct {
+	Wallet      string                   `json:"wallet"`
+	Address     string                   `json:"address"`
+	TxHash      []byte                   `json:"txHash"`
+	BlockHeight uint64                   `json:"blockHeight"`
+	Payloads    []*WebhookPaymentPayload `json:"payloads"`
+}
+
+type WebhookConfirmationData struct {
+	Wallet        string `json:"wallet"`
+	Address       string `json:"address"`
+	TxHash        []byte `json:"txHash"`
+	BlockHeight   uint64 `json:"blockHeight"`
+	Confirmations uint64 `json:"confirmations"`
+}
+
+type WebhookReorgData struct {
+	RemovedHeights []uint64 `json:"removedHeights"`
+	AddedHeights   []uint64 `json:"addedHeights"`
+	Height         uint64   `json:"height"`
+	Hash           []byte   `json:"hash"`
+}
+
+//webhookDelivery is stored in the outbox until the user's endpoint accepts it
+type webhookDelivery struct {
+	ID          string           `msgpack:"id"`
+	User        string           `msgpack:"user"`
+	Event       WebhookEventType `msgpack:"event"`
+	Body        []byte           `msgpack:"body"`
+	Attempts    int              `msgpack:"attempts"`
+	NextAttempt int64            `msgpack:"nextAttempt"` //unix milliseconds
+}
+
+//webhookPendingConfirmation is a tx of a wallet waiting for the user's confirmations
+type webhookPendingConfirmation struct {
+	User          string `msgpack:"user"`
+	Wallet        string `msgpack:"wallet"`
+	Address       string `msgpack:"address"`
+	TxHash        []byte `msgpack:"txHash"`
+	BlockHeight   uint64 `msgpack:"blockHeight"`
+	Confirmations uint64 `msgpack:"confirmations"`
+}
+
+type webhooksStored struct {
+	Outbox  []*webhookDelivery            `msgpack:"outbox"`
+	Pending []*webhookPendingConfirmation `msgpack:"pending"`
+}